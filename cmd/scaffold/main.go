@@ -14,17 +14,19 @@ var (
 )
 
 type Config struct {
-	Name       string
-	Module     string
-	Features   string
-	DBType     string
-	Deployment string
-	OutputDir  string
+	Name          string
+	Module        string
+	Features      string
+	DBType        string
+	Deployment    string
+	DockerProfile string
+	OutputDir     string
 }
 
 func main() {
 	config := Config{}
-	log := logger.New(true)
+	log := logger.New(os.Stderr)
+	log.RegisterFlags(flag.CommandLine)
 
 	// Parse command line flags
 	flag.StringVar(&config.Name, "name", "", "Project name")
@@ -32,6 +34,7 @@ func main() {
 	flag.StringVar(&config.Features, "features", "", "Comma-separated list of features (auth,metrics,tracing)")
 	flag.StringVar(&config.DBType, "db", "postgres", "Database type (postgres)")
 	flag.StringVar(&config.Deployment, "deployment", "docker", "Deployment type (docker,k8s)")
+	flag.StringVar(&config.DockerProfile, "docker-profile", "prod", "Default Docker image (dev,prod)")
 	flag.StringVar(&config.OutputDir, "output", "", "Output directory (default: current directory)")
 	flag.Parse()
 
@@ -42,6 +45,8 @@ func main() {
 		os.Exit(1)
 	}
 
+	log.Debug("Generating %s (%s) with features %q", config.Name, config.Module, config.Features)
+
 	// Set default output directory if not specified
 	if config.OutputDir == "" {
 		currentDir, err := os.Getwd()
@@ -62,6 +67,7 @@ func main() {
 		config.OutputDir,
 		log,
 	)
+	generator.DockerProfile = config.DockerProfile
 
 	// Generate project
 	if err := generator.Generate(); err != nil {
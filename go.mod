@@ -1,3 +1,8 @@
 module github.com/jwill9999/scaffold-go
 
 go 1.22
+
+require (
+	golang.org/x/mod v0.17.0
+	gopkg.in/yaml.v3 v3.0.1
+)
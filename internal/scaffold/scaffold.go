@@ -3,15 +3,19 @@ package scaffold
 import (
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/jwill9999/scaffold-go/pkg/deps"
 	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/templates"
 )
 
-//go:embed templates/api/* templates/config/* templates/docker/*
+//go:embed templates/api/* templates/config/* templates/docker/* templates/pkg/*
 var templateFS embed.FS
 
 type Generator struct {
@@ -20,19 +24,22 @@ type Generator struct {
 	Features    []string
 	DBType      string
 	Deployment  string
-	OutputDir   string
-	Logger      *logger.Logger
+	// DockerProfile is the Dockerfile's default image: dev or prod
+	DockerProfile string
+	OutputDir     string
+	Logger        *logger.Logger
 }
 
 func NewGenerator(name, module, features, dbType, deployment, outputDir string, logger *logger.Logger) *Generator {
 	return &Generator{
-		ProjectName: name,
-		ModulePath:  module,
-		Features:    parseFeatures(features),
-		DBType:      dbType,
-		Deployment:  deployment,
-		OutputDir:   outputDir,
-		Logger:      logger,
+		ProjectName:   name,
+		ModulePath:    module,
+		Features:      parseFeatures(features),
+		DBType:        dbType,
+		Deployment:    deployment,
+		DockerProfile: "prod",
+		OutputDir:     outputDir,
+		Logger:        logger,
 	}
 }
 
@@ -43,7 +50,29 @@ func parseFeatures(features string) []string {
 	return strings.Split(features, ",")
 }
 
+// CGO reports whether the database driver needs cgo. The SQLite driver
+// wraps the C library; the others are pure Go.
+func (g *Generator) CGO() bool {
+	return g.DBType == "sqlite" || g.DBType == "sqlite3"
+}
+
+// GoVersion returns the go directive of the generated go.mod
+func (g *Generator) GoVersion() string {
+	return deps.GoVersion
+}
+
+// Dependencies returns the modules the generated code imports. The base
+// templates only import the standard library and features generate no code
+// yet, so none are required.
+func (g *Generator) Dependencies() ([]deps.Module, error) {
+	return deps.Resolve(nil)
+}
+
 func (g *Generator) Generate() error {
+	if g.DockerProfile != "dev" && g.DockerProfile != "prod" {
+		return fmt.Errorf("unsupported Docker profile %q (supported: dev, prod)", g.DockerProfile)
+	}
+
 	// Create project directory
 	if err := os.MkdirAll(g.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create project directory: %w", err)
@@ -79,6 +108,7 @@ func (g *Generator) generateProjectStructure() error {
 		"internal/core/errors",
 		"internal/core/server",
 		"pkg/database",
+		"pkg/lifecycle",
 		"pkg/logger",
 		"pkg/security",
 		"pkg/metrics",
@@ -96,19 +126,62 @@ func (g *Generator) generateProjectStructure() error {
 
 func (g *Generator) generateBaseFiles() error {
 	files := map[string]string{
-		"main.go":            "templates/api/main.go.tmpl",
-		"go.mod":             "templates/config/go.mod.tmpl",
-		"config.yaml":        "templates/config/config.yaml.tmpl",
-		"Dockerfile":         "templates/docker/Dockerfile.tmpl",
-		"docker-compose.yml": "templates/docker/docker-compose.tmpl",
+		"main.go":                    "templates/api/main.go.tmpl",
+		"go.mod":                     "templates/config/go.mod.tmpl",
+		"config.yaml":                "templates/config/config.yaml.tmpl",
+		"Dockerfile":                 "templates/docker/Dockerfile.tmpl",
+		"pkg/logger/logger.go":       "templates/pkg/logger.go.tmpl",
 	}
 
 	for target, tmpl := range files {
-		if err := g.generateFile(target, tmpl); err != nil {
+		if err := g.generateFile(templateFS, target, tmpl); err != nil {
 			return fmt.Errorf("failed to generate %s: %w", target, err)
 		}
 	}
 
+	// Packages both generators produce come from the scaffold tool's
+	// templates
+	shared := map[string]string{
+		"pkg/lifecycle/lifecycle.go": "lifecycle/lifecycle.go.tmpl",
+	}
+	for target, tmpl := range shared {
+		if err := g.generateFile(templates.FS, target, tmpl); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", target, err)
+		}
+	}
+
+	return g.generateCompose()
+}
+
+// generateCompose writes the docker-compose.yml assembled from the services
+// the database and features use, and the configuration files they mount.
+// The fragments are shared with the scaffold tool's generator.
+func (g *Generator) generateCompose() error {
+	features := make(map[string]bool, len(g.Features))
+	for _, f := range g.Features {
+		features[f] = true
+	}
+
+	files, err := generator.ComposeFiles(generator.ComposeProject{
+		Name:     g.ProjectName,
+		Database: g.DBType,
+		Features: features,
+		Dev:      g.DockerProfile == "dev",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate %s: %w", generator.ComposeFile, err)
+	}
+
+	for target, content := range files {
+		targetPath := filepath.Join(g.OutputDir, target)
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", target, err)
+		}
+		if err := os.WriteFile(targetPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", target, err)
+		}
+		g.Logger.Debug("Created %s", target)
+	}
 	return nil
 }
 
@@ -121,8 +194,8 @@ func (g *Generator) generateFeatures() error {
 	return nil
 }
 
-func (g *Generator) generateFile(target, tmpl string) error {
-	content, err := templateFS.ReadFile(tmpl)
+func (g *Generator) generateFile(fsys fs.FS, target, tmpl string) error {
+	content, err := fs.ReadFile(fsys, tmpl)
 	if err != nil {
 		return fmt.Errorf("failed to read template %s: %w", tmpl, err)
 	}
@@ -151,10 +224,12 @@ func (g *Generator) generateFile(target, tmpl string) error {
 		return fmt.Errorf("failed to execute template %s: %w", tmpl, err)
 	}
 
+	g.Logger.Debug("Created %s", target)
 	return err
 }
 
 func (g *Generator) generateFeature(feature string) error {
+	g.Logger.Debug("Generating feature %s", feature)
 	switch feature {
 	case "auth":
 		return g.generateAuthFeature()
@@ -0,0 +1,122 @@
+// Package deps is the version catalog of generated projects. It pins every
+// third-party module a generated project may import and records which
+// templates need each one, so a project's go.mod lists exactly the modules
+// its code uses at versions the templates are tested against.
+package deps
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GoVersion is the go directive written to generated go.mod files
+const GoVersion = "1.22"
+
+// Module is a required module at its pinned version
+type Module struct {
+	Path    string
+	Version string
+}
+
+// Modules used by generated projects
+const (
+	Chi        = "github.com/go-chi/chi/v5"
+	Dataloader = "github.com/graph-gophers/dataloader/v7"
+	Echo       = "github.com/labstack/echo/v4"
+	Gin        = "github.com/gin-gonic/gin"
+	GRPC       = "google.golang.org/grpc"
+	GraphQL    = "github.com/graph-gophers/graphql-go"
+	MySQL      = "github.com/go-sql-driver/mysql"
+	PQ         = "github.com/lib/pq"
+	Protobuf   = "google.golang.org/protobuf"
+	Redis      = "github.com/redis/go-redis/v9"
+	SQLite     = "github.com/mattn/go-sqlite3"
+	SQLX       = "github.com/jmoiron/sqlx"
+	Validator  = "github.com/go-playground/validator/v10"
+	Viper      = "github.com/spf13/viper"
+	Zap        = "go.uber.org/zap"
+	ZapExp     = "go.uber.org/zap/exp"
+	Zerolog    = "github.com/rs/zerolog"
+)
+
+// Versions pins each module to the version generated code is tested with
+var Versions = map[string]string{
+	Chi:        "v5.1.0",
+	Dataloader: "v7.1.0",
+	Echo:       "v4.12.0",
+	Gin:        "v1.10.0",
+	GRPC:       "v1.64.0",
+	GraphQL:    "v1.5.0",
+	MySQL:      "v1.8.1",
+	PQ:         "v1.10.9",
+	Protobuf:   "v1.34.2",
+	Redis:      "v9.5.1",
+	SQLite:     "v1.14.22",
+	SQLX:       "v1.4.0",
+	Validator:  "v10.22.0",
+	Viper:      "v1.18.2",
+	Zap:        "v1.27.0",
+	ZapExp:     "v0.3.0",
+	Zerolog:    "v1.33.0",
+}
+
+// Templates maps templates, by their path below tools/scaffold/templates,
+// to the modules their output imports. Templates importing only the
+// standard library and the project itself are not listed.
+var Templates = map[string][]string{
+	"config.go.tmpl":                    {Viper},
+	"database.go.tmpl":                  {SQLX},
+	"handlers.go.tmpl":                  {Validator},
+	"health/redis.go.tmpl":              {Redis},
+	"health/redis_client.go.tmpl":       {Redis},
+	"security/ratelimit_redis.go.tmpl":  {Redis},
+	"repository.go.tmpl":                {SQLX},
+	"resource/repository.go.tmpl":       {SQLX},
+	"router/chi.go.tmpl":                {Chi},
+	"router/echo.go.tmpl":               {Echo},
+	"router/gin.go.tmpl":                {Gin},
+	"logger/zap.go.tmpl":                {Zap, ZapExp},
+	"logger/zerolog.go.tmpl":            {Zerolog},
+	"grpc/server.go.tmpl":               {GRPC},
+	"grpc/convert.go.tmpl":              {GRPC, Protobuf, Validator},
+	"grpc/resource_server.go.tmpl":      {GRPC, Protobuf},
+	"graphql/handler.go.tmpl":           {GraphQL, Validator},
+	"graphql/loaders.go.tmpl":           {Dataloader},
+	"graphql/resource_resolver.go.tmpl": {GraphQL},
+	"graphql/scalars.go.tmpl":           {GraphQL},
+}
+
+// Drivers maps each database to the database/sql driver database.go.tmpl
+// imports for it
+var Drivers = map[string]string{
+	"postgres": PQ,
+	"mysql":    MySQL,
+	"sqlite":   SQLite,
+}
+
+// Resolve returns the modules needed by the given templates and any further
+// modules, such as the database driver, sorted by path. Templates without an
+// entry need no modules. Features add modules only through the templates
+// they render, so a feature whose code is not generated adds nothing.
+func Resolve(templates []string, modules ...string) ([]Module, error) {
+	paths := make(map[string]bool)
+	for _, tmpl := range templates {
+		for _, path := range Templates[tmpl] {
+			paths[path] = true
+		}
+	}
+	for _, path := range modules {
+		paths[path] = true
+	}
+
+	required := make([]Module, 0, len(paths))
+	for path := range paths {
+		version, ok := Versions[path]
+		if !ok {
+			return nil, fmt.Errorf("no version pinned for module %s", path)
+		}
+		required = append(required, Module{Path: path, Version: version})
+	}
+	sort.Slice(required, func(i, j int) bool { return required[i].Path < required[j].Path })
+	return required, nil
+}
@@ -0,0 +1,69 @@
+package deps
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []string
+		modules   []string
+		want      []Module
+	}{
+		{
+			name: "Nothing required",
+			want: []Module{},
+		},
+		{
+			name:      "Standard library templates need nothing",
+			templates: []string{"main.go.tmpl", "logger/slog.go.tmpl"},
+			want:      []Module{},
+		},
+		{
+			name:      "Shared modules are listed once and sorted",
+			templates: []string{"database.go.tmpl", "repository.go.tmpl", "router/chi.go.tmpl"},
+			want: []Module{
+				{Chi, Versions[Chi]},
+				{SQLX, Versions[SQLX]},
+			},
+		},
+		{
+			name:      "Extra modules are added",
+			templates: []string{"database.go.tmpl"},
+			modules:   []string{Drivers["sqlite"]},
+			want: []Module{
+				{SQLX, Versions[SQLX]},
+				{SQLite, Versions[SQLite]},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.templates, tt.modules...)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogIsPinned(t *testing.T) {
+	for tmpl, modules := range Templates {
+		for _, m := range modules {
+			if Versions[m] == "" {
+				t.Errorf("%s requires %s, which has no pinned version", tmpl, m)
+			}
+		}
+	}
+	for database, driver := range Drivers {
+		if Versions[driver] == "" {
+			t.Errorf("%s driver %s has no pinned version", database, driver)
+		}
+	}
+}
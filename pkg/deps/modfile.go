@@ -0,0 +1,46 @@
+package deps
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// ModFile returns the go.mod of the module at path requiring modules, with
+// the catalog's go directive. A non-empty toolchain, such as go1.22.5, adds
+// a toolchain line.
+func ModFile(path string, required []Module, toolchain string) ([]byte, error) {
+	if err := module.CheckImportPath(path); err != nil {
+		return nil, fmt.Errorf("invalid module path: %w", err)
+	}
+
+	f := &modfile.File{Syntax: &modfile.FileSyntax{}}
+	if err := f.AddModuleStmt(path); err != nil {
+		return nil, fmt.Errorf("failed to add module path %q: %w", path, err)
+	}
+	if err := f.AddGoStmt(GoVersion); err != nil {
+		return nil, fmt.Errorf("failed to add go directive: %w", err)
+	}
+	if toolchain != "" {
+		if err := f.AddToolchainStmt(toolchain); err != nil {
+			return nil, fmt.Errorf("failed to add toolchain: %w", err)
+		}
+	}
+
+	requires := make([]*modfile.Require, 0, len(required))
+	for _, m := range required {
+		if err := module.Check(m.Path, m.Version); err != nil {
+			return nil, fmt.Errorf("invalid requirement: %w", err)
+		}
+		requires = append(requires, &modfile.Require{Mod: module.Version{Path: m.Path, Version: m.Version}})
+	}
+	f.SetRequireSeparateIndirect(requires)
+	f.Cleanup()
+
+	content, err := f.Format()
+	if err != nil {
+		return nil, fmt.Errorf("failed to format go.mod: %w", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,64 @@
+package deps
+
+import "testing"
+
+func TestModFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		required  []Module
+		toolchain string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "No requirements",
+			path: "example.com/app",
+			want: "module example.com/app\n\ngo " + GoVersion + "\n",
+		},
+		{
+			name:     "Single requirement",
+			path:     "example.com/app",
+			required: []Module{{Gin, "v1.10.0"}},
+			want:     "module example.com/app\n\ngo " + GoVersion + "\n\nrequire github.com/gin-gonic/gin v1.10.0\n",
+		},
+		{
+			name:      "Requirements and toolchain",
+			path:      "example.com/app",
+			required:  []Module{{Chi, "v5.1.0"}, {"github.com/uber/jaeger-client-go", "v2.30.0+incompatible"}},
+			toolchain: "go1.22.5",
+			want: "module example.com/app\n\ngo " + GoVersion + "\n\ntoolchain go1.22.5\n\nrequire (\n" +
+				"\tgithub.com/go-chi/chi/v5 v5.1.0\n" +
+				"\tgithub.com/uber/jaeger-client-go v2.30.0+incompatible\n)\n",
+		},
+		{
+			name:      "Invalid toolchain",
+			path:      "example.com/app",
+			toolchain: "1.22.5",
+			wantErr:   true,
+		},
+		{
+			name:     "Version not matching major suffix",
+			path:     "example.com/app",
+			required: []Module{{Chi, "v1.0.0"}},
+			wantErr:  true,
+		},
+		{
+			name:    "Invalid module path",
+			path:    "example.com/app;rm",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ModFile(tt.path, tt.required, tt.toolchain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ModFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ModFile() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
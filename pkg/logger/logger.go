@@ -0,0 +1,227 @@
+// Package logger writes the scaffold CLIs' progress and diagnostics. Output
+// is human readable text, coloured when written to a terminal, or one JSON
+// object per line for tools consuming it.
+package logger
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a message
+type Level int
+
+// Message levels, from most to least verbose
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the lower case level name
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// Output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ANSI colours used for text output on a terminal
+const (
+	colorReset  = "\033[0m"
+	colorGray   = "\033[90m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+)
+
+// Logger writes messages at or above its level. It is safe for concurrent
+// use.
+type Logger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	level  Level
+	format string
+	color  bool
+	now    func() time.Time
+}
+
+// New returns a logger writing info and above as text to out. Colour is
+// used when out is a terminal and NO_COLOR is not set.
+func New(out io.Writer) *Logger {
+	return &Logger{
+		out:    out,
+		level:  LevelInfo,
+		format: FormatText,
+		color:  isTerminal(out) && os.Getenv("NO_COLOR") == "",
+		now:    time.Now,
+	}
+}
+
+// isTerminal reports whether w is a character device such as a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Writer returns the writer messages are written to. Interactive prompts
+// use it so they appear alongside the log.
+func (l *Logger) Writer() io.Writer {
+	return l.out
+}
+
+// SetLevel sets the lowest level written
+func (l *Logger) SetLevel(level Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
+
+// SetFormat selects text or json output
+func (l *Logger) SetFormat(format string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unsupported log format %q (supported: text, json)", format)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.format = format
+	return nil
+}
+
+// RegisterFlags adds the -verbose, -quiet and -log-format flags to fs. They
+// apply to the logger as fs is parsed, so the last of -verbose and -quiet
+// wins.
+func (l *Logger) RegisterFlags(fs *flag.FlagSet) {
+	fs.BoolFunc("verbose", "Show debug output", func(value string) error {
+		return l.setLevelIf(value, LevelDebug)
+	})
+	fs.BoolFunc("quiet", "Only show warnings and errors", func(value string) error {
+		return l.setLevelIf(value, LevelWarn)
+	})
+	fs.Func("log-format", "Log output format (text, json)", l.SetFormat)
+}
+
+// setLevelIf sets level when the boolean flag value is true, and restores
+// the default level when it is false
+func (l *Logger) setLevelIf(value string, level Level) error {
+	on, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if !on {
+		level = LevelInfo
+	}
+	l.SetLevel(level)
+	return nil
+}
+
+// Debug writes a message shown only with -verbose
+func (l *Logger) Debug(format string, args ...interface{}) {
+	l.log(LevelDebug, format, args...)
+}
+
+// Info writes a progress message
+func (l *Logger) Info(format string, args ...interface{}) {
+	l.log(LevelInfo, format, args...)
+}
+
+// Warn writes a warning
+func (l *Logger) Warn(format string, args ...interface{}) {
+	l.log(LevelWarn, format, args...)
+}
+
+// Error writes an error
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.log(LevelError, format, args...)
+}
+
+// Fatal writes an error and exits with status 1
+func (l *Logger) Fatal(format string, args ...interface{}) {
+	l.log(LevelError, format, args...)
+	os.Exit(1)
+}
+
+// jsonRecord is a message written in json format
+type jsonRecord struct {
+	Time  string `json:"time"`
+	Level string `json:"level"`
+	Msg   string `json:"msg"`
+}
+
+func (l *Logger) log(level Level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level < l.level {
+		return
+	}
+
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+
+	var line string
+	if l.format == FormatJSON {
+		data, err := json.Marshal(jsonRecord{
+			Time:  l.now().UTC().Format(time.RFC3339),
+			Level: level.String(),
+			Msg:   msg,
+		})
+		if err != nil {
+			return
+		}
+		line = string(data)
+	} else {
+		line = l.text(level, msg)
+	}
+
+	// Nothing useful can be done if the output fails
+	_, _ = fmt.Fprintln(l.out, line)
+}
+
+// text formats a message for people, labelling anything but info
+func (l *Logger) text(level Level, msg string) string {
+	var label, color string
+	switch level {
+	case LevelDebug:
+		label, color = "Debug: ", colorGray
+	case LevelWarn:
+		label, color = "Warning: ", colorYellow
+	case LevelError:
+		label, color = "Error: ", colorRed
+	}
+
+	if l.color && color != "" {
+		return color + label + msg + colorReset
+	}
+	return label + msg
+}
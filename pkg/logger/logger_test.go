@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "Default",
+			want: "info\nWarning: warn\nError: error\n",
+		},
+		{
+			name: "Verbose",
+			args: []string{"-verbose"},
+			want: "Debug: debug\ninfo\nWarning: warn\nError: error\n",
+		},
+		{
+			name: "Quiet",
+			args: []string{"-quiet"},
+			want: "Warning: warn\nError: error\n",
+		},
+		{
+			name: "Last flag wins",
+			args: []string{"-quiet", "-verbose=false"},
+			want: "info\nWarning: warn\nError: error\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			log := New(&out)
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			log.RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			log.Debug("debug")
+			log.Info("info")
+			log.Warn("warn")
+			log.Error("error")
+
+			if out.String() != tt.want {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out)
+	log.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	log.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-log-format", "json"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	log.Info("Created %s", "users.go")
+	log.Warn("careful")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", out.String())
+	}
+
+	var got jsonRecord
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", lines[0], err)
+	}
+	want := jsonRecord{Time: "2024-01-02T03:04:05Z", Level: "info", Msg: "Created users.go"}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestInvalidFormat(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	New(&bytes.Buffer{}).RegisterFlags(fs)
+
+	if err := fs.Parse([]string{"-log-format", "xml"}); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
+
+func TestColor(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out)
+	if log.color {
+		t.Fatal("Expected no colour when not writing to a terminal")
+	}
+
+	log.color = true
+	log.Info("plain")
+	log.Error("failed")
+
+	want := "plain\n" + colorRed + "Error: failed" + colorReset + "\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+)
+
+// startupTest checks a generated project starts from the configuration it
+// loads. It imports the project as example.com/shop.
+const startupTest = "tools/scaffold/testdata/startup/config_test.go"
+
+// petstore is imported into each project, whose router then serves it in
+// routesTest
+const (
+	petstore   = "tools/scaffold/testdata/openapi/petstore.yaml"
+	routesTest = "tools/scaffold/testdata/openapi/routes_test.go"
+)
+
+// middlewareTest checks each router applies the middleware NewRouter is
+// given
+const middlewareTest = "tools/scaffold/testdata/middleware/middleware_test.go"
+
+// goCommand runs the go command in the generated project dir and returns
+// its combined output
+func goCommand(dir string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// TestGeneratedProjectsBuild builds generated projects against the module
+// versions their go.mod pins, which the stubs of the type-check cannot
+// catch, and runs their startup test, the routes of an imported OpenAPI
+// document and the middleware on each router
+func TestGeneratedProjectsBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping builds of generated projects in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("Skipping builds of generated projects without the go command")
+	}
+	chdirRepoRoot(t)
+
+	tests := []struct {
+		name     string
+		router   string
+		logger   string
+		database string
+	}{
+		{"gin with slog", generator.RouterGin, generator.LoggerSlog, "postgres"},
+		{"chi with zap on mysql", generator.RouterChi, generator.LoggerZap, "mysql"},
+		{"echo with zerolog", generator.RouterEcho, generator.LoggerZerolog, "postgres"},
+		{"stdlib with slog", generator.RouterStdlib, generator.LoggerSlog, "postgres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := t.TempDir()
+			scaffold := &ProjectScaffold{
+				Name:       project,
+				Module:     "example.com/shop",
+				Transports: []string{generator.TransportHTTP},
+				Router:     tt.router,
+				Logger:     tt.logger,
+				Config: ProjectConfig{
+					Database:   DatabaseConfig{Type: tt.database},
+					Migrations: generator.DefaultMigrationSettings(),
+				},
+			}
+			if err := scaffold.generateBaseFiles(); err != nil {
+				t.Fatalf("Failed to generate project: %v", err)
+			}
+			var out bytes.Buffer
+			if err := runImportCommand([]string{"openapi", "-project", project, petstore}, logger.New(&out), newReporter("test")); err != nil {
+				t.Fatalf("Failed to import %s: %v\n%s", petstore, err, out.String())
+			}
+
+			// Without a module proxy or cache the modules cannot be fetched
+			if out, err := goCommand(project, "mod", "download"); err != nil {
+				t.Skipf("Skipping build, modules unavailable: %v\n%s", err, out)
+			}
+			if out, err := goCommand(project, "build", "./..."); err != nil {
+				t.Fatalf("Generated project does not build: %v\n%s", err, out)
+			}
+
+			copyTest(t, startupTest, filepath.Join(project, "internal", "config", "config_test.go"))
+			copyTest(t, routesTest, filepath.Join(project, "internal", "handlers", "openapi_routes_test.go"))
+			copyTest(t, middlewareTest, filepath.Join(project, "internal", "handlers", "middleware_test.go"))
+			if out, err := goCommand(project, "test", "./internal/config", "./internal/handlers"); err != nil {
+				t.Fatalf("Tests of the generated project failed: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+// copyTest copies a test from the repository into a generated project
+func copyTest(t *testing.T, src, dst string) {
+	t.Helper()
+
+	// #nosec G304 - src is a test in the repository
+	content, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", src, err)
+	}
+	if err := os.WriteFile(dst, content, 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", dst, err)
+	}
+}
+
+// TestGeneratedPackages runs tests against the packages of a generated
+// project that only use the standard library, in a module of their own so
+// no dependency needs to be downloaded
+func TestGeneratedPackages(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping tests of generated packages in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("Skipping tests of generated packages without the go command")
+	}
+	chdirRepoRoot(t)
+
+	tests := []struct {
+		pkg  string
+		test string
+	}{
+		{"pkg/lifecycle", "tools/scaffold/testdata/lifecycle/lifecycle_test.go"},
+		{"pkg/health", "tools/scaffold/testdata/health/health_test.go"},
+	}
+
+	g := generator.NewTemplateGenerator("shop", "example.com/shop", nil, nil)
+	g.Output = make(map[string][]byte)
+	if err := g.Generate(); err != nil {
+		t.Fatalf("Failed to generate project: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			module := t.TempDir()
+			if err := os.WriteFile(filepath.Join(module, "go.mod"), []byte("module example.com/shop\n\ngo 1.22\n"), 0600); err != nil {
+				t.Fatalf("Failed to write go.mod: %v", err)
+			}
+			dir := filepath.Join(module, filepath.FromSlash(tt.pkg))
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				t.Fatalf("Failed to create %s: %v", tt.pkg, err)
+			}
+			for name, content := range g.Output {
+				if path.Dir(name) != tt.pkg {
+					continue
+				}
+				if err := os.WriteFile(filepath.Join(module, filepath.FromSlash(name)), content, 0600); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+			copyTest(t, tt.test, filepath.Join(dir, filepath.Base(tt.test)))
+
+			if out, err := goCommand(module, "test", "-race", "./..."); err != nil {
+				t.Fatalf("Tests of %s failed: %v\n%s", tt.pkg, err, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported CI providers
+const (
+	CIGitHub = "github"
+	CIGitLab = "gitlab"
+	CINone   = "none"
+)
+
+// ciTemplateDir holds the CI pipeline templates. GitHub Actions evaluates
+// ${{ }} expressions itself, so templates in it are rendered with [[ ]]
+// delimiters.
+const ciTemplateDir = "tools/scaffold/templates/ci/"
+
+// ciTemplates maps each CI provider to its pipeline file and template
+var ciTemplates = map[string]map[string]string{
+	CIGitHub: {".github/workflows/ci.yml": "tools/scaffold/templates/ci/github.yml.tmpl"},
+	CIGitLab: {".gitlab-ci.yml": "tools/scaffold/templates/ci/gitlab-ci.yml.tmpl"},
+	CINone:   nil,
+}
+
+// ParseCI validates a CI provider name. An empty name selects GitHub
+// Actions.
+func ParseCI(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return CIGitHub, nil
+	}
+	if _, ok := ciTemplates[name]; !ok {
+		return "", fmt.Errorf("unsupported CI provider %q (supported: github, gitlab, none)", name)
+	}
+	return name, nil
+}
+
+// SetCI selects the CI provider the project's pipeline is generated for
+func (g *TemplateGenerator) SetCI(name string) error {
+	provider, err := ParseCI(name)
+	if err != nil {
+		return err
+	}
+
+	g.CI = provider
+	return nil
+}
+
+// ciFiles returns the pipeline files of the selected CI provider
+func (g *TemplateGenerator) ciFiles() map[string]string {
+	return ciTemplates[g.CI]
+}
@@ -0,0 +1,186 @@
+package generator
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseCI(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", CIGitHub, false},
+		{"github", CIGitHub, false},
+		{" GitLab ", CIGitLab, false},
+		{"none", CINone, false},
+		{"jenkins", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCI(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// ciProject is a project configuration the pipelines are checked for
+type ciProject struct {
+	name       string
+	database   string
+	features   []string
+	transports []string
+	services   []string
+}
+
+var ciProjects = []ciProject{
+	{name: "postgres", database: "postgres", services: []string{"postgres"}},
+	{name: "mysql with cache", database: "mysql", features: []string{"cache"}, services: []string{"mysql", "redis"}},
+	{name: "sqlite with grpc", database: "sqlite", transports: []string{TransportHTTP, TransportGRPC}},
+}
+
+// generateCI generates the project with the CI provider into memory
+func generateCI(t *testing.T, provider string, p ciProject) map[string][]byte {
+	t.Helper()
+
+	g := NewTemplateGenerator("shop", "example.com/shop", p.features, nil)
+	g.Output = make(map[string][]byte)
+	g.Database = p.database
+	if p.transports != nil {
+		g.SetTransports(p.transports)
+	}
+	if err := g.SetCI(provider); err != nil {
+		t.Fatalf("SetCI failed: %v", err)
+	}
+	if err := g.Generate(); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	return g.Output
+}
+
+func TestGitHubWorkflow(t *testing.T) {
+	chdirRepoRoot(t)
+
+	for _, p := range ciProjects {
+		t.Run(p.name, func(t *testing.T) {
+			var workflow struct {
+				Jobs map[string]struct {
+					Needs    []string               `yaml:"needs"`
+					Services map[string]interface{} `yaml:"services"`
+					Env      map[string]string      `yaml:"env"`
+					Steps    []struct {
+						Uses string `yaml:"uses"`
+						Run  string `yaml:"run"`
+					} `yaml:"steps"`
+				} `yaml:"jobs"`
+			}
+			decodeYAML(t, generateCI(t, CIGitHub, p), ".github/workflows/ci.yml", &workflow)
+
+			var jobs []string
+			for name := range workflow.Jobs {
+				jobs = append(jobs, name)
+			}
+			sort.Strings(jobs)
+			if want := []string{"docker", "govulncheck", "lint", "test", "vet"}; !reflect.DeepEqual(jobs, want) {
+				t.Fatalf("Jobs = %v, want %v", jobs, want)
+			}
+			if needs := workflow.Jobs["docker"].Needs; len(needs) != 4 {
+				t.Errorf("Expected the image to be built after every check, got needs %v", needs)
+			}
+
+			grpc := contains(p.transports, TransportGRPC)
+			for name, job := range workflow.Jobs {
+				if len(job.Steps) == 0 || job.Steps[0].Uses != "actions/checkout@v4" {
+					t.Errorf("%s: expected the job to start by checking out the code", name)
+				}
+				generates := false
+				for _, step := range job.Steps {
+					generates = generates || strings.Contains(step.Run, "go generate ./api/proto")
+				}
+				if generates != grpc {
+					t.Errorf("%s: expected protobuf code generation %v, got %v", name, grpc, generates)
+				}
+			}
+
+			var services []string
+			for name := range workflow.Jobs["test"].Services {
+				services = append(services, name)
+			}
+			sort.Strings(services)
+			if !reflect.DeepEqual(services, p.services) {
+				t.Errorf("Test services = %v, want %v", services, p.services)
+			}
+			if _, ok := workflow.Jobs["test"].Env["DB_HOST"]; ok != (p.database != "sqlite") {
+				t.Errorf("Expected DB_HOST only with a database server, got env %v", workflow.Jobs["test"].Env)
+			}
+		})
+	}
+}
+
+func TestGitLabPipeline(t *testing.T) {
+	chdirRepoRoot(t)
+
+	for _, p := range ciProjects {
+		t.Run(p.name, func(t *testing.T) {
+			var pipeline map[string]interface{}
+			decodeYAML(t, generateCI(t, CIGitLab, p), ".gitlab-ci.yml", &pipeline)
+
+			stages := make(map[string]bool)
+			for _, stage := range pipeline["stages"].([]interface{}) {
+				stages[stage.(string)] = true
+			}
+
+			var jobs []string
+			for name, value := range pipeline {
+				job, ok := value.(map[string]interface{})
+				if !ok || name == "default" || name == "variables" {
+					continue
+				}
+				jobs = append(jobs, name)
+				if stage, _ := job["stage"].(string); !stages[stage] {
+					t.Errorf("%s: stage %q is not declared", name, stage)
+				}
+			}
+			sort.Strings(jobs)
+
+			want := []string{"docker", "govulncheck", "lint", "test", "vet"}
+			if contains(p.transports, TransportGRPC) {
+				want = []string{"docker", "generate", "govulncheck", "lint", "test", "vet"}
+			}
+			if !reflect.DeepEqual(jobs, want) {
+				t.Errorf("Jobs = %v, want %v", jobs, want)
+			}
+
+			var services []string
+			test := pipeline["test"].(map[string]interface{})
+			if list, ok := test["services"].([]interface{}); ok {
+				for _, s := range list {
+					services = append(services, s.(map[string]interface{})["alias"].(string))
+				}
+			}
+			sort.Strings(services)
+			if !reflect.DeepEqual(services, p.services) {
+				t.Errorf("Test services = %v, want %v", services, p.services)
+			}
+		})
+	}
+}
+
+func TestNoCI(t *testing.T) {
+	chdirRepoRoot(t)
+
+	for file := range generateCI(t, CINone, ciProjects[0]) {
+		if strings.HasPrefix(file, ".github/") || file == ".gitlab-ci.yml" {
+			t.Errorf("Expected no pipeline, got %s", file)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jwill9999/scaffold-go/tools/scaffold/templates"
+)
+
+// ComposeFile is the name of the generated Compose file
+const ComposeFile = "docker-compose.yml"
+
+// ComposeProject describes the project a Compose file is generated for
+type ComposeProject struct {
+	// Name is the project name, used as the database name
+	Name string
+	// Database is postgres, mysql or sqlite
+	Database string
+	// Features are the enabled features, e.g. cache, metrics or tracing
+	Features map[string]bool
+	// Dev builds the app from the Dockerfile's development stage and mounts
+	// the source for hot reload; otherwise the production stage is used
+	Dev bool
+	// GRPC publishes the app's gRPC port
+	GRPC bool
+}
+
+// composeFragment is a Compose file holding one backing service, its named
+// volumes and the settings it adds to the app service
+type composeFragment struct {
+	name    string
+	enabled func(p ComposeProject) bool
+	// files are configuration files mounted by the fragment's services,
+	// keyed by their path in the project
+	files map[string]string
+}
+
+// composeFragments are merged in this order, which is the order of the
+// services in the generated file. They are rendered from the templates
+// below compose/.
+var composeFragments = []composeFragment{
+	{name: "app", enabled: func(ComposeProject) bool { return true }},
+	{name: "postgres", enabled: composeDatabase("postgres")},
+	{name: "mysql", enabled: composeDatabase("mysql")},
+	{name: "redis", enabled: composeFeature("cache")},
+	{
+		name:    "otel-collector",
+		enabled: composeFeature("tracing"),
+		files:   map[string]string{"deploy/compose/otel-collector.yaml": "otel-collector.yaml"},
+	},
+	{name: "jaeger", enabled: composeFeature("tracing")},
+	{
+		name:    "prometheus",
+		enabled: composeFeature("metrics"),
+		files:   map[string]string{"deploy/compose/prometheus.yml": "prometheus.yml"},
+	},
+	{
+		name:    "grafana",
+		enabled: composeFeature("metrics"),
+		files:   map[string]string{"deploy/compose/grafana/datasources.yml": "grafana-datasources.yml"},
+	},
+}
+
+func composeDatabase(name string) func(ComposeProject) bool {
+	return func(p ComposeProject) bool { return p.Database == name }
+}
+
+func composeFeature(name string) func(ComposeProject) bool {
+	return func(p ComposeProject) bool { return p.Features[name] }
+}
+
+// GenerateCompose writes the project's docker-compose.yml, assembled from
+// the services its database and features use, and the configuration files
+// those services mount
+func (g *TemplateGenerator) GenerateCompose() error {
+	files, err := ComposeFiles(ComposeProject{
+		Name:     appName(g.ProjectName),
+		Database: g.Database,
+		Features: g.Features,
+		Dev:      g.DockerProfile == DockerProfileDev,
+		GRPC:     g.Transports[TransportGRPC],
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate %s: %w", ComposeFile, err)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.writeOutput(name, files[name]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ComposeFiles returns the Compose file of p and the configuration files its
+// services mount, keyed by their path in the project. The fragments of the
+// services the project uses are merged into one file, so the result only
+// starts what its database and features need.
+func ComposeFiles(p ComposeProject) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	var doc *yaml.Node
+	for _, f := range composeFragments {
+		if !f.enabled(p) {
+			continue
+		}
+
+		content, err := renderComposeTemplate("compose/"+f.name+".yaml.tmpl", p)
+		if err != nil {
+			return nil, err
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(content, &node); err != nil {
+			return nil, fmt.Errorf("failed to parse the %s compose fragment: %w", f.name, err)
+		}
+		if doc == nil {
+			doc = &node
+		} else if err := mergeYAML(doc.Content[0], node.Content[0], ""); err != nil {
+			return nil, fmt.Errorf("failed to merge the %s compose fragment: %w", f.name, err)
+		}
+
+		for file, tmpl := range f.files {
+			content, err := renderComposeTemplate("compose/config/"+tmpl+".tmpl", p)
+			if err != nil {
+				return nil, err
+			}
+			files[file] = content
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", ComposeFile, err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", ComposeFile, err)
+	}
+	files[ComposeFile] = separateBlocks(buf.Bytes())
+	return files, nil
+}
+
+// separateBlocks puts a blank line before each top-level key and service
+// that follows a nested block, which the YAML encoder does not preserve
+func separateBlocks(content []byte) []byte {
+	lines := strings.Split(string(content), "\n")
+	out := make([]string, 0, len(lines))
+	prevIndent := 0
+	for _, line := range lines {
+		if line == "" {
+			out = append(out, line)
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if indent <= 2 && prevIndent > indent && !strings.HasPrefix(line[indent:], "- ") {
+			out = append(out, "")
+		}
+		out = append(out, line)
+		prevIndent = indent
+	}
+	return []byte(strings.Join(out, "\n"))
+}
+
+// renderComposeTemplate executes the embedded template at name with p
+func renderComposeTemplate(name string, p ComposeProject) ([]byte, error) {
+	tmpl, err := template.New(path.Base(name)).ParseFS(templates.FS, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mergeYAML adds src to dst. Mappings are merged key by key and sequences
+// are concatenated, so a fragment can add environment variables and
+// dependencies to the app service; any other value may only be set once.
+func mergeYAML(dst, src *yaml.Node, at string) error {
+	if dst.Kind != src.Kind {
+		return fmt.Errorf("%s is set to different kinds of values", at)
+	}
+
+	switch dst.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			if existing := mappingValue(dst, key.Value); existing != nil {
+				if err := mergeYAML(existing, value, strings.TrimPrefix(at+"."+key.Value, ".")); err != nil {
+					return err
+				}
+				continue
+			}
+			dst.Content = append(dst.Content, key, value)
+		}
+	case yaml.SequenceNode:
+		dst.Content = append(dst.Content, src.Content...)
+	default:
+		if dst.Value != src.Value {
+			return fmt.Errorf("%s is set to both %q and %q", at, dst.Value, src.Value)
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value of key in the mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeFile is the part of a Compose file the tests inspect
+type composeFile struct {
+	Services map[string]struct {
+		Build struct {
+			Target string `yaml:"target"`
+		} `yaml:"build"`
+		Profiles    []string          `yaml:"profiles"`
+		Environment map[string]string `yaml:"environment"`
+		Volumes     []string          `yaml:"volumes"`
+		Healthcheck *struct {
+			Test []string `yaml:"test"`
+		} `yaml:"healthcheck"`
+		DependsOn map[string]struct {
+			Condition string `yaml:"condition"`
+		} `yaml:"depends_on"`
+	} `yaml:"services"`
+	Volumes map[string]interface{} `yaml:"volumes"`
+}
+
+func TestComposeFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		project  ComposeProject
+		services []string
+		dependOn []string
+		files    []string
+	}{
+		{
+			name:     "sqlite",
+			project:  ComposeProject{Name: "shop", Database: "sqlite"},
+			services: []string{"app"},
+		},
+		{
+			name:     "postgres with cache",
+			project:  ComposeProject{Name: "shop", Database: "postgres", Features: map[string]bool{"cache": true}},
+			services: []string{"app", "postgres", "redis"},
+			dependOn: []string{"postgres", "redis"},
+		},
+		{
+			name: "mysql with observability",
+			project: ComposeProject{
+				Name:     "shop",
+				Database: "mysql",
+				Features: map[string]bool{"metrics": true, "tracing": true},
+				Dev:      true,
+				GRPC:     true,
+			},
+			services: []string{"app", "grafana", "jaeger", "mysql", "otel-collector", "prometheus"},
+			dependOn: []string{"mysql"},
+			files:    []string{"deploy/compose/grafana/datasources.yml", "deploy/compose/otel-collector.yaml", "deploy/compose/prometheus.yml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := ComposeFiles(tt.project)
+			if err != nil {
+				t.Fatalf("ComposeFiles failed: %v", err)
+			}
+
+			var f composeFile
+			if err := yaml.Unmarshal(files[ComposeFile], &f); err != nil {
+				t.Fatalf("%s is not valid YAML: %v\n%s", ComposeFile, err, files[ComposeFile])
+			}
+
+			var services []string
+			for name := range f.Services {
+				services = append(services, name)
+			}
+			sort.Strings(services)
+			if !reflect.DeepEqual(services, tt.services) {
+				t.Errorf("Services = %v, want %v", services, tt.services)
+			}
+
+			app := f.Services["app"]
+			wantTarget := "production"
+			if tt.project.Dev {
+				wantTarget = "development"
+			}
+			if app.Build.Target != wantTarget {
+				t.Errorf("Expected the app built from %s, got %s", wantTarget, app.Build.Target)
+			}
+			var deps []string
+			for name := range app.DependsOn {
+				deps = append(deps, name)
+			}
+			sort.Strings(deps)
+			if !reflect.DeepEqual(deps, tt.dependOn) {
+				t.Errorf("App depends on %v, want %v", deps, tt.dependOn)
+			}
+			if tt.project.Database != "sqlite" && app.Environment["DB_HOST"] != tt.project.Database {
+				t.Errorf("Expected DB_HOST %s, got %q", tt.project.Database, app.Environment["DB_HOST"])
+			}
+
+			for name, svc := range f.Services {
+				for dep, d := range svc.DependsOn {
+					target, ok := f.Services[dep]
+					if !ok {
+						t.Errorf("%s depends on undefined service %s", name, dep)
+						continue
+					}
+					// A service outside a profile cannot wait for one that
+					// only starts with it
+					if len(target.Profiles) > 0 && !reflect.DeepEqual(target.Profiles, svc.Profiles) {
+						t.Errorf("%s depends on %s, which only starts with profiles %v", name, dep, target.Profiles)
+					}
+					if d.Condition == "service_healthy" && target.Healthcheck == nil {
+						t.Errorf("%s waits for %s to be healthy, which has no healthcheck", name, dep)
+					}
+				}
+				for _, v := range svc.Volumes {
+					source, _, _ := strings.Cut(v, ":")
+					switch {
+					case strings.HasPrefix(source, "./deploy/"):
+						if _, ok := files[strings.TrimPrefix(source, "./")]; !ok {
+							t.Errorf("%s mounts %s, which is not generated", name, source)
+						}
+					case source == ".":
+					default:
+						if _, ok := f.Volumes[source]; !ok {
+							t.Errorf("%s uses undeclared volume %s", name, source)
+						}
+					}
+				}
+			}
+
+			var generated []string
+			for file := range files {
+				if file != ComposeFile {
+					generated = append(generated, file)
+				}
+			}
+			sort.Strings(generated)
+			if !reflect.DeepEqual(generated, tt.files) {
+				t.Errorf("Configuration files = %v, want %v", generated, tt.files)
+			}
+		})
+	}
+}
+
+func TestMergeYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		dst     string
+		src     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "adds keys",
+			dst:  "services:\n  app:\n    environment:\n      A: a\n",
+			src:  "services:\n  app:\n    environment:\n      B: b\n  db:\n    image: db\n",
+			want: "services:\n  app:\n    environment:\n      A: a\n      B: b\n  db:\n    image: db\n",
+		},
+		{
+			name: "concatenates sequences",
+			dst:  "ports:\n  - \"80\"\n",
+			src:  "ports:\n  - \"443\"\n",
+			want: "ports:\n  - \"80\"\n  - \"443\"\n",
+		},
+		{
+			name: "keeps equal values",
+			dst:  "image: db\n",
+			src:  "image: db\n",
+			want: "image: db\n",
+		},
+		{
+			name:    "rejects conflicting values",
+			dst:     "services:\n  app:\n    image: a\n",
+			src:     "services:\n  app:\n    image: b\n",
+			wantErr: "services.app.image",
+		},
+		{
+			name:    "rejects different kinds",
+			dst:     "ports:\n  - \"80\"\n",
+			src:     "ports: \"80\"\n",
+			wantErr: "ports",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst, src yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.dst), &dst); err != nil {
+				t.Fatalf("invalid dst: %v", err)
+			}
+			if err := yaml.Unmarshal([]byte(tt.src), &src); err != nil {
+				t.Fatalf("invalid src: %v", err)
+			}
+
+			err := mergeYAML(dst.Content[0], src.Content[0], "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected an error about %s, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeYAML failed: %v", err)
+			}
+
+			var got strings.Builder
+			enc := yaml.NewEncoder(&got)
+			enc.SetIndent(2)
+			if err := enc.Encode(&dst); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("mergeYAML() =\n%s\nwant\n%s", got.String(), tt.want)
+			}
+		})
+	}
+}
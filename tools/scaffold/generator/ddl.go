@@ -0,0 +1,1030 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DDLSchema holds the resources imported from a SQL DDL file
+type DDLSchema struct {
+	Resources []Resource
+	// Warnings describe columns and constraints that could not be mapped
+	// onto resource fields
+	Warnings []string
+}
+
+// ParseDDL parses the CREATE TABLE statements in a SQL DDL file and builds a
+// resource for each table. Foreign keys and indexes declared in ALTER TABLE
+// and CREATE INDEX statements are applied to the tables they refer to; all
+// other statements are ignored. The DDL is parsed offline, so no database
+// connection is needed.
+func ParseDDL(src string) (*DDLSchema, error) {
+	tokens, err := tokenizeSQL(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ddlParser{tables: make(map[string]*ddlTable)}
+	for _, stmt := range splitTokens(tokens, ";") {
+		if err := p.statement(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(p.order) == 0 {
+		return nil, fmt.Errorf("no CREATE TABLE statements found")
+	}
+
+	schema := &DDLSchema{Warnings: p.warnings}
+	for _, name := range p.order {
+		res, warnings := p.tables[name].resource()
+		schema.Warnings = append(schema.Warnings, warnings...)
+		if res != nil {
+			schema.Resources = append(schema.Resources, *res)
+		}
+	}
+
+	return schema, nil
+}
+
+// auditColumns are generated for every resource and are not imported as fields
+var auditColumns = []string{"created_at", "updated_at", "deleted_at"}
+
+type ddlTable struct {
+	name       string
+	columns    []*ddlColumn
+	primaryKey []string
+	warnings   []string
+}
+
+type ddlColumn struct {
+	name       string
+	typeWords  []string
+	typeArgs   []string
+	array      bool
+	notNull    bool
+	primary    bool
+	unique     bool
+	index      bool
+	generated  bool
+	defaultSQL []sqlToken
+	enum       []string
+	references string
+	refColumn  string
+	onDelete   string
+}
+
+func (t *ddlTable) column(name string) *ddlColumn {
+	for _, c := range t.columns {
+		if strings.EqualFold(c.name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (t *ddlTable) warn(format string, args ...interface{}) {
+	t.warnings = append(t.warnings, fmt.Sprintf("%s: %s", t.name, fmt.Sprintf(format, args...)))
+}
+
+// resource converts the table to a resource. Tables without any importable
+// columns are skipped with a warning.
+func (t *ddlTable) resource() (*Resource, []string) {
+	pk := t.primaryKey
+	for _, c := range t.columns {
+		if c.primary {
+			pk = append(pk, c.name)
+		}
+	}
+	if len(pk) == 0 {
+		t.warn("no primary key; generated code assumes an id primary key")
+	} else if len(pk) != 1 || !strings.EqualFold(pk[0], "id") {
+		t.warn("primary key (%s) is not a single id column; generated code assumes an id primary key", strings.Join(pk, ", "))
+	}
+
+	var missing []string
+	for _, col := range auditColumns {
+		if t.column(col) == nil {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		t.warn("missing %s; generated repositories expect these columns", strings.Join(missing, ", "))
+	}
+
+	var fields []Field
+	for _, c := range t.columns {
+		if strings.EqualFold(c.name, "id") || contains(auditColumns, strings.ToLower(c.name)) {
+			continue
+		}
+
+		f, ok := t.field(c)
+		if ok {
+			fields = append(fields, f)
+		}
+	}
+
+	if len(fields) == 0 {
+		t.warn("no importable columns; table skipped")
+		return nil, t.warnings
+	}
+
+	name := singularize(t.name)
+	res, err := NewResource(name, fields)
+	if err != nil {
+		t.warn("%v; table skipped", err)
+		return nil, t.warnings
+	}
+	if res.TableName() != t.name {
+		res.Table = t.name
+	}
+
+	return &res, t.warnings
+}
+
+// field maps a column onto a resource field by building its definition and
+// parsing it, so imported fields follow the same rules as typed ones
+func (t *ddlTable) field(c *ddlColumn) (Field, bool) {
+	if !fieldNamePattern.MatchString(c.name) || toSnakeCase(c.name) != c.name {
+		t.warn("column %s is not a snake_case identifier; skipped", c.name)
+		return Field{}, false
+	}
+
+	if c.generated {
+		t.warn("column %s is a generated column; skipped", c.name)
+		return Field{}, false
+	}
+
+	typ, size, ok := mapColumnType(c)
+	if !ok {
+		t.warn("column %s has unsupported type %s; skipped", c.name, c.typeName())
+		return Field{}, false
+	}
+
+	var rules []string
+	if c.notNull || c.primary {
+		rules = append(rules, "required")
+	}
+
+	if len(c.enum) > 0 && (typ == "string" || typ == "text" || typ == "enum") {
+		if validEnum(c.enum) {
+			typ = "enum"
+			rules = append(rules, "oneof="+strings.Join(c.enum, "|"))
+		} else {
+			if typ == "enum" {
+				typ = "string"
+			}
+			t.warn("column %s has enum values that cannot be expressed as oneof; imported as %s", c.name, typ)
+		}
+	}
+
+	if size > 0 && typ != "enum" {
+		rules = append(rules, "max="+strconv.Itoa(size))
+	}
+
+	if c.unique || c.primary {
+		rules = append(rules, "unique")
+	}
+	if c.index {
+		rules = append(rules, "index")
+	}
+
+	if len(c.defaultSQL) > 0 && !(len(c.defaultSQL) == 1 && c.defaultSQL[0].is("null")) {
+		if def, ok := defaultLiteral(c.defaultSQL, typ); ok {
+			rules = append(rules, "default="+def)
+		} else {
+			t.warn("column %s has default %s that cannot be imported; skipped", c.name, joinTokens(c.defaultSQL))
+		}
+	}
+
+	if c.references != "" {
+		switch {
+		case typ != "int":
+			t.warn("column %s references %s but is not an integer; imported without the foreign key", c.name, c.references)
+		case c.refColumn != "" && !strings.EqualFold(c.refColumn, "id"):
+			t.warn("column %s references %s(%s) rather than id; imported without the foreign key", c.name, c.references, c.refColumn)
+		case !fieldNamePattern.MatchString(c.references):
+			t.warn("column %s references %s, which is not a valid table name; imported without the foreign key", c.name, c.references)
+		default:
+			typ = "ref"
+			rules = append(rules, "ref="+c.references)
+			if c.onDelete == "setnull" && c.notNull {
+				t.warn("column %s uses ON DELETE SET NULL but is NOT NULL; ON DELETE dropped", c.name)
+			} else if c.onDelete != "" {
+				rules = append(rules, "ondelete="+c.onDelete)
+			}
+		}
+	}
+
+	def := c.name + ":" + typ
+	if len(rules) > 0 {
+		def += ":" + strings.Join(rules, ",")
+	}
+
+	f, err := ParseField(def)
+	if err != nil {
+		t.warn("column %s could not be imported: %v", c.name, err)
+		return Field{}, false
+	}
+	return f, true
+}
+
+func (c *ddlColumn) typeName() string {
+	name := strings.Join(c.typeWords, " ")
+	if len(c.typeArgs) > 0 {
+		name += "(" + strings.Join(c.typeArgs, ",") + ")"
+	}
+	if c.array {
+		name += "[]"
+	}
+	return name
+}
+
+// mapColumnType maps a SQL column type onto a field type and, for strings,
+// the declared length
+func mapColumnType(c *ddlColumn) (string, int, bool) {
+	if c.array || len(c.typeWords) == 0 {
+		return "", 0, false
+	}
+
+	var words []string
+	for _, w := range c.typeWords {
+		switch w {
+		case "unsigned", "signed", "zerofill":
+		default:
+			words = append(words, w)
+		}
+	}
+	base := strings.Join(words, " ")
+
+	size := 0
+	if len(c.typeArgs) > 0 {
+		size, _ = strconv.Atoi(c.typeArgs[0])
+	}
+
+	switch {
+	case base == "enum":
+		return "enum", 0, len(c.enum) > 0
+	case base == "varchar", base == "character varying", base == "nvarchar", base == "varchar2",
+		base == "char", base == "character", base == "nchar", base == "bpchar":
+		return "string", size, true
+	case base == "text", base == "tinytext", base == "mediumtext", base == "longtext",
+		base == "clob", base == "citext", base == "ntext":
+		return "text", 0, true
+	case base == "tinyint" && size == 1, base == "bit" && size <= 1, base == "bool", base == "boolean":
+		return "bool", 0, true
+	case base == "int", base == "integer", base == "int2", base == "int4", base == "int8",
+		base == "smallint", base == "mediumint", base == "bigint", base == "tinyint",
+		base == "serial", base == "smallserial", base == "bigserial", base == "serial4", base == "serial8":
+		return "int", 0, true
+	case base == "real", base == "float", base == "float4", base == "float8", base == "double",
+		base == "double precision", base == "numeric", base == "decimal":
+		return "float", 0, true
+	case base == "date", base == "datetime", base == "timestamptz", strings.HasPrefix(base, "timestamp"):
+		return "time", 0, true
+	case base == "uuid", base == "uniqueidentifier":
+		return "uuid", 0, true
+	case base == "json", base == "jsonb":
+		return "json", 0, true
+	}
+
+	return "", 0, false
+}
+
+// defaultLiteral converts a column default into the value accepted by the
+// default= modifier. Only literals and the current timestamp are supported.
+func defaultLiteral(expr []sqlToken, typ string) (string, bool) {
+	// Drop casts such as 'draft'::character varying
+	for i, tok := range expr {
+		if tok.isPunct("::") {
+			expr = expr[:i]
+			break
+		}
+	}
+	for len(expr) >= 2 && expr[0].isPunct("(") && expr[len(expr)-1].isPunct(")") {
+		expr = expr[1 : len(expr)-1]
+	}
+
+	var value string
+	switch {
+	case len(expr) == 1 && expr[0].kind == tokString:
+		value = expr[0].text
+	case len(expr) == 2 && expr[0].isPunct("-") && expr[1].kind == tokWord:
+		value = "-" + expr[1].text
+	case len(expr) >= 1 && (expr[0].is("now") || expr[0].is("current_timestamp") || expr[0].is("localtimestamp")):
+		if typ != "time" {
+			return "", false
+		}
+		return "now", true
+	case len(expr) == 1 && expr[0].kind == tokWord:
+		value = strings.ToLower(expr[0].text)
+		if value == "null" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	if typ == "bool" {
+		switch strings.ToLower(value) {
+		case "1", "t", "true":
+			value = "true"
+		case "0", "f", "false":
+			value = "false"
+		}
+	}
+
+	// Field definitions are separated by whitespace and modifiers by commas
+	if value == "" || strings.ContainsAny(value, " \t\n,") {
+		return "", false
+	}
+	return value, true
+}
+
+func validEnum(values []string) bool {
+	for _, v := range values {
+		if v == "" || strings.ContainsAny(v, " \t\n,|") {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ddlParser collects tables from a sequence of statements
+type ddlParser struct {
+	tables   map[string]*ddlTable
+	order    []string
+	warnings []string
+}
+
+func (p *ddlParser) statement(toks []sqlToken) error {
+	switch {
+	case len(toks) == 0:
+		return nil
+	case toks[0].is("create"):
+		i := 1
+		for i < len(toks) && (toks[i].is("temporary") || toks[i].is("temp") || toks[i].is("unlogged") || toks[i].is("or") || toks[i].is("replace")) {
+			i++
+		}
+		if i < len(toks) && toks[i].is("table") {
+			return p.createTable(toks[i+1:])
+		}
+		if i < len(toks) && (toks[i].is("index") || toks[i].is("unique")) {
+			p.createIndex(toks[i:])
+		}
+	case toks[0].is("alter") && len(toks) > 1 && toks[1].is("table"):
+		p.alterTable(toks[2:])
+	}
+	return nil
+}
+
+func (p *ddlParser) table(name string) *ddlTable {
+	return p.tables[strings.ToLower(name)]
+}
+
+// createTable parses CREATE TABLE [IF NOT EXISTS] name ( elements ) ...
+func (p *ddlParser) createTable(toks []sqlToken) error {
+	i := skipWords(toks, 0, "if", "not", "exists")
+	name, i := qualifiedName(toks, i)
+	if name == "" {
+		return fmt.Errorf("CREATE TABLE is missing a table name")
+	}
+
+	if i >= len(toks) || !toks[i].isPunct("(") {
+		// CREATE TABLE ... AS SELECT and LIKE copies cannot be imported
+		p.warnings = append(p.warnings, fmt.Sprintf("%s: table has no column list; skipped", name))
+		return nil
+	}
+
+	end := groupEnd(toks, i)
+	if end < 0 {
+		return fmt.Errorf("table %s: unbalanced parentheses", name)
+	}
+
+	key := strings.ToLower(name)
+	if _, ok := p.tables[key]; ok {
+		return fmt.Errorf("table %s is defined more than once", name)
+	}
+
+	t := &ddlTable{name: name}
+	for _, elem := range splitTokens(toks[i+1:end], ",") {
+		if len(elem) == 0 {
+			continue
+		}
+		if isTableConstraint(elem) {
+			t.constraint(elem)
+			continue
+		}
+		t.columns = append(t.columns, parseColumn(elem))
+	}
+
+	p.tables[key] = t
+	p.order = append(p.order, key)
+	return nil
+}
+
+// createIndex applies single column indexes to the table's columns
+func (p *ddlParser) createIndex(toks []sqlToken) {
+	unique := toks[0].is("unique")
+	on := -1
+	for i, tok := range toks {
+		if tok.is("on") {
+			on = i
+			break
+		}
+	}
+	if on < 0 {
+		return
+	}
+
+	name, i := qualifiedName(toks, skipWords(toks, on+1, "only"))
+	t := p.table(name)
+	if t == nil {
+		return
+	}
+
+	if i < len(toks) && toks[i].is("using") {
+		i += 2
+	}
+	if i >= len(toks) || !toks[i].isPunct("(") {
+		return
+	}
+
+	// Partial indexes only cover some rows
+	for _, tok := range toks[i:] {
+		if tok.is("where") {
+			t.warn("partial index on %s not imported", strings.Join(columnList(toks, i), ", "))
+			return
+		}
+	}
+	t.indexColumns(columnList(toks, i), unique)
+}
+
+// alterTable applies ADD CONSTRAINT clauses to a table defined earlier
+func (p *ddlParser) alterTable(toks []sqlToken) {
+	i := skipWords(toks, 0, "only", "if", "exists")
+	name, i := qualifiedName(toks, i)
+	t := p.table(name)
+	if t == nil {
+		return
+	}
+
+	for _, clause := range splitTokens(toks[i:], ",") {
+		if len(clause) < 2 || !clause[0].is("add") {
+			continue
+		}
+		if isTableConstraint(clause[1:]) {
+			t.constraint(clause[1:])
+		}
+	}
+}
+
+func isTableConstraint(elem []sqlToken) bool {
+	if elem[0].kind != tokWord {
+		return false
+	}
+	switch strings.ToLower(elem[0].text) {
+	case "constraint", "primary", "unique", "foreign", "check", "key", "index", "exclude", "fulltext", "spatial":
+		return true
+	}
+	return false
+}
+
+// constraint applies a table constraint such as PRIMARY KEY (id),
+// UNIQUE (email) or FOREIGN KEY (user_id) REFERENCES users (id)
+func (t *ddlTable) constraint(elem []sqlToken) {
+	i := 0
+	if elem[i].is("constraint") {
+		i += 2
+	}
+	if i >= len(elem) {
+		return
+	}
+
+	kind := strings.ToLower(elem[i].text)
+	open := nextPunct(elem, i, "(")
+	if open < 0 {
+		return
+	}
+	cols := columnList(elem, open)
+
+	switch kind {
+	case "primary":
+		t.primaryKey = append(t.primaryKey, cols...)
+	case "unique":
+		if len(cols) != 1 {
+			t.warn("composite unique constraint (%s) not imported", strings.Join(cols, ", "))
+			return
+		}
+		t.indexColumns(cols, true)
+	case "key", "index":
+		t.indexColumns(cols, false)
+	case "foreign":
+		if len(cols) != 1 {
+			t.warn("composite foreign key (%s) not imported", strings.Join(cols, ", "))
+			return
+		}
+		c := t.column(cols[0])
+		if c == nil {
+			return
+		}
+		end := groupEnd(elem, open)
+		if end > 0 {
+			parseReferences(c, elem, end+1)
+		}
+	case "check":
+		t.check(elem[open:])
+	default:
+		t.warn("%s constraint not imported", strings.ToUpper(kind))
+	}
+}
+
+func (t *ddlTable) indexColumns(cols []string, unique bool) {
+	if len(cols) != 1 {
+		return
+	}
+	c := t.column(cols[0])
+	if c == nil {
+		return
+	}
+	if unique {
+		c.unique = true
+	} else {
+		c.index = true
+	}
+}
+
+// check turns CHECK (column IN ('a', 'b')) into enum values
+func (t *ddlTable) check(toks []sqlToken) {
+	for i := 1; i+1 < len(toks); i++ {
+		if !toks[i].is("in") || !toks[i+1].isPunct("(") {
+			continue
+		}
+
+		var column string
+		for j := i - 1; j >= 0; j-- {
+			if toks[j].kind == tokWord || toks[j].kind == tokIdent {
+				column = unqualified(toks[j])
+				break
+			}
+		}
+
+		c := t.column(column)
+		if c == nil {
+			break
+		}
+
+		end := groupEnd(toks, i+1)
+		for _, value := range toks[i+2 : end] {
+			if value.kind == tokString {
+				c.enum = append(c.enum, value.text)
+			}
+		}
+		return
+	}
+	t.warn("CHECK constraint %s not imported", joinTokens(toks))
+}
+
+// parseColumn parses a column definition: name type [constraints...]
+func parseColumn(elem []sqlToken) *ddlColumn {
+	c := &ddlColumn{name: unqualified(elem[0])}
+
+	i := 1
+	for i < len(elem) {
+		tok := elem[i]
+		switch {
+		case tok.isPunct("("):
+			end := groupEnd(elem, i)
+			if end < 0 {
+				end = len(elem) - 1
+			}
+			for _, arg := range splitTokens(elem[i+1:end], ",") {
+				c.typeArgs = append(c.typeArgs, joinTokens(arg))
+				if len(arg) == 1 && arg[0].kind == tokString {
+					c.enum = append(c.enum, arg[0].text)
+				}
+			}
+			i = end + 1
+			continue
+		case tok.isPunct("[]"), tok.isPunct("["):
+			c.array = true
+		case tok.kind == tokWord && !isColumnConstraint(tok, len(c.typeWords) > 0):
+			c.typeWords = append(c.typeWords, strings.ToLower(tok.text))
+		default:
+			parseColumnConstraints(c, elem[i:])
+			return c
+		}
+		i++
+	}
+
+	return c
+}
+
+func isColumnConstraint(tok sqlToken, hasType bool) bool {
+	switch strings.ToLower(tok.text) {
+	case "not", "null", "default", "primary", "unique", "references", "check", "constraint",
+		"collate", "generated", "auto_increment", "autoincrement", "identity", "on", "comment", "as",
+		"charset", "key":
+		return true
+	case "character":
+		// CHARACTER starts a type but also MySQL's CHARACTER SET clause
+		return hasType
+	}
+	return false
+}
+
+func parseColumnConstraints(c *ddlColumn, toks []sqlToken) {
+	for i := 0; i < len(toks); {
+		tok := toks[i]
+		switch {
+		case tok.is("constraint"), tok.is("collate"), tok.is("comment"), tok.is("charset"):
+			i += 2
+		case tok.is("character"):
+			i += 3
+		case tok.is("not") && i+1 < len(toks) && toks[i+1].is("null"):
+			c.notNull = true
+			i += 2
+		case tok.is("primary"):
+			c.primary = true
+			i = skipWords(toks, i+1, "key")
+		case tok.is("unique"):
+			c.unique = true
+			i = skipWords(toks, i+1, "key")
+		case tok.is("default"):
+			c.defaultSQL, i = expression(toks, i+1)
+		case tok.is("references"):
+			i = parseReferences(c, toks, i)
+		case tok.is("on") && i+1 < len(toks) && toks[i+1].is("update"):
+			// MySQL ON UPDATE CURRENT_TIMESTAMP
+			_, i = expression(toks, i+2)
+		case tok.is("check") && i+1 < len(toks) && toks[i+1].isPunct("("):
+			end := groupEnd(toks, i+1)
+			if end < 0 {
+				return
+			}
+			t := &ddlTable{columns: []*ddlColumn{c}}
+			t.check(toks[i+1 : end+1])
+			i = end + 1
+		case tok.is("generated"):
+			i = skipWords(toks, i+1, "always", "by", "default", "as")
+			if i < len(toks) && toks[i].isPunct("(") {
+				c.generated = true
+			}
+		case tok.is("as") && i+1 < len(toks) && toks[i+1].isPunct("("):
+			c.generated = true
+			i++
+		case tok.isPunct("("):
+			end := groupEnd(toks, i)
+			if end < 0 {
+				return
+			}
+			i = end + 1
+		default:
+			i++
+		}
+	}
+}
+
+// parseReferences parses REFERENCES table [(column)] [ON DELETE action]
+// starting at the REFERENCES keyword and returns the index after it
+func parseReferences(c *ddlColumn, toks []sqlToken, i int) int {
+	if i >= len(toks) || !toks[i].is("references") {
+		return i
+	}
+
+	c.references, i = qualifiedName(toks, i+1)
+	if i < len(toks) && toks[i].isPunct("(") {
+		if cols := columnList(toks, i); len(cols) == 1 {
+			c.refColumn = cols[0]
+		}
+		i = groupEnd(toks, i) + 1
+	}
+
+	for i+1 < len(toks) && toks[i].is("on") && (toks[i+1].is("delete") || toks[i+1].is("update")) {
+		isDelete := toks[i+1].is("delete")
+		i += 2
+
+		var action string
+		switch {
+		case i < len(toks) && toks[i].is("cascade"):
+			action = "cascade"
+			i++
+		case i < len(toks) && toks[i].is("restrict"):
+			action = "restrict"
+			i++
+		case i+1 < len(toks) && toks[i].is("set") && toks[i+1].is("null"):
+			action = "setnull"
+			i += 2
+		case i+1 < len(toks):
+			// NO ACTION and SET DEFAULT
+			i += 2
+		}
+
+		if isDelete {
+			c.onDelete = action
+		}
+	}
+
+	return i
+}
+
+// expression returns a single default expression term, such as a literal,
+// a function call, a parenthesised expression or a signed number, along
+// with any trailing casts
+func expression(toks []sqlToken, i int) ([]sqlToken, int) {
+	start := i
+	if i < len(toks) && (toks[i].isPunct("-") || toks[i].isPunct("+")) {
+		i++
+	}
+	if i < len(toks) && toks[i].isPunct("(") {
+		i = groupEnd(toks, i)
+	} else if i+1 < len(toks) && toks[i+1].isPunct("(") {
+		i = groupEnd(toks, i+1)
+	}
+	if i < 0 {
+		return toks[start:], len(toks)
+	}
+	i++
+
+	for i < len(toks) && toks[i].isPunct("::") {
+		i++
+		for i < len(toks) && toks[i].kind == tokWord && !isColumnConstraint(toks[i], false) {
+			i++
+		}
+		if i < len(toks) && toks[i].isPunct("[]") {
+			i++
+		}
+	}
+
+	if i > len(toks) {
+		i = len(toks)
+	}
+	return toks[start:i], i
+}
+
+// sqlToken is a lexical token from a DDL file
+type sqlToken struct {
+	kind tokenKind
+	text string
+}
+
+type tokenKind int
+
+const (
+	tokWord   tokenKind = iota // keywords, bare identifiers and numbers
+	tokIdent                   // quoted identifiers
+	tokString                  // string literals
+	tokPunct                   // punctuation and operators
+)
+
+func (t sqlToken) is(keyword string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, keyword)
+}
+
+func (t sqlToken) isPunct(p string) bool {
+	return t.kind == tokPunct && t.text == p
+}
+
+// tokenizeSQL splits src into tokens, dropping comments
+func tokenizeSQL(src string) ([]sqlToken, error) {
+	var toks []sqlToken
+	r := []rune(src)
+
+	for i := 0; i < len(r); {
+		ch := r[i]
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '-' && i+1 < len(r) && r[i+1] == '-', ch == '#':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case ch == '/' && i+1 < len(r) && r[i+1] == '*':
+			end := strings.Index(string(r[i+2:]), "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += 2 + len([]rune(string(r[i+2:])[:end])) + 2
+		case ch == '\'' || ch == '"' || ch == '`':
+			text, next, err := quoted(r, i, ch)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokIdent
+			if ch == '\'' {
+				kind = tokString
+			}
+			toks = append(toks, sqlToken{kind: kind, text: text})
+			i = next
+		case ch == '[':
+			j := i + 1
+			for j < len(r) && unicode.IsSpace(r[j]) {
+				j++
+			}
+			if j < len(r) && r[j] == ']' {
+				toks = append(toks, sqlToken{kind: tokPunct, text: "[]"})
+				i = j + 1
+				continue
+			}
+			text, next, err := quoted(r, i, ']')
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, sqlToken{kind: tokIdent, text: text})
+			i = next
+		case ch == '$' && dollarTag(r, i) != "":
+			tag := dollarTag(r, i)
+			rest := string(r[i+len(tag):])
+			end := strings.Index(rest, tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string")
+			}
+			toks = append(toks, sqlToken{kind: tokString, text: rest[:end]})
+			i += len(tag) + len([]rune(rest[:end])) + len(tag)
+		case isWordRune(ch):
+			j := i
+			for j < len(r) && (isWordRune(r[j]) || (r[j] == '.' && j+1 < len(r) && isWordRune(r[j+1]))) {
+				j++
+			}
+			toks = append(toks, sqlToken{kind: tokWord, text: string(r[i:j])})
+			i = j
+		case ch == ':' && i+1 < len(r) && r[i+1] == ':':
+			toks = append(toks, sqlToken{kind: tokPunct, text: "::"})
+			i += 2
+		default:
+			toks = append(toks, sqlToken{kind: tokPunct, text: string(ch)})
+			i++
+		}
+	}
+
+	return toks, nil
+}
+
+// quoted reads a quoted string or identifier starting at r[i]. A doubled
+// closing quote is an escaped quote.
+func quoted(r []rune, i int, closing rune) (string, int, error) {
+	var b strings.Builder
+	for j := i + 1; j < len(r); j++ {
+		if r[j] != closing {
+			b.WriteRune(r[j])
+			continue
+		}
+		if j+1 < len(r) && r[j+1] == closing && closing != ']' {
+			b.WriteRune(closing)
+			j++
+			continue
+		}
+		return b.String(), j + 1, nil
+	}
+	return "", 0, fmt.Errorf("unterminated quoted text starting with %c", r[i])
+}
+
+// dollarTag returns the Postgres dollar quote ($$ or $tag$) at r[i], if any
+func dollarTag(r []rune, i int) string {
+	for j := i + 1; j < len(r); j++ {
+		if r[j] == '$' {
+			return string(r[i : j+1])
+		}
+		if !unicode.IsLetter(r[j]) && r[j] != '_' {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isWordRune(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_' || ch == '$'
+}
+
+// splitTokens splits toks on a punctuation token outside parentheses
+func splitTokens(toks []sqlToken, sep string) [][]sqlToken {
+	var parts [][]sqlToken
+	depth, start := 0, 0
+	for i, tok := range toks {
+		switch {
+		case tok.isPunct("("):
+			depth++
+		case tok.isPunct(")"):
+			depth--
+		case tok.isPunct(sep) && depth == 0:
+			parts = append(parts, toks[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(toks) {
+		parts = append(parts, toks[start:])
+	}
+	return parts
+}
+
+// groupEnd returns the index of the parenthesis closing the one at toks[i],
+// or -1 if it is unbalanced
+func groupEnd(toks []sqlToken, i int) int {
+	depth := 0
+	for j := i; j < len(toks); j++ {
+		switch {
+		case toks[j].isPunct("("):
+			depth++
+		case toks[j].isPunct(")"):
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+func nextPunct(toks []sqlToken, i int, p string) int {
+	for j := i; j < len(toks); j++ {
+		if toks[j].isPunct(p) {
+			return j
+		}
+	}
+	return -1
+}
+
+// columnList returns the column names in the parenthesised list at toks[i],
+// ignoring sort orders and prefix lengths
+func columnList(toks []sqlToken, i int) []string {
+	end := groupEnd(toks, i)
+	if end < 0 {
+		return nil
+	}
+
+	var cols []string
+	for _, part := range splitTokens(toks[i+1:end], ",") {
+		if len(part) > 0 && (part[0].kind == tokWord || part[0].kind == tokIdent) {
+			cols = append(cols, unqualified(part[0]))
+		}
+	}
+	return cols
+}
+
+// qualifiedName reads a possibly schema-qualified name starting at toks[i]
+// and returns its last part
+func qualifiedName(toks []sqlToken, i int) (string, int) {
+	if i >= len(toks) || (toks[i].kind != tokWord && toks[i].kind != tokIdent) {
+		return "", i
+	}
+
+	name := unqualified(toks[i])
+	i++
+	for i+1 < len(toks) && toks[i].isPunct(".") && (toks[i+1].kind == tokWord || toks[i+1].kind == tokIdent) {
+		name = unqualified(toks[i+1])
+		i += 2
+	}
+	return name, i
+}
+
+// unqualified strips any schema or table prefix from a bare identifier
+func unqualified(tok sqlToken) string {
+	if tok.kind != tokWord {
+		return tok.text
+	}
+	if dot := strings.LastIndex(tok.text, "."); dot >= 0 {
+		return tok.text[dot+1:]
+	}
+	return tok.text
+}
+
+// skipWords skips any of the given keywords starting at toks[i]
+func skipWords(toks []sqlToken, i int, words ...string) int {
+	for i < len(toks) {
+		matched := false
+		for _, w := range words {
+			if toks[i].is(w) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			break
+		}
+		i++
+	}
+	return i
+}
+
+func joinTokens(toks []sqlToken) string {
+	parts := make([]string, len(toks))
+	for i, tok := range toks {
+		if tok.kind == tokString {
+			parts[i] = "'" + strings.ReplaceAll(tok.text, "'", "''") + "'"
+		} else {
+			parts[i] = tok.text
+		}
+	}
+	return strings.Join(parts, " ")
+}
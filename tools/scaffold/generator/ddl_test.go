@@ -0,0 +1,204 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDDL(t *testing.T) {
+	tests := []struct {
+		name     string
+		ddl      string
+		table    string
+		fields   []string
+		warnings []string
+	}{
+		{
+			name: "postgres dump",
+			ddl: `
+-- Dumped from database version 15
+CREATE TABLE public.users (
+    id bigint NOT NULL,
+    email character varying(255) NOT NULL,
+    bio text,
+    status character varying(20) DEFAULT 'active'::character varying NOT NULL,
+    age integer DEFAULT 0,
+    balance numeric(10,2),
+    active boolean DEFAULT true NOT NULL,
+    settings jsonb,
+    external_id uuid,
+    born_on date,
+    tags text[],
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    deleted_at timestamp with time zone,
+    CONSTRAINT users_status_check CHECK (status IN ('active', 'banned'))
+);
+CREATE SEQUENCE public.users_id_seq;
+ALTER TABLE ONLY public.users ALTER COLUMN id SET DEFAULT nextval('public.users_id_seq'::regclass);
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+CREATE UNIQUE INDEX users_email_key ON public.users USING btree (email);
+`,
+			table: "users",
+			fields: []string{
+				"email:string:required,max=255,unique",
+				"bio:text",
+				"status:enum:required,oneof=active|banned,default=active",
+				"age:int:default=0",
+				"balance:float",
+				"active:bool:required,default=true",
+				"settings:json",
+				"external_id:uuid",
+				"born_on:time",
+			},
+			warnings: []string{"users: column tags has unsupported type text[]; skipped"},
+		},
+		{
+			name: "mysql",
+			ddl: "CREATE TABLE `categories` (\n" +
+				"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `name` varchar(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL,\n" +
+				"  `kind` enum('a','b') NOT NULL DEFAULT 'a',\n" +
+				"  `parent_id` bigint unsigned DEFAULT NULL,\n" +
+				"  `visible` tinyint(1) NOT NULL DEFAULT '1',\n" +
+				"  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+				"  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+				"  `deleted_at` timestamp NULL,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `uq_name` (`name`),\n" +
+				"  KEY `idx_parent` (`parent_id`),\n" +
+				"  CONSTRAINT `fk_parent` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`) ON DELETE SET NULL\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+			table: "categories",
+			fields: []string{
+				"name:string:required,max=100,unique",
+				"kind:enum:required,oneof=a|b,default=a",
+				"parent_id:ref:index,ref=categories,ondelete=setnull",
+				"visible:bool:required,default=true",
+			},
+		},
+		{
+			name: "sqlite with inline references",
+			ddl: `
+CREATE TABLE IF NOT EXISTS "order_items" (
+    "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+    "order_id" INTEGER NOT NULL REFERENCES "orders" ("id") ON DELETE CASCADE,
+    "sku" VARCHAR(32) NOT NULL UNIQUE, /* stock keeping unit; unique */
+    "note" TEXT DEFAULT 'gift wrap'
+);`,
+			table: "order_items",
+			fields: []string{
+				"order_id:ref:required,ref=orders,ondelete=cascade",
+				"sku:string:required,max=32,unique",
+				"note:text",
+			},
+			warnings: []string{
+				"order_items: missing created_at, updated_at, deleted_at; generated repositories expect these columns",
+				"order_items: column note has default 'gift wrap' that cannot be imported; skipped",
+			},
+		},
+		{
+			name: "non id primary key",
+			ddl: `CREATE TABLE countries (
+    code char(2) PRIMARY KEY,
+    name varchar(64) NOT NULL,
+    created_at timestamp, updated_at timestamp, deleted_at timestamp
+);`,
+			table: "countries",
+			fields: []string{
+				"code:string:required,max=2,unique",
+				"name:string:required,max=64",
+			},
+			warnings: []string{"countries: primary key (code) is not a single id column; generated code assumes an id primary key"},
+		},
+		{
+			name: "foreign key to non id column",
+			ddl: `CREATE TABLE addresses (
+    id serial PRIMARY KEY,
+    country_code char(2) REFERENCES countries (code),
+    created_at timestamp, updated_at timestamp, deleted_at timestamp
+);`,
+			table:    "addresses",
+			fields:   []string{"country_code:string:max=2"},
+			warnings: []string{"addresses: column country_code references countries but is not an integer; imported without the foreign key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ParseDDL(tt.ddl)
+			if err != nil {
+				t.Fatalf("ParseDDL failed: %v", err)
+			}
+
+			if len(schema.Resources) != 1 {
+				t.Fatalf("Expected 1 resource, got %d", len(schema.Resources))
+			}
+			res := schema.Resources[0]
+
+			if res.TableName() != tt.table {
+				t.Errorf("Expected table %s, got %s", tt.table, res.TableName())
+			}
+
+			var fields []string
+			for _, f := range res.Fields {
+				fields = append(fields, f.Definition())
+			}
+			if strings.Join(fields, " ") != strings.Join(tt.fields, " ") {
+				t.Errorf("Expected fields:\n%s\ngot:\n%s", strings.Join(tt.fields, "\n"), strings.Join(fields, "\n"))
+			}
+
+			if strings.Join(schema.Warnings, "\n") != strings.Join(tt.warnings, "\n") {
+				t.Errorf("Expected warnings:\n%s\ngot:\n%s", strings.Join(tt.warnings, "\n"), strings.Join(schema.Warnings, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseDDLTableNames(t *testing.T) {
+	schema, err := ParseDDL(`
+CREATE TABLE people (id int PRIMARY KEY, name text, created_at timestamp, updated_at timestamp, deleted_at timestamp);
+CREATE TABLE "addresses" (id int PRIMARY KEY, street text, created_at timestamp, updated_at timestamp, deleted_at timestamp);
+CREATE FUNCTION touch() RETURNS trigger AS $$ BEGIN NEW.updated_at = now(); RETURN NEW; END; $$ LANGUAGE plpgsql;
+`)
+	if err != nil {
+		t.Fatalf("ParseDDL failed: %v", err)
+	}
+
+	if len(schema.Resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(schema.Resources))
+	}
+
+	// "people" does not pluralize back from "person", so the table is kept
+	people := schema.Resources[0]
+	if people.Name != "People" || people.Table != "people" {
+		t.Errorf("Expected People resource for people table, got %s (%s)", people.Name, people.Table)
+	}
+
+	address := schema.Resources[1]
+	if address.Name != "Address" || address.Table != "" || address.TableName() != "addresses" {
+		t.Errorf("Expected Address resource for addresses table, got %s (%s)", address.Name, address.TableName())
+	}
+}
+
+func TestParseDDLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+		want string
+	}{
+		{"no tables", "CREATE INDEX idx ON users (email);", "no CREATE TABLE statements"},
+		{"unterminated string", "CREATE TABLE users (name text DEFAULT 'x);", "unterminated"},
+		{"unbalanced", "CREATE TABLE users (name varchar(10);", "unbalanced parentheses"},
+		{"duplicate", "CREATE TABLE users (name text); CREATE TABLE users (name text);", "defined more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDDL(tt.ddl)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
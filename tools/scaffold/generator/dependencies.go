@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jwill9999/scaffold-go/pkg/deps"
+)
+
+// templateRoot is the directory template paths are relative to in the
+// version catalog and the embedded template tree
+const templateRoot = "tools/scaffold/templates/"
+
+// Dependencies returns the modules the project's code imports, at the
+// versions pinned by the catalog. Besides the project templates it counts
+// the templates resources are generated from with the enabled transports,
+// and the driver of the project's database.
+func (g *TemplateGenerator) Dependencies() ([]deps.Module, error) {
+	var templates []string
+	for _, tmpl := range g.Templates {
+		templates = append(templates, tmpl)
+	}
+	for _, tmpl := range g.healthFiles() {
+		templates = append(templates, tmpl)
+	}
+	for _, tmpl := range g.securityFiles() {
+		templates = append(templates, tmpl)
+	}
+	for _, t := range resourceTemplates {
+		if t.transport == "" || g.Transports[t.transport] {
+			templates = append(templates, t.template)
+		}
+	}
+	for _, tmpl := range sharedResourceTemplates {
+		templates = append(templates, tmpl)
+	}
+	if g.Transports[TransportGraphQL] {
+		templates = append(templates, graphqlTemplates+"loaders.go.tmpl", graphqlTemplates+"resource_resolver.go.tmpl")
+	}
+
+	for i, tmpl := range templates {
+		templates[i] = strings.TrimPrefix(tmpl, templateRoot)
+	}
+	return deps.Resolve(templates, deps.Drivers[g.Database])
+}
+
+// GenerateModFile writes the project's go.mod, requiring the modules
+// returned by Dependencies
+func (g *TemplateGenerator) GenerateModFile() error {
+	modules, err := g.Dependencies()
+	if err != nil {
+		return WithCode(CodeInvalidArguments, err)
+	}
+
+	content, err := deps.ModFile(g.Module, modules, g.Toolchain)
+	if err != nil {
+		return WithCode(CodeInvalidArguments, err)
+	}
+	if err := g.writeOutput("go.mod", content); err != nil {
+		return fmt.Errorf("failed to write go.mod: %w", err)
+	}
+	return nil
+}
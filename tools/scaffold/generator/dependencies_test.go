@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jwill9999/scaffold-go/pkg/deps"
+)
+
+// unrenderedTemplates are kept in the template tree but not generated yet,
+// so the catalog does not list their imports
+var unrenderedTemplates = map[string]bool{
+	"auth/jwt.go.tmpl":           true,
+	"cache/interface.go.tmpl":    true,
+	"cache/redis.go.tmpl":        true,
+	"database/postgres.go.tmpl":  true,
+	"metrics.go.tmpl":            true,
+	"metrics/prometheus.go.tmpl": true,
+	"migration.go.tmpl":          true,
+	"server.go.tmpl":             true,
+	"test.go.tmpl":               true,
+	"tracing/jaeger.go.tmpl":     true,
+}
+
+// importSpec matches a line of an import block naming a third-party package
+var importSpec = regexp.MustCompile(`^\s*(?:[\w.]+\s+)?"([a-z0-9-]+\.[a-z.]+/[^"{}]+)"`)
+
+// TestCatalogCoversTemplates checks every third-party import of a generated
+// template is provided by a module the catalog lists for it
+func TestCatalogCoversTemplates(t *testing.T) {
+	root := filepath.Join("..", "templates")
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go.tmpl") {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if unrenderedTemplates[rel] {
+			return nil
+		}
+
+		// #nosec G304 - path is a template in the repository
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			match := importSpec.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			modules := deps.Templates[rel]
+			if rel == "database.go.tmpl" {
+				for _, driver := range deps.Drivers {
+					modules = append(modules, driver)
+				}
+			}
+			if !provides(modules, match[1]) {
+				t.Errorf("%s imports %s, but the catalog does not list its module for the template", rel, match[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk templates: %v", err)
+	}
+}
+
+// provides reports whether one of modules contains the package path
+func provides(modules []string, pkg string) bool {
+	for _, m := range modules {
+		if pkg == m || strings.HasPrefix(pkg, m+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDependencies(t *testing.T) {
+	tests := []struct {
+		name       string
+		features   []string
+		router     string
+		logger     string
+		transports []string
+		want       []string
+	}{
+		{
+			name: "Defaults",
+			want: []string{deps.Gin, deps.Validator, deps.SQLX, deps.PQ, deps.Viper},
+		},
+		{
+			name:       "Every transport",
+			features:   []string{"auth"},
+			router:     RouterChi,
+			logger:     LoggerZap,
+			transports: []string{TransportHTTP, TransportGRPC, TransportGraphQL},
+			want: []string{
+				deps.Chi, deps.Validator, deps.Dataloader, deps.GraphQL, deps.SQLX,
+				deps.PQ, deps.Viper, deps.Zap, deps.ZapExp, deps.GRPC, deps.Protobuf,
+			},
+		},
+		{
+			name:       "gRPC only with zerolog",
+			router:     RouterStdlib,
+			logger:     LoggerZerolog,
+			transports: []string{TransportGRPC},
+			want:       []string{deps.Validator, deps.SQLX, deps.PQ, deps.Zerolog, deps.Viper, deps.GRPC, deps.Protobuf},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewTemplateGenerator("app", "example.com/app", tt.features, nil)
+			if tt.transports != nil {
+				g.SetTransports(tt.transports)
+			}
+			if err := g.SetRouter(tt.router); err != nil {
+				t.Fatalf("SetRouter failed: %v", err)
+			}
+			if err := g.SetLogger(tt.logger); err != nil {
+				t.Fatalf("SetLogger failed: %v", err)
+			}
+
+			modules, err := g.Dependencies()
+			if err != nil {
+				t.Fatalf("Dependencies() error = %v", err)
+			}
+			var got []string
+			for _, m := range modules {
+				if m.Version != deps.Versions[m.Path] {
+					t.Errorf("%s pinned at %s, want %s", m.Path, m.Version, deps.Versions[m.Path])
+				}
+				got = append(got, m.Path)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Dependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Supported deployment targets
+const (
+	DeploymentDocker     = "docker"
+	DeploymentKubernetes = "kubernetes"
+	DeploymentHelm       = "helm"
+)
+
+// deploymentAliases maps alternative spellings to deployment targets
+var deploymentAliases = map[string]string{
+	"k8s": DeploymentKubernetes,
+}
+
+// kubernetesTemplates are the kustomize base and overlays of a project
+// deployed to Kubernetes
+var kubernetesTemplates = map[string]string{
+	"deploy/k8s/base/kustomization.yaml":          "tools/scaffold/templates/k8s/base/kustomization.yaml.tmpl",
+	"deploy/k8s/base/configmap.yaml":              "tools/scaffold/templates/k8s/base/configmap.yaml.tmpl",
+	"deploy/k8s/base/secret.yaml":                 "tools/scaffold/templates/k8s/base/secret.yaml.tmpl",
+	"deploy/k8s/base/deployment.yaml":             "tools/scaffold/templates/k8s/base/deployment.yaml.tmpl",
+	"deploy/k8s/base/service.yaml":                "tools/scaffold/templates/k8s/base/service.yaml.tmpl",
+	"deploy/k8s/base/hpa.yaml":                    "tools/scaffold/templates/k8s/base/hpa.yaml.tmpl",
+	"deploy/k8s/base/pdb.yaml":                    "tools/scaffold/templates/k8s/base/pdb.yaml.tmpl",
+	"deploy/k8s/base/ingress.yaml":                "tools/scaffold/templates/k8s/base/ingress.yaml.tmpl",
+	"deploy/k8s/overlays/dev/kustomization.yaml":  "tools/scaffold/templates/k8s/overlays/dev/kustomization.yaml.tmpl",
+	"deploy/k8s/overlays/prod/kustomization.yaml": "tools/scaffold/templates/k8s/overlays/prod/kustomization.yaml.tmpl",
+}
+
+// helmTemplateDir holds the Helm chart templates. Helm evaluates {{ }}
+// actions itself, so templates in it are rendered with [[ ]] delimiters.
+const helmTemplateDir = "tools/scaffold/templates/helm/"
+
+// helmTemplates are the files of a project's Helm chart, relative to the
+// chart directory
+var helmTemplates = map[string]string{
+	"Chart.yaml":                "tools/scaffold/templates/helm/Chart.yaml.tmpl",
+	"values.yaml":               "tools/scaffold/templates/helm/values.yaml.tmpl",
+	"values.schema.json":        "tools/scaffold/templates/helm/values.schema.json.tmpl",
+	".helmignore":               "tools/scaffold/templates/helm/helmignore.tmpl",
+	"templates/_helpers.tpl":    "tools/scaffold/templates/helm/templates/_helpers.tpl.tmpl",
+	"templates/NOTES.txt":       "tools/scaffold/templates/helm/templates/NOTES.txt.tmpl",
+	"templates/configmap.yaml":  "tools/scaffold/templates/helm/templates/configmap.yaml.tmpl",
+	"templates/deployment.yaml": "tools/scaffold/templates/helm/templates/deployment.yaml.tmpl",
+	"templates/service.yaml":    "tools/scaffold/templates/helm/templates/service.yaml.tmpl",
+	"templates/hpa.yaml":        "tools/scaffold/templates/helm/templates/hpa.yaml.tmpl",
+	"templates/pdb.yaml":        "tools/scaffold/templates/helm/templates/pdb.yaml.tmpl",
+	"templates/ingress.yaml":    "tools/scaffold/templates/helm/templates/ingress.yaml.tmpl",
+}
+
+// helmSecretTemplate holds the credentials of a database server or Redis
+const helmSecretTemplate = "tools/scaffold/templates/helm/templates/secret.yaml.tmpl"
+
+// helmServiceMonitorTemplate lets the Prometheus operator scrape a project
+// with the metrics feature
+const helmServiceMonitorTemplate = "tools/scaffold/templates/helm/templates/servicemonitor.yaml.tmpl"
+
+// deploymentTargets lists the supported deployment targets. Docker files
+// are part of every project.
+var deploymentTargets = map[string]bool{
+	DeploymentDocker:     true,
+	DeploymentKubernetes: true,
+	DeploymentHelm:       true,
+}
+
+// ParseDeployment validates a deployment target. An empty name selects
+// docker.
+func ParseDeployment(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DeploymentDocker, nil
+	}
+	if alias, ok := deploymentAliases[name]; ok {
+		name = alias
+	}
+	if !deploymentTargets[name] {
+		return "", fmt.Errorf("unsupported deployment %q (supported: docker, kubernetes, helm)", name)
+	}
+	return name, nil
+}
+
+// SetDeployment selects the deployment target. Its files are generated
+// with the rest of the project.
+func (g *TemplateGenerator) SetDeployment(name string) error {
+	deployment, err := ParseDeployment(name)
+	if err != nil {
+		return err
+	}
+
+	g.Deployment = deployment
+	return nil
+}
+
+// deploymentFiles returns the project files of the deployment target,
+// which depend on the database and features selected
+func (g *TemplateGenerator) deploymentFiles() map[string]string {
+	switch g.Deployment {
+	case DeploymentKubernetes:
+		return kubernetesTemplates
+	case DeploymentHelm:
+		chart := path.Join("deploy/helm", appName(g.ProjectName))
+		files := make(map[string]string, len(helmTemplates)+2)
+		for file, tmpl := range helmTemplates {
+			files[path.Join(chart, file)] = tmpl
+		}
+		if g.Database != "sqlite" || g.Features["cache"] {
+			files[path.Join(chart, "templates/secret.yaml")] = helmSecretTemplate
+		}
+		if g.Features["metrics"] {
+			files[path.Join(chart, "templates/servicemonitor.yaml")] = helmServiceMonitorTemplate
+		}
+		return files
+	default:
+		return nil
+	}
+}
+
+// appName returns the project name as a DNS label, as Kubernetes object
+// names and image names require
+func appName(project string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(project)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+
+	name := b.String()
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "app"
+	}
+	return name
+}
@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDeployment(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", DeploymentDocker, false},
+		{"docker", DeploymentDocker, false},
+		{"Kubernetes", DeploymentKubernetes, false},
+		{" k8s ", DeploymentKubernetes, false},
+		{"nomad", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDeployment(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAppName(t *testing.T) {
+	tests := []struct {
+		project string
+		want    string
+	}{
+		{"shop", "shop"},
+		{"/tmp/build/My_Shop.API", "my-shop-api"},
+		{"--", "app"},
+		{strings.Repeat("a", 70), strings.Repeat("a", 63)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.project, func(t *testing.T) {
+			if got := appName(tt.project); got != tt.want {
+				t.Errorf("appName(%q) = %q, want %q", tt.project, got, tt.want)
+			}
+		})
+	}
+}
+
+// kubeObject is the part of a Kubernetes object the tests inspect
+type kubeObject struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+}
+
+// kustomization is the part of a kustomization.yaml the tests inspect
+type kustomization struct {
+	Resources []string `yaml:"resources"`
+	Patches   []struct {
+		Target struct {
+			Kind string `yaml:"kind"`
+			Name string `yaml:"name"`
+		} `yaml:"target"`
+		Patch string `yaml:"patch"`
+	} `yaml:"patches"`
+}
+
+func TestKubernetesManifests(t *testing.T) {
+	chdirRepoRoot(t)
+
+	for _, database := range []string{"postgres", "mysql", "sqlite"} {
+		t.Run(database, func(t *testing.T) {
+			g := NewTemplateGenerator("/tmp/My Shop", "example.com/shop", []string{"metrics"}, nil)
+			g.Output = make(map[string][]byte)
+			g.Database = database
+			g.SetTransports([]string{TransportHTTP, TransportGRPC})
+			if err := g.SetDeployment("k8s"); err != nil {
+				t.Fatalf("SetDeployment failed: %v", err)
+			}
+			if err := g.Generate(); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+
+			// Every base resource exists and is a single object named
+			// after the project
+			var base kustomization
+			decodeYAML(t, g.Output, "deploy/k8s/base/kustomization.yaml", &base)
+			objects := make(map[string]map[string]interface{})
+			kinds := make(map[string]bool)
+			for _, res := range base.Resources {
+				file := path.Join("deploy/k8s/base", res)
+				var obj kubeObject
+				decodeYAML(t, g.Output, file, &obj)
+				if !strings.HasPrefix(obj.Metadata.Name, "my-shop") {
+					t.Errorf("%s: expected a name derived from the project, got %q", file, obj.Metadata.Name)
+				}
+				var raw map[string]interface{}
+				decodeYAML(t, g.Output, file, &raw)
+				objects[obj.Kind+"/"+obj.Metadata.Name] = raw
+				kinds[obj.Kind] = true
+			}
+			for _, kind := range []string{"Deployment", "Service", "ConfigMap", "Secret", "HorizontalPodAutoscaler", "Ingress", "PodDisruptionBudget"} {
+				if !kinds[kind] {
+					t.Errorf("Expected a %s in the kustomize base", kind)
+				}
+			}
+
+			deployment := objects["Deployment/my-shop"]
+			for _, field := range []string{
+				"spec.template.spec.containers.0.livenessProbe.httpGet.path",
+				"spec.template.spec.containers.0.readinessProbe.httpGet.path",
+				"spec.template.spec.containers.0.resources.limits.memory",
+				"spec.template.spec.containers.0.ports.1.containerPort",
+				"spec.template.metadata.annotations",
+			} {
+				if _, ok := lookup(deployment, strings.Split(field, ".")); !ok {
+					t.Errorf("Expected the deployment to set %s", field)
+				}
+			}
+
+			_, hasDB := lookup(objects["ConfigMap/my-shop-config"], []string{"data", "DB_HOST"})
+			if hasDB != (database != "sqlite") {
+				t.Errorf("Expected DB_HOST only for a database server, got %v", hasDB)
+			}
+
+			// Overlays build on the base and patch paths that exist
+			for _, overlay := range []string{"dev", "prod"} {
+				var k kustomization
+				decodeYAML(t, g.Output, "deploy/k8s/overlays/"+overlay+"/kustomization.yaml", &k)
+				if len(k.Resources) != 1 || k.Resources[0] != "../../base" {
+					t.Errorf("%s: expected the overlay to build on the base, got %v", overlay, k.Resources)
+				}
+				for _, p := range k.Patches {
+					target := objects[p.Target.Kind+"/"+p.Target.Name]
+					if target == nil {
+						t.Errorf("%s: patch targets unknown %s/%s", overlay, p.Target.Kind, p.Target.Name)
+						continue
+					}
+					checkPatch(t, overlay, target, p.Patch)
+				}
+			}
+		})
+	}
+}
+
+// decodeYAML decodes the generated file into v
+func decodeYAML(t *testing.T, files map[string][]byte, file string, v interface{}) {
+	t.Helper()
+
+	content, ok := files[file]
+	if !ok {
+		t.Fatalf("Expected %s to be generated", file)
+	}
+	if err := yaml.Unmarshal(content, v); err != nil {
+		t.Fatalf("%s is not valid YAML: %v\n%s", file, err, content)
+	}
+}
+
+// checkPatch verifies that each replace and remove operation of a JSON
+// patch addresses an existing field and each add has an existing parent
+func checkPatch(t *testing.T, overlay string, target map[string]interface{}, patch string) {
+	t.Helper()
+
+	var ops []struct {
+		Op   string `yaml:"op"`
+		Path string `yaml:"path"`
+	}
+	if err := yaml.Unmarshal([]byte(patch), &ops); err != nil {
+		t.Fatalf("%s: invalid patch: %v", overlay, err)
+	}
+	for _, op := range ops {
+		parts := strings.Split(strings.TrimPrefix(op.Path, "/"), "/")
+		if op.Op == "add" {
+			parts = parts[:len(parts)-1]
+		}
+		if _, ok := lookup(target, parts); !ok {
+			t.Errorf("%s: %s %s does not address an existing field", overlay, op.Op, op.Path)
+		}
+	}
+}
+
+// lookup returns the value at the path of map keys and list indexes in v
+func lookup(v interface{}, parts []string) (interface{}, bool) {
+	for _, part := range parts {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,626 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Statement is a single SQL statement in a migration. Block statements
+// contain embedded semicolons (functions, triggers) and must be executed
+// as one unit by the migration tool.
+type Statement struct {
+	SQL   string
+	Block bool
+}
+
+// Dialect captures the differences between the SQL databases supported by
+// the migration generator
+type Dialect interface {
+	// Name returns the database name used in flags and config (postgres, mysql, sqlite)
+	Name() string
+	// Quote quotes an identifier
+	Quote(ident string) string
+	// PrimaryKey returns the column definition for the surrogate id column
+	PrimaryKey() string
+	// AuditColumns returns the created_at, updated_at and deleted_at column definitions
+	AuditColumns() []string
+	// ColumnType maps a resource field to a column type
+	ColumnType(f Field) string
+	// ColumnCheck returns an optional CHECK constraint expression for the field
+	ColumnCheck(f Field) string
+	// ValidateField rejects a field the database cannot create as defined
+	ValidateField(f Field) error
+	// TableOptions returns options appended after the CREATE TABLE column list
+	TableOptions() string
+	// UniqueConstraint returns a named UNIQUE table constraint for CREATE
+	// TABLE, or "" when the unique index is added with AddUnique instead
+	UniqueConstraint(name, column string) string
+	// CreateIndex returns a CREATE INDEX statement
+	CreateIndex(name, table, column string) string
+	// AfterCreateTable returns statements run after a table is created
+	AfterCreateTable(table string) []Statement
+	// BeforeDropTable returns statements run before a table is dropped
+	BeforeDropTable(table string) []Statement
+	// DropIndex returns a DROP INDEX statement
+	DropIndex(name, table string) string
+	// AddColumn returns an ALTER TABLE statement adding a column definition
+	AddColumn(table, definition string) string
+	// DropColumn returns an ALTER TABLE statement dropping a column
+	DropColumn(table, column string) string
+	// RenameColumn returns an ALTER TABLE statement renaming a column
+	RenameColumn(table, from, to string) string
+	// RenameConstraints returns the statements renaming the constraints and
+	// indexes of a renamed column, so later migrations find them by the
+	// column's new name. from and to differ only in name.
+	RenameConstraints(table string, from, to Field) []Statement
+	// AlterColumn returns the statements changing a column's type,
+	// nullability or default from one field definition to another
+	AlterColumn(table string, from, to Field) ([]Statement, error)
+	// AddUnique returns a statement adding a named unique constraint
+	AddUnique(name, table, column string) string
+	// DropUnique returns a statement dropping a named unique constraint
+	DropUnique(name, table string) string
+	// AddForeignKey returns a statement adding a foreign key table constraint
+	AddForeignKey(table, constraint string) (string, error)
+	// DropForeignKey returns a statement dropping a named foreign key
+	DropForeignKey(name, table string) (string, error)
+}
+
+// DialectFor returns the dialect for the given database type
+func DialectFor(dbType string) (Dialect, error) {
+	switch strings.ToLower(dbType) {
+	case "", "postgres", "postgresql":
+		return postgresDialect{}, nil
+	case "mysql":
+		return mysqlDialect{}, nil
+	case "sqlite", "sqlite3":
+		return sqliteDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", dbType)
+	}
+}
+
+// enumSize returns a VARCHAR size large enough for every enum value
+func enumSize(values []string) int {
+	size := 1
+	for _, v := range values {
+		if len(v) > size {
+			size = len(v)
+		}
+	}
+	return size
+}
+
+// quoteLiteral quotes a SQL string literal
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// enumCheck returns an IN (...) check for enum fields
+func enumCheck(d Dialect, f Field) string {
+	values := make([]string, len(f.Enum))
+	for i, v := range f.Enum {
+		values[i] = quoteLiteral(v)
+	}
+	return fmt.Sprintf("%s IN (%s)", d.Quote(f.Column()), strings.Join(values, ", "))
+}
+
+// defaultValue renders the DEFAULT expression for a field
+func defaultValue(f Field) (string, error) {
+	switch f.Type {
+	case "int", "ref":
+		if _, err := strconv.ParseInt(f.Default, 10, 64); err != nil {
+			return "", fmt.Errorf("invalid integer default %q for field %s", f.Default, f.Name)
+		}
+		return f.Default, nil
+	case "float":
+		if _, err := strconv.ParseFloat(f.Default, 64); err != nil {
+			return "", fmt.Errorf("invalid float default %q for field %s", f.Default, f.Name)
+		}
+		return f.Default, nil
+	case "bool":
+		v, err := strconv.ParseBool(f.Default)
+		if err != nil {
+			return "", fmt.Errorf("invalid bool default %q for field %s", f.Default, f.Name)
+		}
+		return strings.ToUpper(strconv.FormatBool(v)), nil
+	case "time":
+		if strings.EqualFold(f.Default, "now") {
+			return "CURRENT_TIMESTAMP", nil
+		}
+		return quoteLiteral(f.Default), nil
+	case "enum":
+		for _, v := range f.Enum {
+			if v == f.Default {
+				return quoteLiteral(f.Default), nil
+			}
+		}
+		return "", fmt.Errorf("default %q for field %s is not one of %v", f.Default, f.Name, f.Enum)
+	default:
+		return quoteLiteral(f.Default), nil
+	}
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgres" }
+
+func (postgresDialect) Quote(ident string) string { return `"` + ident + `"` }
+
+func (postgresDialect) PrimaryKey() string { return `"id" BIGSERIAL PRIMARY KEY` }
+
+func (postgresDialect) AuditColumns() []string {
+	return []string{
+		`"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+		`"updated_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+		`"deleted_at" TIMESTAMP WITH TIME ZONE`,
+	}
+}
+
+func (postgresDialect) ColumnType(f Field) string {
+	switch f.Type {
+	case "string":
+		if f.Size > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", f.Size)
+		}
+		return "VARCHAR(255)"
+	case "text":
+		return "TEXT"
+	case "int", "ref":
+		return "BIGINT"
+	case "float":
+		return "DOUBLE PRECISION"
+	case "bool":
+		return "BOOLEAN"
+	case "time":
+		return "TIMESTAMP WITH TIME ZONE"
+	case "uuid":
+		return "UUID"
+	case "json":
+		return "JSONB"
+	case "enum":
+		return fmt.Sprintf("VARCHAR(%d)", enumSize(f.Enum))
+	default:
+		return "TEXT"
+	}
+}
+
+func (d postgresDialect) ColumnCheck(f Field) string {
+	if f.Type == "enum" {
+		return enumCheck(d, f)
+	}
+	return ""
+}
+
+func (postgresDialect) ValidateField(Field) error { return nil }
+
+func (postgresDialect) TableOptions() string { return "" }
+
+func (d postgresDialect) UniqueConstraint(name, column string) string {
+	return fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", d.Quote(name), d.Quote(column))
+}
+
+func (d postgresDialect) CreateIndex(name, table, column string) string {
+	return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s);", d.Quote(name), d.Quote(table), d.Quote(column))
+}
+
+func (d postgresDialect) AfterCreateTable(table string) []Statement {
+	return []Statement{
+		{
+			SQL: `CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';`,
+			Block: true,
+		},
+		// CREATE OR REPLACE TRIGGER needs PostgreSQL 14, so the trigger is
+		// dropped first to let the migration run again
+		{SQL: fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s;", d.Quote("update_"+table+"_updated_at"), d.Quote(table))},
+		{
+			SQL: fmt.Sprintf(`CREATE TRIGGER %s
+    BEFORE UPDATE ON %s
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();`, d.Quote("update_"+table+"_updated_at"), d.Quote(table)),
+		},
+	}
+}
+
+// BeforeDropTable drops only the trigger; update_updated_at_column is shared
+// by every table and is left in place for the remaining tables.
+func (d postgresDialect) BeforeDropTable(table string) []Statement {
+	return []Statement{
+		{SQL: fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s;", d.Quote("update_"+table+"_updated_at"), d.Quote(table))},
+	}
+}
+
+func (d postgresDialect) DropIndex(name, _ string) string {
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s;", d.Quote(name))
+}
+
+func (d postgresDialect) AddColumn(table, definition string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", d.Quote(table), definition)
+}
+
+func (d postgresDialect) DropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", d.Quote(table), d.Quote(column))
+}
+
+func (d postgresDialect) RenameColumn(table, from, to string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", d.Quote(table), d.Quote(from), d.Quote(to))
+}
+
+// RenameConstraints renames each constraint in place, including the inline
+// enum check, which carries PostgreSQL's default <table>_<column>_check name
+func (d postgresDialect) RenameConstraints(table string, from, to Field) []Statement {
+	prefix := fmt.Sprintf("ALTER TABLE %s ", d.Quote(table))
+	rename := func(from, to string) Statement {
+		return Statement{SQL: prefix + fmt.Sprintf("RENAME CONSTRAINT %s TO %s;", d.Quote(from), d.Quote(to))}
+	}
+
+	var stmts []Statement
+	if from.Unique {
+		stmts = append(stmts, rename(uniqueName(table, from), uniqueName(table, to)))
+	}
+	if from.References != "" {
+		stmts = append(stmts, rename(foreignKeyName(table, from), foreignKeyName(table, to)))
+	}
+	if indexed(from) {
+		stmts = append(stmts, Statement{SQL: fmt.Sprintf("ALTER INDEX IF EXISTS %s RENAME TO %s;",
+			d.Quote(indexName(table, from.Column())), d.Quote(indexName(table, to.Column())))})
+	}
+	if d.ColumnCheck(from) != "" {
+		stmts = append(stmts, rename(table+"_"+from.Column()+"_check", table+"_"+to.Column()+"_check"))
+	}
+	return stmts
+}
+
+// AlterColumn issues one ALTER COLUMN clause per changed attribute. Inline
+// enum checks use PostgreSQL's default <table>_<column>_check name.
+func (d postgresDialect) AlterColumn(table string, from, to Field) ([]Statement, error) {
+	prefix := fmt.Sprintf("ALTER TABLE %s ", d.Quote(table))
+	column := d.Quote(to.Column())
+	checkName := d.Quote(table + "_" + to.Column() + "_check")
+
+	var stmts []Statement
+
+	if d.ColumnCheck(from) != d.ColumnCheck(to) && d.ColumnCheck(from) != "" {
+		stmts = append(stmts, Statement{SQL: prefix + fmt.Sprintf("DROP CONSTRAINT IF EXISTS %s;", checkName)})
+	}
+
+	if fromType, toType := d.ColumnType(from), d.ColumnType(to); fromType != toType {
+		stmts = append(stmts, Statement{SQL: prefix + fmt.Sprintf("ALTER COLUMN %s TYPE %s USING %s::%s;", column, toType, column, toType)})
+	}
+
+	if from.HasDefault != to.HasDefault || from.Default != to.Default {
+		if to.HasDefault {
+			def, err := defaultValue(to)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, Statement{SQL: prefix + fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s;", column, def)})
+		} else {
+			stmts = append(stmts, Statement{SQL: prefix + fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT;", column)})
+		}
+	}
+
+	if from.Required != to.Required {
+		action := "DROP NOT NULL"
+		if to.Required {
+			action = "SET NOT NULL"
+		}
+		stmts = append(stmts, Statement{SQL: prefix + fmt.Sprintf("ALTER COLUMN %s %s;", column, action)})
+	}
+
+	if check := d.ColumnCheck(to); check != "" && check != d.ColumnCheck(from) {
+		stmts = append(stmts, Statement{SQL: prefix + fmt.Sprintf("ADD CONSTRAINT %s CHECK (%s);", checkName, check)})
+	}
+
+	return stmts, nil
+}
+
+func (d postgresDialect) AddUnique(name, table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s);", d.Quote(table), d.Quote(name), d.Quote(column))
+}
+
+func (d postgresDialect) DropUnique(name, table string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", d.Quote(table), d.Quote(name))
+}
+
+func (d postgresDialect) AddForeignKey(table, constraint string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", d.Quote(table), constraint), nil
+}
+
+func (d postgresDialect) DropForeignKey(name, table string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", d.Quote(table), d.Quote(name)), nil
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string { return "mysql" }
+
+func (mysqlDialect) Quote(ident string) string { return "`" + ident + "`" }
+
+func (mysqlDialect) PrimaryKey() string {
+	return "`id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY"
+}
+
+func (mysqlDialect) AuditColumns() []string {
+	return []string{
+		"`created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+		"`updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)",
+		"`deleted_at` DATETIME(6) NULL",
+	}
+}
+
+func (mysqlDialect) ColumnType(f Field) string {
+	switch f.Type {
+	case "string":
+		if f.Size > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", f.Size)
+		}
+		return "VARCHAR(255)"
+	case "text":
+		return "TEXT"
+	case "int":
+		return "BIGINT"
+	case "ref":
+		// Must match the BIGINT UNSIGNED primary key it references
+		return "BIGINT UNSIGNED"
+	case "float":
+		return "DOUBLE"
+	case "bool":
+		return "BOOLEAN"
+	case "time":
+		return "DATETIME(6)"
+	case "uuid":
+		return "CHAR(36)"
+	case "json":
+		return "JSON"
+	case "enum":
+		values := make([]string, len(f.Enum))
+		for i, v := range f.Enum {
+			values[i] = quoteLiteral(v)
+		}
+		return fmt.Sprintf("ENUM(%s)", strings.Join(values, ", "))
+	default:
+		return "TEXT"
+	}
+}
+
+func (mysqlDialect) ColumnCheck(Field) string { return "" }
+
+// ValidateField rejects what MySQL cannot do with TEXT and JSON columns:
+// indexing them needs a key length, which would make a unique constraint
+// apply to a prefix only, and they take no literal DEFAULT
+func (d mysqlDialect) ValidateField(f Field) error {
+	typ := d.ColumnType(f)
+	if typ != "TEXT" && typ != "JSON" {
+		return nil
+	}
+	if f.Unique || f.Index {
+		return fmt.Errorf("mysql cannot index %s column %s; use a string field with a size instead", typ, f.Column())
+	}
+	if f.HasDefault {
+		return fmt.Errorf("mysql does not allow a default on %s column %s; use a string field with a size or set the value in the application", typ, f.Column())
+	}
+	return nil
+}
+
+func (mysqlDialect) TableOptions() string {
+	return "ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci"
+}
+
+func (d mysqlDialect) UniqueConstraint(name, column string) string {
+	return fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", d.Quote(name), d.Quote(column))
+}
+
+// CreateIndex omits IF NOT EXISTS, which MySQL does not support for indexes
+func (d mysqlDialect) CreateIndex(name, table, column string) string {
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);", d.Quote(name), d.Quote(table), d.Quote(column))
+}
+
+func (mysqlDialect) AfterCreateTable(string) []Statement { return nil }
+
+func (mysqlDialect) BeforeDropTable(string) []Statement { return nil }
+
+func (d mysqlDialect) DropIndex(name, table string) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s;", d.Quote(name), d.Quote(table))
+}
+
+func (d mysqlDialect) AddColumn(table, definition string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", d.Quote(table), definition)
+}
+
+func (d mysqlDialect) DropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", d.Quote(table), d.Quote(column))
+}
+
+func (d mysqlDialect) RenameColumn(table, from, to string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", d.Quote(table), d.Quote(from), d.Quote(to))
+}
+
+// RenameConstraints renames indexes in place. MySQL cannot rename a foreign
+// key, so it is dropped and added again under the new name.
+func (d mysqlDialect) RenameConstraints(table string, from, to Field) []Statement {
+	prefix := fmt.Sprintf("ALTER TABLE %s ", d.Quote(table))
+	renameIndex := func(from, to string) Statement {
+		return Statement{SQL: prefix + fmt.Sprintf("RENAME INDEX %s TO %s;", d.Quote(from), d.Quote(to))}
+	}
+
+	var stmts []Statement
+	if from.References != "" {
+		stmts = append(stmts, Statement{SQL: prefix + fmt.Sprintf("DROP FOREIGN KEY %s;", d.Quote(foreignKeyName(table, from)))})
+	}
+	if from.Unique {
+		stmts = append(stmts, renameIndex(uniqueName(table, from), uniqueName(table, to)))
+	}
+	if indexed(from) {
+		stmts = append(stmts, renameIndex(indexName(table, from.Column()), indexName(table, to.Column())))
+	}
+	if from.References != "" {
+		stmts = append(stmts, Statement{SQL: prefix + "ADD " + foreignKeyConstraint(d, table, to) + ";"})
+	}
+	return stmts
+}
+
+// AlterColumn restates the full column definition with MODIFY COLUMN, which
+// covers type, nullability and default in one statement
+func (d mysqlDialect) AlterColumn(table string, _, to Field) ([]Statement, error) {
+	def, err := columnDefinition(d, to)
+	if err != nil {
+		return nil, err
+	}
+	return []Statement{{SQL: fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;", d.Quote(table), def)}}, nil
+}
+
+func (d mysqlDialect) AddUnique(name, table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s);", d.Quote(table), d.Quote(name), d.Quote(column))
+}
+
+// DropUnique drops the index backing the constraint; MySQL has no DROP CONSTRAINT for unique keys before 8.0.19
+func (d mysqlDialect) DropUnique(name, table string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;", d.Quote(table), d.Quote(name))
+}
+
+func (d mysqlDialect) AddForeignKey(table, constraint string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", d.Quote(table), constraint), nil
+}
+
+func (d mysqlDialect) DropForeignKey(name, table string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", d.Quote(table), d.Quote(name)), nil
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string { return "sqlite" }
+
+func (sqliteDialect) Quote(ident string) string { return `"` + ident + `"` }
+
+func (sqliteDialect) PrimaryKey() string { return `"id" INTEGER PRIMARY KEY AUTOINCREMENT` }
+
+func (sqliteDialect) AuditColumns() []string {
+	return []string{
+		`"created_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+		`"updated_at" DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP`,
+		`"deleted_at" DATETIME`,
+	}
+}
+
+func (sqliteDialect) ColumnType(f Field) string {
+	switch f.Type {
+	case "int", "ref":
+		return "INTEGER"
+	case "float":
+		return "REAL"
+	case "bool":
+		return "BOOLEAN"
+	case "time":
+		return "DATETIME"
+	default:
+		// SQLite stores strings, enums, uuids and json documents as TEXT
+		return "TEXT"
+	}
+}
+
+func (d sqliteDialect) ColumnCheck(f Field) string {
+	if f.Type == "enum" {
+		return enumCheck(d, f)
+	}
+	return ""
+}
+
+func (sqliteDialect) ValidateField(Field) error { return nil }
+
+func (sqliteDialect) TableOptions() string { return "" }
+
+// UniqueConstraint returns "": SQLite backs a UNIQUE table constraint with
+// an unnamed sqlite_autoindex that cannot be dropped, which would block
+// dropping the column later, so a named unique index is created instead
+func (sqliteDialect) UniqueConstraint(string, string) string { return "" }
+
+func (d sqliteDialect) CreateIndex(name, table, column string) string {
+	return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s);", d.Quote(name), d.Quote(table), d.Quote(column))
+}
+
+func (d sqliteDialect) AfterCreateTable(table string) []Statement {
+	return []Statement{
+		{
+			SQL: fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s
+    AFTER UPDATE ON %s
+    FOR EACH ROW
+BEGIN
+    UPDATE %s SET "updated_at" = CURRENT_TIMESTAMP WHERE "id" = OLD."id";
+END;`, d.Quote("update_"+table+"_updated_at"), d.Quote(table), d.Quote(table)),
+			Block: true,
+		},
+	}
+}
+
+func (d sqliteDialect) BeforeDropTable(table string) []Statement {
+	return []Statement{
+		{SQL: fmt.Sprintf("DROP TRIGGER IF EXISTS %s;", d.Quote("update_"+table+"_updated_at"))},
+	}
+}
+
+func (d sqliteDialect) DropIndex(name, _ string) string {
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s;", d.Quote(name))
+}
+
+func (d sqliteDialect) AddColumn(table, definition string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", d.Quote(table), definition)
+}
+
+func (d sqliteDialect) DropColumn(table, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", d.Quote(table), d.Quote(column))
+}
+
+func (d sqliteDialect) RenameColumn(table, from, to string) string {
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", d.Quote(table), d.Quote(from), d.Quote(to))
+}
+
+// RenameConstraints recreates the column's indexes under the new name, as
+// SQLite cannot rename an index. Foreign keys are left alone: RENAME COLUMN
+// rewrites them and SQLite never drops them by name.
+func (d sqliteDialect) RenameConstraints(table string, from, to Field) []Statement {
+	var stmts []Statement
+	if from.Unique {
+		stmts = append(stmts,
+			Statement{SQL: d.DropUnique(uniqueName(table, from), table)},
+			Statement{SQL: d.AddUnique(uniqueName(table, to), table, to.Column())},
+		)
+	}
+	if indexed(from) {
+		stmts = append(stmts,
+			Statement{SQL: d.DropIndex(indexName(table, from.Column()), table)},
+			Statement{SQL: d.CreateIndex(indexName(table, to.Column()), table, to.Column())},
+		)
+	}
+	return stmts
+}
+
+// AlterColumn is not supported: SQLite cannot change a column in place and
+// needs a hand-written table rebuild
+func (sqliteDialect) AlterColumn(table string, _, to Field) ([]Statement, error) {
+	return nil, fmt.Errorf("sqlite cannot alter column %s.%s in place; write a table rebuild migration with `migration create`", table, to.Column())
+}
+
+// AddUnique uses a unique index because SQLite cannot add table constraints
+func (d sqliteDialect) AddUnique(name, table, column string) string {
+	return fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s (%s);", d.Quote(name), d.Quote(table), d.Quote(column))
+}
+
+func (d sqliteDialect) DropUnique(name, _ string) string {
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s;", d.Quote(name))
+}
+
+func (sqliteDialect) AddForeignKey(table, _ string) (string, error) {
+	return "", fmt.Errorf("sqlite cannot add a foreign key to existing table %s; write a table rebuild migration with `migration create`", table)
+}
+
+func (sqliteDialect) DropForeignKey(name, table string) (string, error) {
+	return "", fmt.Errorf("sqlite cannot drop foreign key %s from table %s; write a table rebuild migration with `migration create`", name, table)
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported Docker image profiles. Both build the hardened production
+// stage; the dev profile adds a hot-reloading development stage and makes
+// it the default build target.
+const (
+	DockerProfileDev  = "dev"
+	DockerProfileProd = "prod"
+)
+
+// dockerProfiles lists the supported profiles in the order shown in errors
+var dockerProfiles = []string{DockerProfileDev, DockerProfileProd}
+
+// ParseDockerProfile validates a Docker profile name. An empty name selects
+// the prod profile.
+func ParseDockerProfile(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return DockerProfileProd, nil
+	}
+	if !contains(dockerProfiles, name) {
+		return "", fmt.Errorf("unsupported Docker profile %q (supported: %s)", name, strings.Join(dockerProfiles, ", "))
+	}
+	return name, nil
+}
+
+// SetDockerProfile selects the profile the project's Dockerfile is
+// generated for
+func (g *TemplateGenerator) SetDockerProfile(name string) error {
+	profile, err := ParseDockerProfile(name)
+	if err != nil {
+		return err
+	}
+
+	g.DockerProfile = profile
+	return nil
+}
+
+// cgoEnabled reports whether the project's database driver needs cgo. The
+// SQLite driver wraps the C library; the others are pure Go, so their
+// binaries are static and run on a distroless static image.
+func (g *TemplateGenerator) cgoEnabled() bool {
+	return g.Database == "sqlite" || g.Database == "sqlite3"
+}
@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseDockerProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", DockerProfileProd, false},
+		{"prod", DockerProfileProd, false},
+		{" Dev ", DockerProfileDev, false},
+		{"debug", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDockerProfile(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// dockerStage is a stage of a Dockerfile with its instructions, keyed by
+// instruction name
+type dockerStage struct {
+	name         string
+	from         string
+	instructions map[string][]string
+}
+
+// parseDockerfile splits a Dockerfile into stages, joining continued lines
+func parseDockerfile(content string) []dockerStage {
+	var stages []dockerStage
+	var line string
+	for _, raw := range strings.Split(content, "\n") {
+		raw = strings.TrimSpace(raw)
+		if strings.HasPrefix(raw, "#") {
+			continue
+		}
+		if strings.HasSuffix(raw, "\\") {
+			line += strings.TrimSuffix(raw, "\\")
+			continue
+		}
+		line += raw
+		if line == "" {
+			continue
+		}
+
+		instruction, args, _ := strings.Cut(line, " ")
+		line = ""
+		if instruction == "FROM" {
+			fields := strings.Fields(args)
+			stage := dockerStage{from: fields[0], instructions: make(map[string][]string)}
+			if len(fields) == 3 {
+				stage.name = fields[2]
+			}
+			stages = append(stages, stage)
+			continue
+		}
+		if len(stages) > 0 {
+			last := stages[len(stages)-1]
+			last.instructions[instruction] = append(last.instructions[instruction], args)
+		}
+	}
+	return stages
+}
+
+func TestDockerfile(t *testing.T) {
+	chdirRepoRoot(t)
+
+	tests := []struct {
+		database string
+		profile  string
+		cgo      bool
+	}{
+		{"postgres", DockerProfileProd, false},
+		{"mysql", DockerProfileDev, false},
+		{"sqlite", DockerProfileProd, true},
+		{"sqlite", DockerProfileDev, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.database+"/"+tt.profile, func(t *testing.T) {
+			g := NewTemplateGenerator("shop", "example.com/shop", nil, nil)
+			g.Output = make(map[string][]byte)
+			g.Database = tt.database
+			if err := g.SetDockerProfile(tt.profile); err != nil {
+				t.Fatalf("SetDockerProfile failed: %v", err)
+			}
+			if err := g.Generate(); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+
+			stages := parseDockerfile(string(g.Output["Dockerfile"]))
+			byName := make(map[string]dockerStage)
+			for _, s := range stages {
+				byName[s.name] = s
+			}
+
+			wantLast := "production"
+			if tt.profile == DockerProfileDev {
+				wantLast = "development"
+			}
+			if last := stages[len(stages)-1].name; last != wantLast {
+				t.Errorf("Expected the default target to be %s, got %s", wantLast, last)
+			}
+
+			builder, ok := byName["builder"]
+			if !ok || builder.from != "modules" {
+				t.Fatalf("Expected a builder stage on the module download stage, got %+v", stages)
+			}
+			if copies := byName["modules"].instructions["COPY"]; len(copies) != 1 || copies[0] != "go.* ./" {
+				t.Errorf("Expected the module stage to copy only go.mod and go.sum, if present, got %v", copies)
+			}
+			build := strings.Join(builder.instructions["RUN"], "\n")
+			wantCGO := "CGO_ENABLED=0"
+			if tt.cgo {
+				wantCGO = "CGO_ENABLED=1"
+			}
+			if !strings.Contains(build, wantCGO) {
+				t.Errorf("Expected the build to set %s, got %q", wantCGO, build)
+			}
+			for _, v := range []string{"Version", "Commit", "BuildDate"} {
+				stamp := "-X example.com/shop/internal/handlers." + v + "="
+				if !strings.Contains(build, stamp) {
+					t.Errorf("Expected the build to stamp %s, got %q", v, build)
+				}
+				if !strings.Contains(string(g.Output["internal/handlers/handlers.go"]), "\t"+v+" ") {
+					t.Errorf("Expected handlers.go to declare %s", v)
+				}
+			}
+
+			prod := byName["production"]
+			wantImage := "gcr.io/distroless/static-debian12:nonroot"
+			if tt.cgo {
+				wantImage = "gcr.io/distroless/base-debian12:nonroot"
+			}
+			if prod.from != wantImage {
+				t.Errorf("Expected the production image on %s, got %s", wantImage, prod.from)
+			}
+			if user := prod.instructions["USER"]; len(user) != 1 || strings.HasPrefix(user[0], "root") {
+				t.Errorf("Expected the production image to run as a non-root user, got %v", user)
+			}
+			// The nonroot user cannot write to /app, so the SQLite database
+			// is kept in a data directory it owns
+			wantData := []string{"--from=builder --chown=nonroot:nonroot /out/data /data"}
+			wantEnv := []string{"DB_NAME=/data/shop.db"}
+			if !tt.cgo {
+				wantData, wantEnv = nil, nil
+			}
+			var data []string
+			for _, c := range prod.instructions["COPY"] {
+				if strings.HasSuffix(c, " /data") {
+					data = append(data, c)
+				}
+			}
+			if !slices.Equal(data, wantData) {
+				t.Errorf("Expected the production image to copy the data directory %v, got %v", wantData, data)
+			}
+			if env := prod.instructions["ENV"]; !slices.Equal(env, wantEnv) {
+				t.Errorf("Expected the production environment %v, got %v", wantEnv, env)
+			}
+			if hc := prod.instructions["HEALTHCHECK"]; len(hc) != 1 || !strings.Contains(hc[0], `"healthcheck"`) {
+				t.Errorf("Expected a HEALTHCHECK running the binary, got %v", hc)
+			}
+			if !strings.Contains(string(g.Output["cmd/api/main.go"]), `os.Args[1] == "healthcheck"`) {
+				t.Error("Expected main.go to handle the healthcheck argument")
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// supportedFeatures lists the optional project features. The modules a
+// feature needs come from the templates it renders, not from the feature.
+var supportedFeatures = map[string]bool{
+	"auth":    true,
+	"cache":   true,
+	"metrics": true,
+	"tracing": true,
+}
+
+// ParseFeatures parses a comma separated feature list, dropping empty
+// entries and duplicates
+func ParseFeatures(spec string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(spec, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		switch {
+		case f == "":
+			continue
+		case supportedFeatures[f]:
+			seen[f] = true
+		default:
+			return nil, fmt.Errorf("unsupported feature %q (supported: auth, cache, metrics, tracing)", f)
+		}
+	}
+
+	features := make([]string, 0, len(seen))
+	for f := range seen {
+		features = append(features, f)
+	}
+	sort.Strings(features)
+	return features, nil
+}
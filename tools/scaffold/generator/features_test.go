@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeatures(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []string
+		wantErr bool
+	}{
+		{"", []string{}, false},
+		{"auth", []string{"auth"}, false},
+		{"tracing, METRICS,,metrics", []string{"metrics", "tracing"}, false},
+		{"auth,blockchain", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseFeatures(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
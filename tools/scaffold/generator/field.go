@@ -0,0 +1,319 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field describes a single resource field parsed from a field definition
+// of the form name:type:validation[,validation]
+type Field struct {
+	Name        string
+	Type        string
+	Validations []string
+	Required    bool
+	Unique      bool
+	Index       bool
+	Default     string
+	HasDefault  bool
+	Size        int
+	Enum        []string
+	References  string
+	OnDelete    string
+}
+
+// supportedFieldTypes lists the field types understood by the generator
+var supportedFieldTypes = map[string]bool{
+	"string": true,
+	"text":   true,
+	"int":    true,
+	"float":  true,
+	"bool":   true,
+	"time":   true,
+	"enum":   true,
+	"uuid":   true,
+	"json":   true,
+	"ref":    true,
+}
+
+// fieldNamePattern restricts field names to identifiers that are safe to use
+// as both SQL column names and Go struct field names
+var fieldNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// ParseFields parses a whitespace separated list of field definitions
+func ParseFields(spec string) ([]Field, error) {
+	var fields []Field
+	seen := make(map[string]bool)
+
+	for _, def := range strings.Fields(spec) {
+		field, err := ParseField(def)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[field.Column()] {
+			return nil, fmt.Errorf("duplicate field: %s", field.Name)
+		}
+		seen[field.Column()] = true
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// ParseField parses a single name:type:validation[,validation] definition.
+// Schema modifiers (unique, index, default=, ref=, ondelete=) are extracted
+// from the validation list; the remaining entries are kept as binding rules.
+func ParseField(def string) (Field, error) {
+	parts := strings.SplitN(def, ":", 3)
+	if len(parts) < 2 {
+		return Field{}, fmt.Errorf("invalid field definition %q: expected name:type[:validations]", def)
+	}
+
+	field := Field{
+		Name: parts[0],
+		Type: strings.ToLower(parts[1]),
+	}
+
+	if !fieldNamePattern.MatchString(field.Name) {
+		return Field{}, fmt.Errorf("invalid field name %q", field.Name)
+	}
+
+	if !supportedFieldTypes[field.Type] {
+		return Field{}, fmt.Errorf("unsupported type %q for field %s", field.Type, field.Name)
+	}
+
+	if len(parts) == 3 && parts[2] != "" {
+		for _, rule := range strings.Split(parts[2], ",") {
+			if err := field.applyRule(rule); err != nil {
+				return Field{}, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+	}
+
+	if field.Type == "enum" && len(field.Enum) == 0 {
+		return Field{}, fmt.Errorf("field %s: enum requires oneof=a|b", field.Name)
+	}
+
+	if field.Type == "ref" && field.References == "" {
+		return Field{}, fmt.Errorf("field %s: ref requires ref=<table>", field.Name)
+	}
+
+	if field.OnDelete == "setnull" && field.Required {
+		return Field{}, fmt.Errorf("field %s: ondelete=setnull cannot be used on a required field", field.Name)
+	}
+
+	return field, nil
+}
+
+func (f *Field) applyRule(rule string) error {
+	key, value, hasValue := strings.Cut(rule, "=")
+
+	switch key {
+	case "":
+		return nil
+	case "required":
+		f.Required = true
+	case "unique":
+		f.Unique = true
+		return nil
+	case "index":
+		f.Index = true
+		return nil
+	case "default":
+		f.Default = value
+		f.HasDefault = true
+		return nil
+	case "ref":
+		if !fieldNamePattern.MatchString(value) {
+			return fmt.Errorf("invalid reference %q", value)
+		}
+		f.References = value
+		return nil
+	case "ondelete":
+		switch value {
+		case "cascade", "restrict", "setnull":
+			f.OnDelete = value
+		default:
+			return fmt.Errorf("invalid ondelete action %q", value)
+		}
+		return nil
+	case "oneof":
+		f.Enum = strings.Split(value, "|")
+		// Binding rules separate oneof values with spaces
+		rule = "oneof=" + strings.Join(f.Enum, " ")
+	case "max":
+		if hasValue && (f.Type == "string" || f.Type == "text") {
+			size, err := strconv.Atoi(value)
+			if err != nil || size <= 0 {
+				return fmt.Errorf("invalid max %q", value)
+			}
+			f.Size = size
+		}
+	}
+
+	f.Validations = append(f.Validations, rule)
+	return nil
+}
+
+// Definition returns the field in the name:type:validation[,validation]
+// form accepted by ParseField
+func (f Field) Definition() string {
+	rules := make([]string, 0, len(f.Validations)+5)
+	for _, rule := range f.Validations {
+		if strings.HasPrefix(rule, "oneof=") {
+			rule = "oneof=" + strings.Join(f.Enum, "|")
+		}
+		rules = append(rules, rule)
+	}
+	if f.Unique {
+		rules = append(rules, "unique")
+	}
+	if f.Index {
+		rules = append(rules, "index")
+	}
+	if f.HasDefault {
+		rules = append(rules, "default="+f.Default)
+	}
+	if f.References != "" {
+		rules = append(rules, "ref="+f.References)
+	}
+	if f.OnDelete != "" {
+		rules = append(rules, "ondelete="+f.OnDelete)
+	}
+
+	def := f.Name + ":" + f.Type
+	if len(rules) > 0 {
+		def += ":" + strings.Join(rules, ",")
+	}
+	return def
+}
+
+// Column returns the snake_case column name for the field
+func (f Field) Column() string {
+	return toSnakeCase(f.Name)
+}
+
+// GoName returns the exported Go identifier for the field
+func (f Field) GoName() string {
+	return toPascalCase(f.Name)
+}
+
+// Nullable reports whether the column accepts NULL values
+func (f Field) Nullable() bool {
+	return !f.Required
+}
+
+// toSnakeCase converts CamelCase or mixedCase identifiers to snake_case
+func toSnakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' &&
+				(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// toPascalCase converts snake_case identifiers to PascalCase, keeping
+// common initialisms upper case
+func toPascalCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(toSnakeCase(s), "_") {
+		if part == "" {
+			continue
+		}
+		switch part {
+		case "id", "url", "api", "uuid", "ip", "http", "json", "sql":
+			b.WriteString(strings.ToUpper(part))
+		default:
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+// singularize reverses pluralize for the common English plural forms
+func singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"),
+		strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "ss"), strings.HasSuffix(s, "us"):
+		return s
+	case strings.HasSuffix(s, "s") && len(s) > 1:
+		return s[:len(s)-1]
+	default:
+		return s
+	}
+}
+
+// pluralize returns a naive English plural used for table and route names
+func pluralize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "y") && len(s) > 1 && !strings.ContainsRune("aeiou", rune(s[len(s)-2])):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"),
+		strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	default:
+		return s + "s"
+	}
+}
+
+// GoType returns the Go type used for the field in generated models.
+// Nullable columns use pointers so NULL can be told apart from zero values.
+func (f Field) GoType() string {
+	var typ string
+	switch f.Type {
+	case "int":
+		typ = "int64"
+	case "float":
+		typ = "float64"
+	case "bool":
+		typ = "bool"
+	case "time":
+		typ = "time.Time"
+	case "json":
+		typ = "json.RawMessage"
+	case "ref":
+		typ = "uint"
+	default:
+		typ = "string"
+	}
+
+	if f.Nullable() {
+		return "*" + typ
+	}
+	return typ
+}
+
+// Binding returns the gin binding tag for the field's validations
+func (f Field) Binding() string {
+	rules := make([]string, 0, len(f.Validations)+1)
+	for _, rule := range f.Validations {
+		// A required bool would reject false
+		if rule == "required" && f.Type == "bool" {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) > 0 && f.Nullable() {
+		rules = append([]string{"omitempty"}, rules...)
+	}
+	return strings.Join(rules, ",")
+}
@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     string
+		want    Field
+		wantErr bool
+	}{
+		{
+			name: "Simple string field",
+			def:  "name:string",
+			want: Field{Name: "name", Type: "string"},
+		},
+		{
+			name: "Required string with size and binding rules",
+			def:  "email:string:required,email,max=120,unique",
+			want: Field{
+				Name:        "email",
+				Type:        "string",
+				Validations: []string{"required", "email", "max=120"},
+				Required:    true,
+				Unique:      true,
+				Size:        120,
+			},
+		},
+		{
+			name: "Enum with default",
+			def:  "role:enum:required,oneof=admin|user,default=user",
+			want: Field{
+				Name:        "role",
+				Type:        "enum",
+				Validations: []string{"required", "oneof=admin user"},
+				Required:    true,
+				Default:     "user",
+				HasDefault:  true,
+				Enum:        []string{"admin", "user"},
+			},
+		},
+		{
+			name: "Reference with on delete action",
+			def:  "owner_id:ref:ref=users,ondelete=cascade,index",
+			want: Field{
+				Name:       "owner_id",
+				Type:       "ref",
+				Index:      true,
+				References: "users",
+				OnDelete:   "cascade",
+			},
+		},
+		{
+			name:    "Missing type",
+			def:     "name",
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported type",
+			def:     "name:varchar",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid field name",
+			def:     "na-me:string",
+			wantErr: true,
+		},
+		{
+			name:    "Enum without values",
+			def:     "role:enum:required",
+			wantErr: true,
+		},
+		{
+			name:    "Reference without table",
+			def:     "owner_id:ref",
+			wantErr: true,
+		},
+		{
+			name:    "Set null on required reference",
+			def:     "owner_id:ref:required,ref=users,ondelete=setnull",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseField(tt.def)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseField() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFieldsRejectsDuplicates(t *testing.T) {
+	if _, err := ParseFields("userName:string user_name:string"); err == nil {
+		t.Error("Expected duplicate column error, got nil")
+	}
+}
+
+func TestNamingHelpers(t *testing.T) {
+	tests := []struct {
+		in, snake, pascal string
+	}{
+		{"name", "name", "Name"},
+		{"createdAt", "created_at", "CreatedAt"},
+		{"owner_id", "owner_id", "OwnerID"},
+		{"HTTPStatus", "http_status", "HTTPStatus"},
+	}
+
+	for _, tt := range tests {
+		if got := toSnakeCase(tt.in); got != tt.snake {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+		if got := toPascalCase(tt.in); got != tt.pascal {
+			t.Errorf("toPascalCase(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+	}
+
+	tables := map[string]string{
+		"User":     "users",
+		"Category": "categories",
+		"Address":  "addresses",
+		"BlogPost": "blog_posts",
+	}
+	for name, want := range tables {
+		if got := (Resource{Name: name}).TableName(); got != want {
+			t.Errorf("TableName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenSuffix is appended to snapshot names so generated Go files in
+// testdata are not mistaken for source
+const goldenSuffix = ".golden"
+
+// goldenPreset is a project configuration whose output is snapshotted
+type goldenPreset struct {
+	name       string
+	features   []string
+	database   string
+	router     string
+	logger     string
+	deployment string
+	ci         string
+	docker     string
+	transports []string
+	migrations MigrationSettings
+	resources  [][2]string
+}
+
+var goldenPresets = []goldenPreset{
+	{
+		name: "minimal",
+	},
+	{
+		name:       "auth-postgres",
+		features:   []string{"auth"},
+		database:   "postgres",
+		deployment: DeploymentHelm,
+		docker:     DockerProfileDev,
+		resources: [][2]string{
+			{"User", "email:string:required,email password_hash:string:required role:enum:oneof=member|admin,default=member"},
+			{"Session", "user_id:ref:required,ref=users,ondelete=cascade token:string:required expires_at:time:required"},
+		},
+	},
+	{
+		name:       "full",
+		features:   []string{"auth", "metrics", "tracing"},
+		database:   "mysql",
+		router:     RouterChi,
+		logger:     LoggerZap,
+		deployment: DeploymentKubernetes,
+		ci:         CIGitLab,
+		transports: []string{TransportHTTP, TransportGRPC, TransportGraphQL},
+		migrations: MigrationSettings{Format: MigrationFormatGolangMigrate, Prefix: MigrationPrefixSequence},
+		resources: [][2]string{
+			{"User", "name:string:required email:string:required,email active:bool"},
+			{"BlogPost", "title:string:required author_id:ref:required,ref=users views:int rating:float meta:json published_at:time status:enum:oneof=draft|live,default=draft"},
+		},
+	},
+}
+
+// render generates the preset into an in-memory tree
+func (p goldenPreset) render(t *testing.T) map[string][]byte {
+	t.Helper()
+
+	g := NewTemplateGenerator(p.name, "example.com/"+p.name, p.features, nil)
+	g.Output = make(map[string][]byte)
+	g.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	if p.database != "" {
+		g.Database = p.database
+	}
+	if p.migrations != (MigrationSettings{}) {
+		g.Migrations = p.migrations
+	}
+	if p.transports != nil {
+		g.SetTransports(p.transports)
+	}
+	if err := g.SetRouter(p.router); err != nil {
+		t.Fatalf("SetRouter failed: %v", err)
+	}
+	if err := g.SetLogger(p.logger); err != nil {
+		t.Fatalf("SetLogger failed: %v", err)
+	}
+	if err := g.SetDeployment(p.deployment); err != nil {
+		t.Fatalf("SetDeployment failed: %v", err)
+	}
+	if err := g.SetCI(p.ci); err != nil {
+		t.Fatalf("SetCI failed: %v", err)
+	}
+	if err := g.SetDockerProfile(p.docker); err != nil {
+		t.Fatalf("SetDockerProfile failed: %v", err)
+	}
+
+	for _, r := range p.resources {
+		fields, err := ParseFields(r[1])
+		if err != nil {
+			t.Fatalf("ParseFields(%q) failed: %v", r[1], err)
+		}
+		res, err := NewResource(r[0], fields)
+		if err != nil {
+			t.Fatalf("NewResource(%q) failed: %v", r[0], err)
+		}
+		g.Resources = append(g.Resources, res)
+	}
+
+	if err := g.Generate(); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, res := range g.Resources {
+		if _, err := g.GenerateResource(res); err != nil {
+			t.Fatalf("GenerateResource(%s) failed: %v", res.Name, err)
+		}
+	}
+	return g.Output
+}
+
+func TestGolden(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	goldenDir := filepath.Join(wd, "testdata", "golden")
+	chdirRepoRoot(t)
+
+	for _, preset := range goldenPresets {
+		t.Run(preset.name, func(t *testing.T) {
+			got := preset.render(t)
+			dir := filepath.Join(goldenDir, preset.name)
+
+			if *update {
+				writeGolden(t, dir, got)
+				return
+			}
+
+			want := readGolden(t, dir)
+			for _, file := range sortedKeys(got, want) {
+				gotContent, inGot := got[file]
+				wantContent, inWant := want[file]
+				switch {
+				case !inWant:
+					t.Errorf("%s: unexpected file generated; run go test -update to accept it", file)
+				case !inGot:
+					t.Errorf("%s: expected file was not generated", file)
+				case string(gotContent) != string(wantContent):
+					t.Errorf("%s differs from golden file (-want +got):\n%s", file, lineDiff(string(wantContent), string(gotContent)))
+				}
+			}
+		})
+	}
+}
+
+// chdirRepoRoot runs the test from the repository root, where the template
+// and testdata paths tests read from disk are resolved
+func chdirRepoRoot(t *testing.T) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(filepath.Join(wd, "..", "..", "..")); err != nil {
+		t.Fatalf("Failed to change to repository root: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Errorf("Failed to restore working directory: %v", err)
+		}
+	})
+}
+
+// writeGolden replaces the snapshot in dir with files
+func writeGolden(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("Failed to clear %s: %v", dir, err)
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file)+goldenSuffix)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+}
+
+// readGolden loads the snapshot in dir keyed by generated file path
+func readGolden(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		// #nosec G304 - path is inside the test's golden directory
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[strings.TrimSuffix(filepath.ToSlash(rel), goldenSuffix)] = content
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read golden files (run go test -update to create them): %v", err)
+	}
+	return files
+}
+
+// sortedKeys returns the keys of both maps in order
+func sortedKeys(a, b map[string][]byte) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range []map[string][]byte{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffContext is the number of unchanged lines shown around a change
+const diffContext = 3
+
+// lineDiff returns a unified-style diff of two texts, prefixing removed
+// lines with - and added lines with +
+func lineDiff(want, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+		pos  int
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i], j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i], j + 1})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j], j + 1})
+			j++
+		}
+	}
+
+	// Print changed lines with their context, separating distant hunks
+	var out strings.Builder
+	last := -1
+	for k, l := range lines {
+		near := false
+		for c := max(0, k-diffContext); c <= min(len(lines)-1, k+diffContext); c++ {
+			if lines[c].op != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if last != k-1 {
+			fmt.Fprintf(&out, "@@ line %d @@\n", l.pos)
+		}
+		fmt.Fprintf(&out, "%c %s\n", l.op, l.text)
+		last = k
+	}
+	return out.String()
+}
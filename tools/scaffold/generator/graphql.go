@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const graphqlTemplates = "tools/scaffold/templates/graphql/"
+
+// graphqlProjectTemplates are the GraphQL files created with the project.
+// They hold the HTTP handler and scalar helpers, which do not depend on the
+// resources.
+var graphqlProjectTemplates = map[string]string{
+	"internal/gql/handler.go": graphqlTemplates + "handler.go.tmpl",
+	"internal/gql/scalars.go": graphqlTemplates + "scalars.go.tmpl",
+}
+
+// graphqlData is the template data for the files describing every resource
+type graphqlData struct {
+	Module    string
+	Resources []graphqlResource
+	// Loaded lists the resources fetched through dataloaders because other
+	// resources reference them
+	Loaded []resourceData
+}
+
+// graphqlResource is a resource with the relations resolved from its ref
+// fields
+type graphqlResource struct {
+	resourceData
+	Relations []graphqlRelation
+}
+
+// graphqlRelation exposes the row a ref field points to as an object field
+type graphqlRelation struct {
+	Name   string
+	GoName string
+	Field  Field
+	Target resourceData
+}
+
+// GenerateGraphQL writes the GraphQL schema and resolvers for resources and
+// returns the paths written. Relations between resources are only known
+// once every resource is seen, so all files are rewritten together.
+func (g *TemplateGenerator) GenerateGraphQL(resources []Resource) ([]string, error) {
+	data := graphqlData{Module: g.Module}
+
+	byTable := make(map[string]resourceData, len(resources))
+	for _, res := range resources {
+		byTable[res.TableName()] = g.resourceData(res)
+	}
+
+	loaded := make(map[string]bool)
+	for _, res := range resources {
+		r := graphqlResource{resourceData: g.resourceData(res)}
+		for _, f := range res.Fields {
+			rel, ok := graphqlRelationFor(f, res.Fields, byTable)
+			if !ok {
+				continue
+			}
+			r.Relations = append(r.Relations, rel)
+			if !loaded[rel.Target.Name] {
+				loaded[rel.Target.Name] = true
+				data.Loaded = append(data.Loaded, rel.Target)
+			}
+		}
+		data.Resources = append(data.Resources, r)
+	}
+	sort.Slice(data.Loaded, func(i, j int) bool { return data.Loaded[i].Name < data.Loaded[j].Name })
+
+	var written []string
+	generate := func(file, tmpl string, data interface{}) error {
+		if err := g.renderFile(file, graphqlTemplates+tmpl, data); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file, err)
+		}
+		written = append(written, filepath.Join(g.ProjectName, file))
+		return nil
+	}
+
+	if err := generate("internal/gql/schema.graphql", "schema.graphql.tmpl", data); err != nil {
+		return written, err
+	}
+	if err := generate("internal/gql/resolver.go", "resolver.go.tmpl", data); err != nil {
+		return written, err
+	}
+	if err := generate("internal/gql/loaders.go", "loaders.go.tmpl", data); err != nil {
+		return written, err
+	}
+	for _, r := range data.Resources {
+		if err := generate(fmt.Sprintf("internal/gql/%s_resolver.go", r.Base), "resource_resolver.go.tmpl", r); err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// graphqlRelationFor returns the relation for a ref field named <name>_id
+// whose table is one of the resources
+func graphqlRelationFor(f Field, fields []Field, byTable map[string]resourceData) (graphqlRelation, bool) {
+	if f.Type != "ref" || !strings.HasSuffix(f.Column(), "_id") {
+		return graphqlRelation{}, false
+	}
+
+	target, ok := byTable[f.References]
+	if !ok {
+		return graphqlRelation{}, false
+	}
+
+	column := strings.TrimSuffix(f.Column(), "_id")
+	for _, other := range fields {
+		// A field already uses the name
+		if other.Column() == column {
+			return graphqlRelation{}, false
+		}
+	}
+
+	goName := protoGoName(column)
+	return graphqlRelation{
+		Name:   strings.ToLower(goName[:1]) + goName[1:],
+		GoName: goName,
+		Field:  f,
+		Target: target,
+	}, true
+}
+
+// GraphQLName returns the lower camelCase GraphQL field name
+func (f Field) GraphQLName() string {
+	name := protoGoName(f.Column())
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// GraphQLType returns the GraphQL type of the field, non-null when the
+// field is required
+func (f Field) GraphQLType() string {
+	var typ string
+	switch f.Type {
+	case "int":
+		typ = "Int64"
+	case "float":
+		typ = "Float"
+	case "bool":
+		typ = "Boolean"
+	case "time":
+		typ = "Time"
+	case "json":
+		typ = "JSON"
+	case "ref":
+		typ = "ID"
+	default:
+		typ = "String"
+	}
+
+	if f.Nullable() {
+		return typ
+	}
+	return typ + "!"
+}
+
+// GraphQLGoType returns the Go type graphql-go resolves the field's
+// GraphQL type to
+func (f Field) GraphQLGoType() string {
+	var typ string
+	switch f.Type {
+	case "int":
+		typ = "Int64"
+	case "float":
+		typ = "float64"
+	case "bool":
+		typ = "bool"
+	case "time":
+		typ = "graphql.Time"
+	case "json":
+		typ = "JSON"
+	case "ref":
+		typ = "graphql.ID"
+	default:
+		typ = "string"
+	}
+
+	if f.Nullable() {
+		return "*" + typ
+	}
+	return typ
+}
+
+// ToGraphQL returns the expression converting the model value expr to its
+// GraphQL value
+func (f Field) ToGraphQL(expr string) string {
+	nullable := f.Nullable()
+	switch f.Type {
+	case "int":
+		if nullable {
+			return "toInt64(" + expr + ")"
+		}
+		return "Int64(" + expr + ")"
+	case "time":
+		if nullable {
+			return "toTime(" + expr + ")"
+		}
+		return "graphql.Time{Time: " + expr + "}"
+	case "json":
+		if nullable {
+			return "toJSON(" + expr + ")"
+		}
+		return "JSON(" + expr + ")"
+	case "ref":
+		if nullable {
+			return "toIDPtr(" + expr + ")"
+		}
+		return "toID(" + expr + ")"
+	default:
+		return expr
+	}
+}
+
+// FromGraphQL returns the expression converting the GraphQL input value
+// expr to the model value. IDs are parsed by an idParser named ids.
+func (f Field) FromGraphQL(expr string) string {
+	nullable := f.Nullable()
+	switch f.Type {
+	case "int":
+		if nullable {
+			return "fromInt64(" + expr + ")"
+		}
+		return "int64(" + expr + ")"
+	case "time":
+		if nullable {
+			return "fromTime(" + expr + ")"
+		}
+		return expr + ".Time"
+	case "json":
+		if nullable {
+			return "fromJSON(" + expr + ")"
+		}
+		return "[]byte(" + expr + ")"
+	case "ref":
+		if nullable {
+			return "ids.parsePtr(" + expr + ")"
+		}
+		return "ids.parse(" + expr + ")"
+	default:
+		return expr
+	}
+}
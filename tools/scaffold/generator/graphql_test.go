@@ -0,0 +1,83 @@
+package generator
+
+import "testing"
+
+func TestFieldGraphQLConversions(t *testing.T) {
+	tests := []struct {
+		def         string
+		name        string
+		graphqlType string
+		goType      string
+		toGraphQL   string
+		fromGraphQL string
+	}{
+		{"name:string:required", "name", "String!", "string", "m.Name", "in.Name"},
+		{"active:bool:required", "active", "Boolean!", "bool", "m.Active", "in.Active"},
+		{"qty:int:required", "qty", "Int64!", "Int64", "Int64(m.Qty)", "int64(in.Qty)"},
+		{"qty:int", "qty", "Int64", "*Int64", "toInt64(m.Qty)", "fromInt64(in.Qty)"},
+		{"owner_id:ref:required,ref=users", "ownerId", "ID!", "graphql.ID", "toID(m.OwnerID)", "ids.parse(in.OwnerID)"},
+		{"owner_id:ref:ref=users", "ownerId", "ID", "*graphql.ID", "toIDPtr(m.OwnerID)", "ids.parsePtr(in.OwnerID)"},
+		{"due:time:required", "due", "Time!", "graphql.Time", "graphql.Time{Time: m.Due}", "in.Due.Time"},
+		{"due:time", "due", "Time", "*graphql.Time", "toTime(m.Due)", "fromTime(in.Due)"},
+		{"data:json:required", "data", "JSON!", "JSON", "JSON(m.Data)", "[]byte(in.Data)"},
+		{"data:json", "data", "JSON", "*JSON", "toJSON(m.Data)", "fromJSON(in.Data)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.def, func(t *testing.T) {
+			f, err := ParseField(tt.def)
+			if err != nil {
+				t.Fatalf("ParseField failed: %v", err)
+			}
+			if got := f.GraphQLName(); got != tt.name {
+				t.Errorf("GraphQLName() = %q, want %q", got, tt.name)
+			}
+			if got := f.GraphQLType(); got != tt.graphqlType {
+				t.Errorf("GraphQLType() = %q, want %q", got, tt.graphqlType)
+			}
+			if got := f.GraphQLGoType(); got != tt.goType {
+				t.Errorf("GraphQLGoType() = %q, want %q", got, tt.goType)
+			}
+			if got := f.ToGraphQL("m." + f.GoName()); got != tt.toGraphQL {
+				t.Errorf("ToGraphQL() = %q, want %q", got, tt.toGraphQL)
+			}
+			if got := f.FromGraphQL("in." + f.GoName()); got != tt.fromGraphQL {
+				t.Errorf("FromGraphQL() = %q, want %q", got, tt.fromGraphQL)
+			}
+		})
+	}
+}
+
+func TestGraphQLRelationFor(t *testing.T) {
+	byTable := map[string]resourceData{"users": {Name: "User", Resource: "user"}}
+	fields, err := ParseFields("author_id:ref:ref=users editor:ref:ref=users tag_id:ref:ref=tags owner_id:ref:ref=users owner:string")
+	if err != nil {
+		t.Fatalf("ParseFields failed: %v", err)
+	}
+
+	tests := []struct {
+		field  Field
+		want   string
+		wantOK bool
+	}{
+		{fields[0], "author", true},
+		// Not named <name>_id
+		{fields[1], "", false},
+		// Not a resource
+		{fields[2], "", false},
+		// owner is already a field
+		{fields[3], "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field.Name, func(t *testing.T) {
+			rel, ok := graphqlRelationFor(tt.field, fields, byTable)
+			if ok != tt.wantOK {
+				t.Fatalf("Expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if ok && (rel.Name != tt.want || rel.Target.Name != "User") {
+				t.Errorf("Expected relation %s to User, got %s to %s", tt.want, rel.Name, rel.Target.Name)
+			}
+		})
+	}
+}
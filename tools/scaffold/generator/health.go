@@ -0,0 +1,17 @@
+package generator
+
+// healthTemplateDir holds the health check package templates
+const healthTemplateDir = "tools/scaffold/templates/health/"
+
+// healthFiles returns the health checkers of the enabled features. The
+// registry and the database and HTTP checkers are base templates; the
+// checkers below import a feature's client, so they are only generated
+// with it.
+func (g *TemplateGenerator) healthFiles() map[string]string {
+	files := make(map[string]string)
+	if g.Features["cache"] {
+		files["pkg/health/redis.go"] = healthTemplateDir + "redis.go.tmpl"
+		files["cmd/api/redis.go"] = healthTemplateDir + "redis_client.go.tmpl"
+	}
+	return files
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHealthChecks(t *testing.T) {
+	chdirRepoRoot(t)
+
+	tests := []struct {
+		name     string
+		database string
+		features []string
+		checks   []string
+		files    []string
+	}{
+		{"postgres", "postgres", nil, []string{"database"}, nil},
+		{"mysql", "mysql", nil, []string{"database"}, nil},
+		{"sqlite", "sqlite", nil, []string{"database"}, nil},
+		{"postgres with cache", "postgres", []string{"cache"}, []string{"database", "redis"}, []string{"pkg/health/redis.go", "cmd/api/redis.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewTemplateGenerator("shop", "example.com/shop", tt.features, nil)
+			g.Output = make(map[string][]byte)
+			g.Database = tt.database
+			if err := g.SetDeployment("k8s"); err != nil {
+				t.Fatalf("SetDeployment failed: %v", err)
+			}
+			if err := g.Generate(); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+
+			for _, file := range []string{"pkg/health/redis.go", "cmd/api/redis.go"} {
+				_, ok := g.Output[file]
+				if want := contains(tt.files, file); ok != want {
+					t.Errorf("Expected %s generated %v, got %v", file, want, ok)
+				}
+			}
+
+			main := string(g.Output["cmd/api/main.go"])
+			for _, check := range []string{"database", "redis"} {
+				registered := strings.Contains(main, `checks.Register("`+check+`"`)
+				if want := contains(tt.checks, check); registered != want {
+					t.Errorf("Expected the %s check registered %v, got %v", check, want, registered)
+				}
+			}
+
+			// The probes hit the endpoints main.go serves
+			var deployment map[string]interface{}
+			decodeYAML(t, g.Output, "deploy/k8s/base/deployment.yaml", &deployment)
+			for probe, path := range map[string]string{"livenessProbe": "/livez", "readinessProbe": "/readyz"} {
+				got, _ := lookup(deployment, strings.Split("spec.template.spec.containers.0."+probe+".httpGet.path", "."))
+				if got != path {
+					t.Errorf("Expected the %s on %s, got %v", probe, path, got)
+				}
+				if !strings.Contains(main, `Path: "`+path+`"`) {
+					t.Errorf("Expected main.go to serve %s", path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestHelmChart(t *testing.T) {
+	chdirRepoRoot(t)
+
+	tests := []struct {
+		name       string
+		features   []string
+		database   string
+		transports []string
+		wantKinds  []string
+		absent     []string
+	}{
+		{
+			name:      "sqlite without features",
+			database:  "sqlite",
+			wantKinds: []string{"ConfigMap", "Deployment", "HorizontalPodAutoscaler", "PodDisruptionBudget", "Service"},
+			absent:    []string{"templates/secret.yaml", "templates/servicemonitor.yaml"},
+		},
+		{
+			name:       "postgres with cache, metrics and grpc",
+			features:   []string{"cache", "metrics"},
+			database:   "postgres",
+			transports: []string{TransportHTTP, TransportGRPC},
+			wantKinds:  []string{"ConfigMap", "Deployment", "HorizontalPodAutoscaler", "PodDisruptionBudget", "Secret", "Service", "ServiceMonitor"},
+		},
+		{
+			name:      "mysql",
+			database:  "mysql",
+			wantKinds: []string{"ConfigMap", "Deployment", "HorizontalPodAutoscaler", "PodDisruptionBudget", "Secret", "Service"},
+			absent:    []string{"templates/servicemonitor.yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewTemplateGenerator("/tmp/Shop", "example.com/shop", tt.features, nil)
+			g.Output = make(map[string][]byte)
+			g.Database = tt.database
+			if tt.transports != nil {
+				g.SetTransports(tt.transports)
+			}
+			if err := g.SetDeployment(DeploymentHelm); err != nil {
+				t.Fatalf("SetDeployment failed: %v", err)
+			}
+			if err := g.Generate(); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+
+			chart := loadChart(t, g.Output, "deploy/helm/shop")
+			for _, file := range tt.absent {
+				if _, ok := chart.files[file]; ok {
+					t.Errorf("Expected %s not to be generated", file)
+				}
+			}
+
+			// Chart metadata as helm lint requires it
+			var meta struct {
+				APIVersion string `yaml:"apiVersion"`
+				Name       string `yaml:"name"`
+				Version    string `yaml:"version"`
+				AppVersion string `yaml:"appVersion"`
+			}
+			if err := yaml.Unmarshal(chart.files["Chart.yaml"], &meta); err != nil {
+				t.Fatalf("Chart.yaml is not valid YAML: %v", err)
+			}
+			if meta.APIVersion != "v2" || meta.Name != "shop" || meta.Version == "" {
+				t.Errorf("Unexpected chart metadata %+v", meta)
+			}
+
+			// The default values satisfy the schema
+			var schema map[string]interface{}
+			if err := json.Unmarshal(chart.files["values.schema.json"], &schema); err != nil {
+				t.Fatalf("values.schema.json is not valid JSON: %v", err)
+			}
+			values := chart.values(t)
+			for _, err := range validateSchema(schema, values, "") {
+				t.Errorf("values.yaml does not match values.schema.json: %s", err)
+			}
+			for key := range values {
+				if _, ok := schema["properties"].(map[string]interface{})[key]; !ok {
+					t.Errorf("values.yaml key %s is missing from values.schema.json", key)
+				}
+			}
+
+			// Render with the defaults and with every optional object on
+			objects := chart.render(t, values)
+			var kinds []string
+			for kind := range objects {
+				kinds = append(kinds, kind)
+			}
+			sort.Strings(kinds)
+			if !reflect.DeepEqual(kinds, tt.wantKinds) {
+				t.Errorf("Rendered kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+
+			container := "spec.template.spec.containers.0."
+			for _, field := range []string{
+				container + "livenessProbe.httpGet.path",
+				container + "readinessProbe.httpGet.path",
+				container + "resources.limits.memory",
+				container + "securityContext.readOnlyRootFilesystem",
+				container + "volumeMounts.0.mountPath",
+				"spec.template.spec.securityContext.runAsNonRoot",
+				"spec.template.metadata.annotations.checksum/config",
+			} {
+				if _, ok := lookup(objects["Deployment"], strings.Split(field, ".")); !ok {
+					t.Errorf("Expected the deployment to set %s", field)
+				}
+			}
+			if config, _ := lookup(objects["ConfigMap"], []string{"data", "config.yaml"}); !strings.Contains(fmt.Sprint(config), "log_level: info") {
+				t.Errorf("Expected the config map to hold config.yaml, got %v", config)
+			}
+
+			values["ingress"].(map[string]interface{})["enabled"] = true
+			values["autoscaling"].(map[string]interface{})["enabled"] = false
+			values["existingSecret"] = "shop-credentials"
+			kinds = kinds[:0]
+			for kind := range chart.render(t, values) {
+				kinds = append(kinds, kind)
+			}
+			if !contains(kinds, "Ingress") || contains(kinds, "HorizontalPodAutoscaler") || contains(kinds, "Secret") {
+				t.Errorf("Expected the values to toggle objects, got %v", kinds)
+			}
+		})
+	}
+}
+
+// helmChart is a generated chart keyed by path in the chart directory
+type helmChart struct {
+	dir   string
+	name  string
+	files map[string][]byte
+}
+
+func loadChart(t *testing.T, output map[string][]byte, dir string) *helmChart {
+	t.Helper()
+
+	chart := &helmChart{dir: dir, name: path.Base(dir), files: make(map[string][]byte)}
+	for file, content := range output {
+		if rel, ok := strings.CutPrefix(file, dir+"/"); ok {
+			chart.files[rel] = content
+		}
+	}
+	if len(chart.files) == 0 {
+		t.Fatalf("No chart generated in %s", dir)
+	}
+	return chart
+}
+
+func (c *helmChart) values(t *testing.T) map[string]interface{} {
+	t.Helper()
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(c.files["values.yaml"], &values); err != nil {
+		t.Fatalf("values.yaml is not valid YAML: %v", err)
+	}
+	return values
+}
+
+// render executes the chart's templates like helm template does, with the
+// subset of Helm's functions the scaffolded chart uses, and decodes the
+// objects they produce keyed by kind
+func (c *helmChart) render(t *testing.T, values map[string]interface{}) map[string]map[string]interface{} {
+	t.Helper()
+
+	root := template.New(c.name)
+	root.Funcs(helmFuncs(root))
+
+	var names []string
+	for file, content := range c.files {
+		if !strings.HasPrefix(file, "templates/") {
+			continue
+		}
+		name := path.Join(c.name, file)
+		if _, err := root.New(name).Parse(string(content)); err != nil {
+			t.Fatalf("%s does not parse: %v", file, err)
+		}
+		if path.Ext(file) == ".yaml" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var meta map[string]interface{}
+	if err := yaml.Unmarshal(c.files["Chart.yaml"], &meta); err != nil {
+		t.Fatalf("Chart.yaml is not valid YAML: %v", err)
+	}
+	data := map[string]interface{}{
+		"Values": values,
+		"Chart": map[string]interface{}{
+			"Name":       meta["name"],
+			"Version":    meta["version"],
+			"AppVersion": meta["appVersion"],
+		},
+		"Release": map[string]interface{}{
+			"Name":      "test",
+			"Namespace": "default",
+			"Service":   "Helm",
+		},
+		"Template": map[string]interface{}{
+			"BasePath": path.Join(c.name, "templates"),
+		},
+	}
+
+	objects := make(map[string]map[string]interface{})
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := root.ExecuteTemplate(&buf, name, data); err != nil {
+			t.Fatalf("%s does not render: %v", name, err)
+		}
+		if strings.TrimSpace(buf.String()) == "" {
+			continue
+		}
+
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal(buf.Bytes(), &obj); err != nil {
+			t.Fatalf("%s renders invalid YAML: %v\n%s", name, err, buf.String())
+		}
+		kind, _ := obj["kind"].(string)
+		if _, ok := lookup(obj, []string{"metadata", "name"}); kind == "" || !ok {
+			t.Errorf("%s renders an object without kind or name:\n%s", name, buf.String())
+		}
+		objects[kind] = obj
+	}
+
+	// NOTES.txt is rendered but not applied
+	var notes bytes.Buffer
+	if err := root.ExecuteTemplate(&notes, path.Join(c.name, "templates/NOTES.txt"), data); err != nil {
+		t.Errorf("NOTES.txt does not render: %v", err)
+	}
+	return objects
+}
+
+// helmFuncs returns the Helm template functions used by the chart
+func helmFuncs(root *template.Template) template.FuncMap {
+	return template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			var buf bytes.Buffer
+			err := root.ExecuteTemplate(&buf, name, data)
+			return buf.String(), err
+		},
+		"toYaml": func(v interface{}) (string, error) {
+			out, err := yaml.Marshal(v)
+			return strings.TrimSuffix(string(out), "\n"), err
+		},
+		"indent": func(n int, s string) string {
+			pad := strings.Repeat(" ", n)
+			return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"nindent": func(n int, s string) string {
+			pad := strings.Repeat(" ", n)
+			return "\n" + pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"quote": func(v interface{}) string {
+			if v == nil {
+				return `""`
+			}
+			return strconv.Quote(fmt.Sprint(v))
+		},
+		"default": func(def, v interface{}) interface{} {
+			if v == nil || reflect.ValueOf(v).IsZero() {
+				return def
+			}
+			return v
+		},
+		"trunc": func(n int, s string) string {
+			if len(s) > n {
+				return s[:n]
+			}
+			return s
+		},
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"contains":   func(sub, s string) bool { return strings.Contains(s, sub) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"sha256sum": func(s string) string {
+			sum := sha256.Sum256([]byte(s))
+			return hex.EncodeToString(sum[:])
+		},
+	}
+}
+
+// validateSchema checks v against the subset of JSON schema the chart's
+// values.schema.json uses and returns the violations
+func validateSchema(schema map[string]interface{}, v interface{}, at string) []string {
+	var errs []string
+	if typ, ok := schema["type"]; ok && !schemaTypeMatches(typ, v) {
+		return []string{fmt.Sprintf("%s: %v is not of type %v", at, v, typ)}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, v) {
+		errs = append(errs, fmt.Sprintf("%s: %v is not one of %v", at, v, enum))
+	}
+	if n, ok := toFloat(v); ok {
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			errs = append(errs, fmt.Sprintf("%s: %v is below %v", at, v, min))
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			errs = append(errs, fmt.Sprintf("%s: %v is above %v", at, v, max))
+		}
+	}
+	if s, ok := v.(string); ok {
+		if min, ok := schema["minLength"].(float64); ok && float64(len(s)) < min {
+			errs = append(errs, fmt.Sprintf("%s: %q is too short", at, s))
+		}
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return errs
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, key := range required {
+			if _, ok := obj[key.(string)]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required %s", at, key))
+			}
+		}
+	}
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		for key, sub := range props {
+			if value, ok := obj[key]; ok {
+				errs = append(errs, validateSchema(sub.(map[string]interface{}), value, at+"."+key)...)
+			}
+		}
+	}
+	return errs
+}
+
+// schemaTypeMatches reports whether v is of the JSON schema type, or one
+// of the types when typ is a list
+func schemaTypeMatches(typ, v interface{}) bool {
+	if types, ok := typ.([]interface{}); ok {
+		for _, t := range types {
+			if schemaTypeMatches(t, v) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch typ {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "integer":
+		_, ok := v.(int)
+		return ok
+	case "number":
+		_, ok := toFloat(v)
+		return ok
+	default:
+		return false
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// containsValue reports whether list holds v
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// componentName matches the name of a component registered in main.go
+var componentName = regexp.MustCompile(`(?m)^\t\tName:\s+"([^"]+)"`)
+
+func TestLifecycleComponents(t *testing.T) {
+	chdirRepoRoot(t)
+
+	tests := []struct {
+		name       string
+		database   string
+		features   []string
+		transports []string
+		want       []string
+	}{
+		{"postgres", "postgres", nil, []string{TransportHTTP}, []string{"database", "http"}},
+		{"sqlite with cache and grpc", "sqlite", []string{"cache"}, []string{TransportHTTP, TransportGRPC}, []string{"database", "redis", "http", "grpc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewTemplateGenerator("shop", "example.com/shop", tt.features, nil)
+			g.Output = make(map[string][]byte)
+			g.Database = tt.database
+			g.SetTransports(tt.transports)
+			if err := g.Generate(); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+
+			if _, ok := g.Output["pkg/lifecycle/lifecycle.go"]; !ok {
+				t.Fatal("Expected pkg/lifecycle/lifecycle.go to be generated")
+			}
+
+			var got []string
+			for _, m := range componentName.FindAllStringSubmatch(string(g.Output["cmd/api/main.go"]), -1) {
+				got = append(got, m[1])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Registered components = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported logging backends. Generated code always logs through log/slog;
+// the backend only selects the handler records are written with.
+const (
+	LoggerSlog    = "slog"
+	LoggerZap     = "zap"
+	LoggerZerolog = "zerolog"
+)
+
+// loggerHandlerTemplates maps each backend to the template creating its
+// slog handler
+var loggerHandlerTemplates = map[string]string{
+	LoggerSlog:    "tools/scaffold/templates/logger/slog.go.tmpl",
+	LoggerZap:     "tools/scaffold/templates/logger/zap.go.tmpl",
+	LoggerZerolog: "tools/scaffold/templates/logger/zerolog.go.tmpl",
+}
+
+// loggerHandlerFile is the project file holding the backend's handler
+const loggerHandlerFile = "pkg/logger/handler.go"
+
+// ParseLogger validates a logging backend name. An empty name selects slog.
+func ParseLogger(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return LoggerSlog, nil
+	}
+	if _, ok := loggerHandlerTemplates[name]; !ok {
+		return "", fmt.Errorf("unsupported logger %q (supported: slog, zap, zerolog)", name)
+	}
+	return name, nil
+}
+
+// SetLogger selects the handler backing the generated slog logger
+func (g *TemplateGenerator) SetLogger(name string) error {
+	backend, err := ParseLogger(name)
+	if err != nil {
+		return err
+	}
+
+	g.Logger = backend
+	g.Templates[loggerHandlerFile] = loggerHandlerTemplates[backend]
+	return nil
+}
@@ -0,0 +1,50 @@
+package generator
+
+import "testing"
+
+func TestParseLogger(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", LoggerSlog, false},
+		{"slog", LoggerSlog, false},
+		{"Zap", LoggerZap, false},
+		{" zerolog ", LoggerZerolog, false},
+		{"logrus", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLogger(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	g := NewTemplateGenerator("app", "example.com/app", nil, nil)
+	if got := g.Templates[loggerHandlerFile]; got != loggerHandlerTemplates[LoggerSlog] {
+		t.Errorf("Expected slog by default, got %s", got)
+	}
+
+	if err := g.SetLogger("zerolog"); err != nil {
+		t.Fatalf("SetLogger failed: %v", err)
+	}
+	if got := g.Templates[loggerHandlerFile]; got != loggerHandlerTemplates[LoggerZerolog] {
+		t.Errorf("Expected the zerolog template, got %s", got)
+	}
+	if m := g.NewManifest(); m.Logger != LoggerZerolog {
+		t.Errorf("Expected the manifest to record zerolog, got %q", m.Logger)
+	}
+
+	if err := g.SetLogger("logrus"); err == nil {
+		t.Error("Expected an error for an unsupported logger")
+	}
+}
@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFile is the name of the project manifest written to the root of
+// every generated project
+const ManifestFile = "scaffold.json"
+
+// Manifest records the choices a project was generated with so that later
+// commands (migrations, resources) can follow them
+type Manifest struct {
+	Name       string               `json:"name"`
+	Module     string               `json:"module"`
+	Database   string               `json:"database"`
+	Features   []string             `json:"features,omitempty"`
+	Transports []string             `json:"transports,omitempty"`
+	Router     string               `json:"router,omitempty"`
+	Logger     string               `json:"logger,omitempty"`
+	Migrations MigrationSettings    `json:"migrations"`
+	Resources  []ResourceDefinition `json:"resources,omitempty"`
+}
+
+// ResourceDefinition records the field definitions a resource was last
+// generated with, used to diff the schema when the resource changes
+type ResourceDefinition struct {
+	Name   string        `json:"name"`
+	Table  string        `json:"table,omitempty"`
+	Fields []string      `json:"fields"`
+	Proto  *ProtoNumbers `json:"proto,omitempty"`
+}
+
+// Resource parses the stored definition back into a Resource
+func (d ResourceDefinition) Resource() (Resource, error) {
+	fields := make([]Field, 0, len(d.Fields))
+	for _, def := range d.Fields {
+		f, err := ParseField(def)
+		if err != nil {
+			return Resource{}, fmt.Errorf("invalid stored definition for %s: %w", d.Name, err)
+		}
+		fields = append(fields, f)
+	}
+	res, err := NewResource(d.Name, fields)
+	if err != nil {
+		return Resource{}, err
+	}
+	res.Table = d.Table
+	res.Proto = d.Proto
+	return res, nil
+}
+
+// Resource returns the stored definition for the named resource, or nil
+func (m *Manifest) Resource(name string) *ResourceDefinition {
+	for i := range m.Resources {
+		if strings.EqualFold(m.Resources[i].Name, name) {
+			return &m.Resources[i]
+		}
+	}
+	return nil
+}
+
+// ProtoNumbers numbers the protobuf fields of res following the numbers
+// recorded for the resource. Resources recorded before numbers were kept
+// were numbered in the order of their stored fields.
+func (m *Manifest) ProtoNumbers(res Resource) (*ProtoNumbers, error) {
+	def := m.Resource(res.Name)
+	if def == nil {
+		return NumberProtoFields(res.Fields, nil), nil
+	}
+
+	previous := def.Proto
+	if previous == nil {
+		stored, err := def.Resource()
+		if err != nil {
+			return nil, err
+		}
+		previous = NumberProtoFields(stored.Fields, nil)
+	}
+	return NumberProtoFields(res.Fields, previous), nil
+}
+
+// AllResources parses every stored resource definition
+func (m *Manifest) AllResources() ([]Resource, error) {
+	resources := make([]Resource, 0, len(m.Resources))
+	for _, def := range m.Resources {
+		res, err := def.Resource()
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
+
+// SetResource records the current field definitions for a resource
+func (m *Manifest) SetResource(res Resource) {
+	def := ResourceDefinition{Name: res.Name, Table: res.Table, Fields: make([]string, len(res.Fields)), Proto: res.Proto}
+	for i, f := range res.Fields {
+		def.Fields[i] = f.Definition()
+	}
+
+	if existing := m.Resource(res.Name); existing != nil {
+		*existing = def
+		return
+	}
+	m.Resources = append(m.Resources, def)
+}
+
+// NewManifest returns a manifest for the generator's project. The project
+// is named after its directory, not the path it was generated at.
+func (g *TemplateGenerator) NewManifest() *Manifest {
+	var features []string
+	for f, enabled := range g.Features {
+		if enabled && f != "" {
+			features = append(features, f)
+		}
+	}
+	sort.Strings(features)
+
+	return &Manifest{
+		Name:       filepath.Base(g.ProjectName),
+		Module:     g.Module,
+		Database:   g.Database,
+		Features:   features,
+		Transports: g.transportList(),
+		Router:     g.Router,
+		Logger:     g.Logger,
+		Migrations: g.Migrations.withDefaults(),
+	}
+}
+
+// LoadManifest reads the manifest from projectDir. A missing manifest is
+// reported with an error wrapping os.ErrNotExist.
+func LoadManifest(projectDir string) (*Manifest, error) {
+	path := filepath.Join(filepath.Clean(projectDir), ManifestFile)
+
+	// #nosec G304 - the manifest name is fixed and projectDir is cleaned
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	m.Migrations = m.Migrations.withDefaults()
+	return &m, nil
+}
+
+// Save writes the manifest to projectDir, replacing any existing manifest
+func (m *Manifest) Save(projectDir string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	content = append(content, '\n')
+
+	path := filepath.Join(filepath.Clean(projectDir), ManifestFile)
+	tempFile := path + ".tmp"
+	if err := os.WriteFile(tempFile, content, 0600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	// Rename the temp file to the manifest (atomic operation)
+	if err := os.Rename(tempFile, path); err != nil {
+		_ = os.Remove(tempFile)
+		return fmt.Errorf("failed to finalize manifest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package generator
+
+import "testing"
+
+func TestNewManifestName(t *testing.T) {
+	tests := []struct {
+		project string
+		want    string
+	}{
+		{"shop", "shop"},
+		{"/tmp/p1", "p1"},
+		{"projects/shop/", "shop"},
+		{"./my-shop", "my-shop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.project, func(t *testing.T) {
+			g := NewTemplateGenerator(tt.project, "example.com/shop", nil, nil)
+			if got := g.NewManifest().Name; got != tt.want {
+				t.Errorf("Expected name %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
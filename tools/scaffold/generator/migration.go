@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Migration is a pair of up and down statement lists for a single schema change
+type Migration struct {
+	Name    string
+	Dialect string
+	Up      []Statement
+	Down    []Statement
+	// Warnings are written as comments at the top of the migration
+	Warnings []string
+}
+
+// CreateTableMigration builds the migration that creates the table for a
+// resource, including column constraints, foreign keys and indexes
+func CreateTableMigration(res Resource, d Dialect) (Migration, error) {
+	table := res.TableName()
+
+	defs := []string{d.PrimaryKey()}
+	var constraints []string
+	var indexes []Statement
+
+	for _, f := range res.Fields {
+		def, err := columnDefinition(d, f)
+		if err != nil {
+			return Migration{}, err
+		}
+		defs = append(defs, def)
+
+		if f.Unique {
+			if c := d.UniqueConstraint(uniqueName(table, f), f.Column()); c != "" {
+				constraints = append(constraints, c)
+			} else {
+				indexes = append(indexes, Statement{SQL: d.AddUnique(uniqueName(table, f), table, f.Column())})
+			}
+		}
+
+		if f.References != "" {
+			constraints = append(constraints, foreignKeyConstraint(d, table, f))
+		}
+
+		if indexed(f) {
+			indexes = append(indexes, Statement{SQL: d.CreateIndex(indexName(table, f.Column()), table, f.Column())})
+		}
+	}
+
+	defs = append(defs, d.AuditColumns()...)
+	defs = append(defs, constraints...)
+
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n    %s\n)", d.Quote(table), strings.Join(defs, ",\n    "))
+	if opts := d.TableOptions(); opts != "" {
+		create += " " + opts
+	}
+	create += ";"
+
+	m := Migration{
+		Name:    "create_" + table + "_table",
+		Dialect: d.Name(),
+	}
+
+	m.Up = append(m.Up, Statement{SQL: create})
+	m.Up = append(m.Up, indexes...)
+	m.Up = append(m.Up,
+		Statement{SQL: d.CreateIndex(indexName(table, "created_at"), table, "created_at")},
+		Statement{SQL: d.CreateIndex(indexName(table, "deleted_at"), table, "deleted_at")},
+	)
+	m.Up = append(m.Up, d.AfterCreateTable(table)...)
+
+	m.Down = append(m.Down, d.BeforeDropTable(table)...)
+	m.Down = append(m.Down, Statement{SQL: fmt.Sprintf("DROP TABLE IF EXISTS %s;", d.Quote(table))})
+
+	return m, m.Validate()
+}
+
+// Validate ensures every migration can be rolled back
+func (m Migration) Validate() error {
+	if len(m.Up) == 0 {
+		return fmt.Errorf("migration %s has no up statements", m.Name)
+	}
+	if len(m.Down) == 0 {
+		return fmt.Errorf("migration %s has no matching down statements", m.Name)
+	}
+	return nil
+}
+
+// columnDefinition renders the column definition for a field
+func columnDefinition(d Dialect, f Field) (string, error) {
+	if err := d.ValidateField(f); err != nil {
+		return "", err
+	}
+
+	parts := []string{d.Quote(f.Column()), d.ColumnType(f)}
+
+	if f.Required {
+		parts = append(parts, "NOT NULL")
+	}
+
+	if f.HasDefault {
+		def, err := defaultValue(f)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "DEFAULT "+def)
+	}
+
+	if check := d.ColumnCheck(f); check != "" {
+		parts = append(parts, "CHECK ("+check+")")
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// foreignKeyConstraint renders a named FOREIGN KEY table constraint
+func foreignKeyConstraint(d Dialect, table string, f Field) string {
+	fk := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		d.Quote(foreignKeyName(table, f)), d.Quote(f.Column()), d.Quote(f.References), d.Quote("id"))
+
+	switch f.OnDelete {
+	case "cascade":
+		fk += " ON DELETE CASCADE"
+	case "setnull":
+		fk += " ON DELETE SET NULL"
+	case "restrict":
+		fk += " ON DELETE RESTRICT"
+	}
+
+	return fk
+}
+
+// indexed reports whether a column gets a plain index. Foreign key columns
+// are indexed as well; unique columns already have an index backing the
+// constraint.
+func indexed(f Field) bool {
+	return (f.Index || f.References != "") && !f.Unique
+}
+
+func indexName(table, column string) string {
+	return "idx_" + table + "_" + column
+}
+
+func uniqueName(table string, f Field) string {
+	return "uq_" + table + "_" + f.Column()
+}
+
+func foreignKeyName(table string, f Field) string {
+	return "fk_" + table + "_" + f.Column()
+}
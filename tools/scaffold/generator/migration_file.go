@@ -0,0 +1,225 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported migration file formats
+const (
+	// MigrationFormatGoose writes a single .sql file with goose annotations
+	MigrationFormatGoose = "goose"
+	// MigrationFormatGolangMigrate writes .up.sql/.down.sql pairs for golang-migrate
+	MigrationFormatGolangMigrate = "golang-migrate"
+)
+
+// Supported migration file prefixes
+const (
+	MigrationPrefixTimestamp = "timestamp"
+	MigrationPrefixSequence  = "sequence"
+)
+
+// timestampLayout is the prefix layout shared by goose and golang-migrate
+const timestampLayout = "20060102150405"
+
+// MigrationSettings controls how migration files are named and laid out
+type MigrationSettings struct {
+	Format string `json:"format"`
+	Prefix string `json:"prefix"`
+	Dir    string `json:"dir"`
+}
+
+// DefaultMigrationSettings returns timestamped goose migrations in migrations/
+func DefaultMigrationSettings() MigrationSettings {
+	return MigrationSettings{
+		Format: MigrationFormatGoose,
+		Prefix: MigrationPrefixTimestamp,
+		Dir:    "migrations",
+	}
+}
+
+func (s MigrationSettings) withDefaults() MigrationSettings {
+	def := DefaultMigrationSettings()
+	if s.Format == "" {
+		s.Format = def.Format
+	}
+	if s.Prefix == "" {
+		s.Prefix = def.Prefix
+	}
+	if s.Dir == "" {
+		s.Dir = def.Dir
+	}
+	return s
+}
+
+// Validate checks the format and prefix are supported
+func (s MigrationSettings) Validate() error {
+	switch s.Format {
+	case MigrationFormatGoose, MigrationFormatGolangMigrate:
+	default:
+		return fmt.Errorf("unsupported migration format %q (expected %s or %s)",
+			s.Format, MigrationFormatGoose, MigrationFormatGolangMigrate)
+	}
+
+	switch s.Prefix {
+	case MigrationPrefixTimestamp, MigrationPrefixSequence:
+	default:
+		return fmt.Errorf("unsupported migration prefix %q (expected %s or %s)",
+			s.Prefix, MigrationPrefixTimestamp, MigrationPrefixSequence)
+	}
+
+	if strings.Contains(s.Dir, "..") || filepath.IsAbs(s.Dir) {
+		return fmt.Errorf("invalid migrations directory: %s", s.Dir)
+	}
+
+	return nil
+}
+
+// sequenceWidth matches the zero padding used by each tool's own -seq mode
+func (s MigrationSettings) sequenceWidth() int {
+	if s.Format == MigrationFormatGoose {
+		return 5
+	}
+	return 6
+}
+
+var (
+	migrationNamePattern   = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+	migrationPrefixPattern = regexp.MustCompile(`^(\d+)_`)
+)
+
+// NormalizeMigrationName converts a migration name to lower snake_case and
+// rejects names that cannot be used safely in a file name
+func NormalizeMigrationName(name string) (string, error) {
+	normalized := strings.ToLower(strings.NewReplacer("-", "_", " ", "_").Replace(toSnakeCase(name)))
+	if !migrationNamePattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid migration name %q: use letters, numbers and underscores", name)
+	}
+	return normalized, nil
+}
+
+// migrationFiles returns the names of the files in the project's
+// migrations directory
+func (g *TemplateGenerator) migrationFiles(dir string) ([]string, error) {
+	if g.Output != nil {
+		var names []string
+		prefix := filepath.ToSlash(dir) + "/"
+		for file := range g.Output {
+			if name := strings.TrimPrefix(file, prefix); name != file && !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		return names, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(g.ProjectName, dir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return names, nil
+}
+
+// nextMigrationPrefix returns a prefix that sorts after every migration in
+// names. Timestamp prefixes are bumped past the newest existing migration
+// when the clock has not moved on.
+func nextMigrationPrefix(names []string, settings MigrationSettings, now time.Time) (string, error) {
+	var latest uint64
+	for _, name := range names {
+		match := migrationPrefixPattern.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+		if n, err := strconv.ParseUint(match[1], 10, 64); err == nil && n > latest {
+			latest = n
+		}
+	}
+
+	if settings.Prefix == MigrationPrefixSequence {
+		return fmt.Sprintf("%0*d", settings.sequenceWidth(), latest+1), nil
+	}
+
+	ts := now.UTC()
+	if n, _ := strconv.ParseUint(ts.Format(timestampLayout), 10, 64); n <= latest {
+		prev, err := time.Parse(timestampLayout, strconv.FormatUint(latest, 10))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse migration timestamp %d: %w", latest, err)
+		}
+		ts = prev.Add(time.Second)
+	}
+	return ts.Format(timestampLayout), nil
+}
+
+// WriteMigration writes m into the project's migrations directory using the
+// generator's migration settings and returns the paths of the created files
+func (g *TemplateGenerator) WriteMigration(m Migration, createdAt time.Time) ([]string, error) {
+	settings := g.Migrations.withDefaults()
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	name, err := NormalizeMigrationName(m.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := g.migrationFiles(settings.Dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := nextMigrationPrefix(existing, settings, createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		Name      string
+		Timestamp string
+		Dialect   string
+		Up        []Statement
+		Down      []Statement
+		Warnings  []string
+	}{
+		Name:      name,
+		Timestamp: createdAt.UTC().Format(time.RFC3339),
+		Dialect:   m.Dialect,
+		Up:        m.Up,
+		Down:      m.Down,
+		Warnings:  m.Warnings,
+	}
+
+	base := filepath.Join(settings.Dir, prefix+"_"+name)
+
+	files := map[string]string{
+		base + ".sql": "tools/scaffold/templates/migration.sql.tmpl",
+	}
+	if settings.Format == MigrationFormatGolangMigrate {
+		files = map[string]string{
+			base + ".up.sql":   "tools/scaffold/templates/migration.up.sql.tmpl",
+			base + ".down.sql": "tools/scaffold/templates/migration.down.sql.tmpl",
+		}
+	}
+
+	var written []string
+	for filename, templatePath := range files {
+		if g.exists(filename) {
+			return written, WithCode(CodeConflict, fmt.Errorf("migration already exists: %s", filename))
+		}
+		if err := g.renderFile(filename, templatePath, data); err != nil {
+			return written, err
+		}
+		written = append(written, filepath.Join(g.ProjectName, filename))
+	}
+	sort.Strings(written)
+
+	return written, nil
+}
@@ -0,0 +1,186 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func testResource(t *testing.T) Resource {
+	t.Helper()
+
+	fields, err := ParseFields("title:string:required,max=200 " +
+		"status:enum:required,oneof=draft|published,default=draft " +
+		"slug:string:required,unique " +
+		"author_id:ref:required,ref=users,ondelete=cascade " +
+		"views:int:default=0 " +
+		"published_at:time:index")
+	if err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+
+	return Resource{Name: "Post", Resource: "post", Fields: fields}
+}
+
+func TestCreateTableMigration(t *testing.T) {
+	tests := []struct {
+		dialect  string
+		contains []string
+		down     []string
+	}{
+		{
+			dialect: "postgres",
+			contains: []string{
+				`CREATE TABLE IF NOT EXISTS "posts" (`,
+				`"id" BIGSERIAL PRIMARY KEY`,
+				`"title" VARCHAR(200) NOT NULL`,
+				`"status" VARCHAR(9) NOT NULL DEFAULT 'draft' CHECK ("status" IN ('draft', 'published'))`,
+				`"views" BIGINT DEFAULT 0`,
+				`CONSTRAINT "uq_posts_slug" UNIQUE ("slug")`,
+				`CONSTRAINT "fk_posts_author_id" FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE CASCADE`,
+				`CREATE INDEX IF NOT EXISTS "idx_posts_author_id" ON "posts" ("author_id");`,
+				`CREATE INDEX IF NOT EXISTS "idx_posts_published_at" ON "posts" ("published_at");`,
+				"DROP TRIGGER IF EXISTS \"update_posts_updated_at\" ON \"posts\";\nCREATE TRIGGER \"update_posts_updated_at\"",
+			},
+			down: []string{
+				`DROP TRIGGER IF EXISTS "update_posts_updated_at" ON "posts";`,
+				`DROP TABLE IF EXISTS "posts";`,
+			},
+		},
+		{
+			dialect: "mysql",
+			contains: []string{
+				"`id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY",
+				"`status` ENUM('draft', 'published') NOT NULL DEFAULT 'draft'",
+				"`author_id` BIGINT UNSIGNED NOT NULL",
+				"ON UPDATE CURRENT_TIMESTAMP(6)",
+				") ENGINE=InnoDB",
+				"CREATE INDEX `idx_posts_published_at` ON `posts` (`published_at`);",
+			},
+			down: []string{
+				"DROP TABLE IF EXISTS `posts`;",
+			},
+		},
+		{
+			dialect: "sqlite",
+			contains: []string{
+				`"id" INTEGER PRIMARY KEY AUTOINCREMENT`,
+				`"title" TEXT NOT NULL`,
+				`"published_at" DATETIME`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "uq_posts_slug" ON "posts" ("slug");`,
+				`CREATE TRIGGER IF NOT EXISTS "update_posts_updated_at"`,
+			},
+			down: []string{
+				`DROP TRIGGER IF EXISTS "update_posts_updated_at";`,
+				`DROP TABLE IF EXISTS "posts";`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			d, err := DialectFor(tt.dialect)
+			if err != nil {
+				t.Fatalf("DialectFor() error = %v", err)
+			}
+
+			m, err := CreateTableMigration(testResource(t), d)
+			if err != nil {
+				t.Fatalf("CreateTableMigration() error = %v", err)
+			}
+
+			if m.Name != "create_posts_table" {
+				t.Errorf("Expected name create_posts_table, got %s", m.Name)
+			}
+
+			up := joinStatements(m.Up)
+			for _, part := range tt.contains {
+				if !strings.Contains(up, part) {
+					t.Errorf("Expected up migration to contain %q.\nOutput: %s", part, up)
+				}
+			}
+
+			if len(m.Down) != len(tt.down) {
+				t.Fatalf("Expected %d down statements, got %d", len(tt.down), len(m.Down))
+			}
+			for i, want := range tt.down {
+				if m.Down[i].SQL != want {
+					t.Errorf("Down[%d] = %q, want %q", i, m.Down[i].SQL, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateTableMigrationInvalidDefault(t *testing.T) {
+	fields, err := ParseFields("count:int:default=many")
+	if err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+
+	d, _ := DialectFor("postgres")
+	if _, err := CreateTableMigration(Resource{Name: "Counter", Fields: fields}, d); err == nil {
+		t.Error("Expected invalid default error, got nil")
+	}
+}
+
+func TestDialectValidateField(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		wantErr map[string]bool
+	}{
+		{name: "Unique string", fields: "email:string:unique"},
+		{name: "Indexed string with default", fields: "status:string:index,default=new"},
+		{name: "Nullable text and json", fields: "bio:text metadata:json"},
+		{name: "Unique text", fields: "bio:text:unique", wantErr: map[string]bool{"mysql": true}},
+		{name: "Indexed json", fields: "metadata:json:index", wantErr: map[string]bool{"mysql": true}},
+		{name: "Text default", fields: "bio:text:default=none", wantErr: map[string]bool{"mysql": true}},
+	}
+
+	for _, tt := range tests {
+		for _, dialect := range []string{"postgres", "mysql", "sqlite"} {
+			t.Run(tt.name+"/"+dialect, func(t *testing.T) {
+				d, _ := DialectFor(dialect)
+				res := mustResource(t, "Profile", tt.fields)
+
+				_, err := CreateTableMigration(res, d)
+				if (err != nil) != tt.wantErr[dialect] {
+					t.Errorf("CreateTableMigration() error = %v, wantErr %v", err, tt.wantErr[dialect])
+				}
+
+				// Adding the same columns to an existing table is checked too
+				diff, err := DiffResources(mustResource(t, "Profile", "name:string"), res, nil)
+				if err != nil {
+					t.Fatalf("DiffResources() error = %v", err)
+				}
+				if _, err := AlterTableMigration(diff, d); (err != nil) != tt.wantErr[dialect] {
+					t.Errorf("AlterTableMigration() error = %v, wantErr %v", err, tt.wantErr[dialect])
+				}
+			})
+		}
+	}
+
+	// Indexing an existing MySQL text column is rejected as well
+	d, _ := DialectFor("mysql")
+	diff, err := DiffResources(mustResource(t, "Profile", "bio:text"), mustResource(t, "Profile", "bio:text:index"), nil)
+	if err != nil {
+		t.Fatalf("DiffResources() error = %v", err)
+	}
+	if _, err := AlterTableMigration(diff, d); err == nil {
+		t.Error("Expected indexing a mysql text column to fail, got nil")
+	}
+}
+
+func TestDialectForUnknown(t *testing.T) {
+	if _, err := DialectFor("oracle"); err == nil {
+		t.Error("Expected error for unsupported dialect, got nil")
+	}
+}
+
+func joinStatements(stmts []Statement) string {
+	parts := make([]string, len(stmts))
+	for i, s := range stmts {
+		parts[i] = s.SQL
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,971 @@
+package generator
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIDocument is the subset of an OpenAPI 3 document used to generate
+// server code. JSON documents are read through the YAML decoder as well.
+type openAPIDocument struct {
+	OpenAPI string `yaml:"openapi"`
+	Info    struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths      map[string]*openAPIPathItem `yaml:"paths"`
+	Components struct {
+		Schemas       map[string]*openAPISchema      `yaml:"schemas"`
+		Parameters    map[string]*openAPIParameter   `yaml:"parameters"`
+		RequestBodies map[string]*openAPIRequestBody `yaml:"requestBodies"`
+		Responses     map[string]*openAPIResponse    `yaml:"responses"`
+	} `yaml:"components"`
+}
+
+type openAPIPathItem struct {
+	Parameters []*openAPIParameter `yaml:"parameters"`
+	Get        *openAPIOperation   `yaml:"get"`
+	Put        *openAPIOperation   `yaml:"put"`
+	Post       *openAPIOperation   `yaml:"post"`
+	Delete     *openAPIOperation   `yaml:"delete"`
+	Patch      *openAPIOperation   `yaml:"patch"`
+	Head       *openAPIOperation   `yaml:"head"`
+	Options    *openAPIOperation   `yaml:"options"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `yaml:"operationId"`
+	Summary     string                      `yaml:"summary"`
+	Description string                      `yaml:"description"`
+	Tags        []string                    `yaml:"tags"`
+	Parameters  []*openAPIParameter         `yaml:"parameters"`
+	RequestBody *openAPIRequestBody         `yaml:"requestBody"`
+	Responses   map[string]*openAPIResponse `yaml:"responses"`
+}
+
+type openAPIParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Schema      *openAPISchema `yaml:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Ref      string                       `yaml:"$ref"`
+	Required bool                         `yaml:"required"`
+	Content  map[string]*openAPIMediaType `yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Ref         string                       `yaml:"$ref"`
+	Description string                       `yaml:"description"`
+	Content     map[string]*openAPIMediaType `yaml:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string            `yaml:"$ref"`
+	Type                 schemaType        `yaml:"type"`
+	Format               string            `yaml:"format"`
+	Description          string            `yaml:"description"`
+	Enum                 []interface{}     `yaml:"enum"`
+	Items                *openAPISchema    `yaml:"items"`
+	Properties           schemaProperties  `yaml:"properties"`
+	Required             []string          `yaml:"required"`
+	AdditionalProperties *openAPISchemaRef `yaml:"additionalProperties"`
+	AllOf                []*openAPISchema  `yaml:"allOf"`
+	OneOf                []*openAPISchema  `yaml:"oneOf"`
+	AnyOf                []*openAPISchema  `yaml:"anyOf"`
+	Nullable             bool              `yaml:"nullable"`
+	MinLength            *int              `yaml:"minLength"`
+	MaxLength            *int              `yaml:"maxLength"`
+	Minimum              *float64          `yaml:"minimum"`
+	Maximum              *float64          `yaml:"maximum"`
+	MinItems             *int              `yaml:"minItems"`
+	MaxItems             *int              `yaml:"maxItems"`
+}
+
+// isStruct reports whether the schema is generated as a struct
+func (s *openAPISchema) isStruct() bool {
+	return len(s.AllOf) > 0 || len(s.Properties) > 0
+}
+
+// schemaType accepts both the OpenAPI 3.0 form (type: string) and the 3.1
+// form (type: [string, "null"])
+type schemaType struct {
+	Name     string
+	Nullable bool
+}
+
+func (t *schemaType) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		t.Name = node.Value
+		return nil
+	}
+
+	var types []string
+	if err := node.Decode(&types); err != nil {
+		return err
+	}
+	for _, name := range types {
+		if name == "null" {
+			t.Nullable = true
+		} else if t.Name == "" {
+			t.Name = name
+		}
+	}
+	return nil
+}
+
+// schemaProperties keeps object properties in document order so generated
+// structs follow the spec
+type schemaProperties []namedSchema
+
+type namedSchema struct {
+	Name   string
+	Schema *openAPISchema
+}
+
+func (p *schemaProperties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a mapping", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var schema openAPISchema
+		if err := node.Content[i+1].Decode(&schema); err != nil {
+			return err
+		}
+		*p = append(*p, namedSchema{Name: node.Content[i].Value, Schema: &schema})
+	}
+	return nil
+}
+
+// openAPISchemaRef is additionalProperties, which is either a boolean or
+// a schema
+type openAPISchemaRef struct {
+	Allowed bool
+	Schema  *openAPISchema
+}
+
+func (r *openAPISchemaRef) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&r.Allowed)
+	}
+	r.Allowed = true
+	r.Schema = &openAPISchema{}
+	return node.Decode(r.Schema)
+}
+
+// API is the server code model built from an OpenAPI document
+type API struct {
+	Title    string
+	Version  string
+	BasePath string
+	Types    []APIType
+	Groups   []APIGroup
+	// Warnings describe parts of the document that were not generated
+	Warnings []string
+}
+
+// APIType is a DTO generated from a schema
+type APIType struct {
+	Name        string
+	Doc         string
+	Description string
+	Alias       string
+	Fields      []APIField
+}
+
+// APIField is a DTO struct field
+type APIField struct {
+	Name     string
+	Type     string
+	Tag      string
+	Doc      string
+	Embedded bool
+}
+
+// APIGroup holds the operations sharing a tag. Each group gets a handler and
+// a service interface.
+type APIGroup struct {
+	Name       string
+	File       string
+	Operations []APIOperation
+}
+
+// APIOperation is a single path and method
+type APIOperation struct {
+	Name            string
+	Method          string
+	Path            string
+	RoutePath       string
+	Summary         string
+	ParamsType      string
+	PathParams      bool
+	QueryParams     bool
+	HeaderParams    bool
+	RequiredQuery   []string
+	RequiredHeaders []string
+	BodyType        string
+	BodyPointer     bool
+	BodyRequired    bool
+	ResultType      string
+	Status          string
+}
+
+// Signature returns the service method signature for the operation
+func (op APIOperation) Signature() string {
+	args := []string{"ctx context.Context"}
+	if op.ParamsType != "" {
+		args = append(args, "params dto."+op.ParamsType)
+	}
+	if op.BodyType != "" {
+		body := op.BodyType
+		if op.BodyPointer {
+			body = "*" + body
+		}
+		args = append(args, "body "+body)
+	}
+
+	results := "error"
+	if op.ResultType != "" {
+		results = "(" + op.ResultType + ", error)"
+	}
+	return fmt.Sprintf("%s(%s) %s", op.Name, strings.Join(args, ", "), results)
+}
+
+// NotImplemented returns the values a stub implementation returns
+func (op APIOperation) NotImplemented() string {
+	if op.ResultType == "" {
+		return "errors.ErrNotImplemented"
+	}
+	return zeroValue(op.ResultType) + ", errors.ErrNotImplemented"
+}
+
+// SignatureUses reports whether any service method signature in the group
+// refers to the package with the given qualifier, such as "dto."
+func (g APIGroup) SignatureUses(qualifier string) bool {
+	for _, op := range g.Operations {
+		if strings.Contains(op.Signature(), qualifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandlerUses reports whether the handlers declare values from the package
+// with the given qualifier. An empty qualifier reports whether anything is
+// bound from the request.
+func (g APIGroup) HandlerUses(qualifier string) bool {
+	for _, op := range g.Operations {
+		if qualifier == "" && (op.ParamsType != "" || op.BodyType != "") {
+			return true
+		}
+		if qualifier == "dto." && op.ParamsType != "" {
+			return true
+		}
+		if qualifier != "" && strings.Contains(op.BodyType, qualifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// Receiver returns the unexported implementation type name for the group
+func (g APIGroup) Receiver() string {
+	return strings.ToLower(g.Name[:1]) + g.Name[1:] + "API"
+}
+
+// HasImport reports whether the DTOs need the given package
+func (a *API) HasImport(pkg string) bool {
+	needle := map[string]string{"time": "time.", "encoding/json": "json."}[pkg]
+	for _, t := range a.Types {
+		if strings.Contains(t.Alias, needle) {
+			return true
+		}
+		for _, f := range t.Fields {
+			if strings.Contains(f.Type, needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseOpenAPI reads an OpenAPI 3 document in YAML or JSON form and builds
+// the server code model for it
+func ParseOpenAPI(content []byte) (*API, error) {
+	var doc openAPIDocument
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q: only OpenAPI 3 documents are supported", doc.OpenAPI)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, fmt.Errorf("OpenAPI document has no paths")
+	}
+
+	b := &apiBuilder{doc: &doc, types: make(map[string]*APIType)}
+	api := &API{Title: doc.Info.Title, Version: doc.Info.Version, BasePath: "/"}
+
+	if len(doc.Servers) > 0 {
+		if u, err := url.Parse(doc.Servers[0].URL); err == nil && u.Path != "" {
+			api.BasePath = "/" + strings.Trim(u.Path, "/")
+		}
+	}
+
+	// Component schemas are generated even if no operation uses them
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := b.namedType(goIdentifier(name), doc.Components.Schemas[name]); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+
+	groups := make(map[string]*APIGroup)
+	var groupOrder []string
+	seen := make(map[string]string)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, m := range []struct {
+			method string
+			op     *openAPIOperation
+		}{
+			{"GET", item.Get}, {"POST", item.Post}, {"PUT", item.Put}, {"PATCH", item.Patch},
+			{"DELETE", item.Delete}, {"HEAD", item.Head}, {"OPTIONS", item.Options},
+		} {
+			if m.op == nil {
+				continue
+			}
+
+			op, err := b.operation(m.method, path, item, m.op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", m.method, path, err)
+			}
+
+			if previous, ok := seen[op.Name]; ok {
+				return nil, fmt.Errorf("%s %s: operation %s is already used by %s", m.method, path, op.Name, previous)
+			}
+			seen[op.Name] = m.method + " " + path
+
+			tag := "default"
+			if len(m.op.Tags) > 0 {
+				tag = m.op.Tags[0]
+			}
+			group, ok := groups[tag]
+			if !ok {
+				name := goIdentifier(tag)
+				group = &APIGroup{Name: name, File: toSnakeCase(name)}
+				groups[tag] = group
+				groupOrder = append(groupOrder, tag)
+			}
+			group.Operations = append(group.Operations, op)
+		}
+	}
+
+	sort.Strings(groupOrder)
+	for _, tag := range groupOrder {
+		api.Groups = append(api.Groups, *groups[tag])
+	}
+	for _, name := range b.order {
+		api.Types = append(api.Types, *b.types[name])
+	}
+	api.Warnings = b.warnings
+
+	return api, nil
+}
+
+// apiBuilder converts schemas to Go types, collecting the DTOs to generate
+type apiBuilder struct {
+	doc      *openAPIDocument
+	types    map[string]*APIType
+	order    []string
+	warnings []string
+}
+
+func (b *apiBuilder) warn(format string, args ...interface{}) {
+	b.warnings = append(b.warnings, fmt.Sprintf(format, args...))
+}
+
+func (b *apiBuilder) operation(method, path string, item *openAPIPathItem, o *openAPIOperation) (APIOperation, error) {
+	name := goIdentifier(o.OperationID)
+	if name == "" {
+		name = operationName(method, path)
+	}
+
+	op := APIOperation{
+		Name:      name,
+		Method:    method,
+		Path:      path,
+		RoutePath: routePath(path),
+		Summary:   firstLine(o.Summary, o.Description),
+	}
+
+	params, err := b.parameters(append(append([]*openAPIParameter{}, item.Parameters...), o.Parameters...))
+	if err != nil {
+		return op, err
+	}
+	if len(params) > 0 {
+		t := &APIType{Name: name + "Params", Doc: fmt.Sprintf("holds the parameters of %s %s", method, path)}
+		for _, p := range params {
+			field, err := b.paramField(name, p)
+			if err != nil {
+				return op, err
+			}
+			t.Fields = append(t.Fields, field)
+
+			switch p.In {
+			case "path":
+				op.PathParams = true
+			case "query":
+				op.QueryParams = true
+				if p.Required {
+					op.RequiredQuery = append(op.RequiredQuery, p.Name)
+				}
+			case "header":
+				op.HeaderParams = true
+				if p.Required {
+					op.RequiredHeaders = append(op.RequiredHeaders, p.Name)
+				}
+			}
+		}
+		if err := b.addType(t); err != nil {
+			return op, err
+		}
+		op.ParamsType = t.Name
+	}
+
+	if o.RequestBody != nil {
+		body, err := b.requestBody(o.RequestBody)
+		if err != nil {
+			return op, err
+		}
+		if schema := jsonSchema(body.Content); schema != nil {
+			typ, err := b.goType(name+"Request", schema)
+			if err != nil {
+				return op, fmt.Errorf("request body: %w", err)
+			}
+			op.BodyType = strings.TrimPrefix(typ, "*")
+			op.BodyPointer = strings.HasPrefix(typ, "*")
+			op.BodyRequired = body.Required
+		} else {
+			b.warn("%s %s: request body has no application/json schema; not bound", method, path)
+		}
+	}
+
+	status, resp, err := b.successResponse(o.Responses)
+	if err != nil {
+		return op, err
+	}
+	op.Status = statusConstant(status)
+	if schema := jsonSchema(resp.Content); schema != nil && status != 204 {
+		typ, err := b.goType(name+"Response", schema)
+		if err != nil {
+			return op, fmt.Errorf("response: %w", err)
+		}
+		op.ResultType = typ
+	}
+
+	op.BodyType = dtoQualified(op.BodyType)
+	op.ResultType = dtoQualified(op.ResultType)
+	return op, nil
+}
+
+// parameters resolves parameter references, letting operation parameters
+// override path item parameters with the same name and location
+func (b *apiBuilder) parameters(list []*openAPIParameter) ([]*openAPIParameter, error) {
+	var params []*openAPIParameter
+	index := make(map[string]int)
+
+	for _, p := range list {
+		if p.Ref != "" {
+			name := refName(p.Ref, "#/components/parameters/")
+			resolved, ok := b.doc.Components.Parameters[name]
+			if !ok {
+				return nil, fmt.Errorf("unresolved parameter reference %s", p.Ref)
+			}
+			p = resolved
+		}
+
+		if p.In == "cookie" {
+			b.warn("cookie parameter %s is not bound", p.Name)
+			continue
+		}
+
+		key := p.In + ":" + p.Name
+		if i, ok := index[key]; ok {
+			params[i] = p
+			continue
+		}
+		index[key] = len(params)
+		params = append(params, p)
+	}
+
+	return params, nil
+}
+
+func (b *apiBuilder) paramField(opName string, p *openAPIParameter) (APIField, error) {
+	schema := p.Schema
+	if schema == nil {
+		schema = &openAPISchema{Type: schemaType{Name: "string"}}
+	}
+
+	typ, err := b.goType(opName+goIdentifier(p.Name), schema)
+	if err != nil {
+		return APIField{}, fmt.Errorf("parameter %s: %w", p.Name, err)
+	}
+
+	required := p.Required || p.In == "path"
+	if !required && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "*") {
+		typ = "*" + typ
+	}
+
+	name := p.Name
+	switch p.In {
+	case "path":
+		name = routeParam(name)
+	case "query", "header":
+	default:
+		return APIField{}, fmt.Errorf("parameter %s has unsupported location %q", p.Name, p.In)
+	}
+
+	tag := fmt.Sprintf(`%s:"%s"`, p.In, name)
+	// The handler checks required query parameters and headers are present,
+	// since their zero value is valid
+	if rules := validationRules(schema); len(rules) > 0 {
+		tag += fmt.Sprintf(` binding:"omitempty,%s"`, strings.Join(rules, ","))
+	}
+
+	return APIField{
+		Name: goIdentifier(p.Name),
+		Type: typ,
+		Tag:  tag,
+		Doc:  firstLine(p.Description),
+	}, nil
+}
+
+func (b *apiBuilder) requestBody(body *openAPIRequestBody) (*openAPIRequestBody, error) {
+	if body.Ref == "" {
+		return body, nil
+	}
+	resolved, ok := b.doc.Components.RequestBodies[refName(body.Ref, "#/components/requestBodies/")]
+	if !ok {
+		return nil, fmt.Errorf("unresolved request body reference %s", body.Ref)
+	}
+	return resolved, nil
+}
+
+// successResponse returns the lowest 2xx response, treating an operation
+// without one as 204 No Content
+func (b *apiBuilder) successResponse(responses map[string]*openAPIResponse) (int, *openAPIResponse, error) {
+	best := 0
+	var resp *openAPIResponse
+	for code, r := range responses {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 200 || status > 299 {
+			continue
+		}
+		if best == 0 || status < best {
+			best, resp = status, r
+		}
+	}
+
+	if resp == nil {
+		return 204, &openAPIResponse{}, nil
+	}
+
+	if resp.Ref != "" {
+		resolved, ok := b.doc.Components.Responses[refName(resp.Ref, "#/components/responses/")]
+		if !ok {
+			return 0, nil, fmt.Errorf("unresolved response reference %s", resp.Ref)
+		}
+		resp = resolved
+	}
+	return best, resp, nil
+}
+
+// goType returns the Go type for a schema. Inline objects become DTOs named
+// after their context.
+func (b *apiBuilder) goType(context string, s *openAPISchema) (string, error) {
+	if s.Ref != "" {
+		name := refName(s.Ref, "#/components/schemas/")
+		target, ok := b.doc.Components.Schemas[name]
+		if !ok {
+			return "", fmt.Errorf("unresolved schema reference %s", s.Ref)
+		}
+		if !target.isStruct() {
+			return goIdentifier(name), nil
+		}
+		return "*" + goIdentifier(name), nil
+	}
+
+	switch {
+	case s.isStruct():
+		if err := b.namedType(context, s); err != nil {
+			return "", err
+		}
+		return "*" + context, nil
+	case len(s.OneOf) > 0 || len(s.AnyOf) > 0:
+		b.warn("%s: oneOf and anyOf are generated as json.RawMessage", context)
+		return "json.RawMessage", nil
+	}
+
+	switch s.Type.Name {
+	case "string":
+		switch s.Format {
+		case "date-time", "date":
+			return "time.Time", nil
+		case "binary", "byte":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		if s.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		if s.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		if s.Items == nil {
+			return "[]interface{}", nil
+		}
+		item, err := b.goType(context+"Item", s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + strings.TrimPrefix(item, "*"), nil
+	case "object", "":
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			value, err := b.goType(context+"Value", s.AdditionalProperties.Schema)
+			if err != nil {
+				return "", err
+			}
+			return "map[string]" + strings.TrimPrefix(value, "*"), nil
+		}
+		return "map[string]interface{}", nil
+	}
+
+	return "", fmt.Errorf("unsupported schema type %q", s.Type.Name)
+}
+
+// namedType registers a DTO for a schema
+func (b *apiBuilder) namedType(name string, s *openAPISchema) error {
+	t := &APIType{Name: name, Description: firstLine(s.Description)}
+
+	if !s.isStruct() {
+		alias, err := b.goType(name+"Item", s)
+		if err != nil {
+			return err
+		}
+		t.Alias = strings.TrimPrefix(alias, "*")
+		return b.addType(t)
+	}
+
+	// Register the name first so self references resolve
+	if err := b.addType(t); err != nil {
+		return err
+	}
+
+	for _, part := range s.AllOf {
+		if part.Ref != "" {
+			embedded, err := b.goType(name, part)
+			if err != nil {
+				return err
+			}
+			t.Fields = append(t.Fields, APIField{Type: strings.TrimPrefix(embedded, "*"), Embedded: true})
+			continue
+		}
+		fields, err := b.structFields(name, part)
+		if err != nil {
+			return err
+		}
+		t.Fields = append(t.Fields, fields...)
+	}
+
+	fields, err := b.structFields(name, s)
+	if err != nil {
+		return err
+	}
+	t.Fields = append(t.Fields, fields...)
+	return nil
+}
+
+func (b *apiBuilder) structFields(parent string, s *openAPISchema) ([]APIField, error) {
+	required := make(map[string]bool)
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	var fields []APIField
+	for _, prop := range s.Properties {
+		goName := goIdentifier(prop.Name)
+		typ, err := b.goType(parent+goName, prop.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", prop.Name, err)
+		}
+
+		nullable := prop.Schema.Nullable || prop.Schema.Type.Nullable
+		optional := !required[prop.Name] || nullable
+		isRef := strings.HasPrefix(typ, "*")
+		if !optional || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "json.RawMessage" {
+			typ = strings.TrimPrefix(typ, "*")
+		} else if !isRef {
+			typ = "*" + typ
+		}
+
+		tag := fmt.Sprintf(`json:"%s`, prop.Name)
+		if optional {
+			tag += ",omitempty"
+		}
+		tag += `"`
+
+		rules := validationRules(prop.Schema)
+		if required[prop.Name] && !nullable && typ != "bool" {
+			rules = append([]string{"required"}, rules...)
+		} else if len(rules) > 0 {
+			rules = append([]string{"omitempty"}, rules...)
+		}
+		if len(rules) > 0 {
+			tag += fmt.Sprintf(` binding:"%s"`, strings.Join(rules, ","))
+		}
+
+		fields = append(fields, APIField{
+			Name: goName,
+			Type: typ,
+			Tag:  tag,
+			Doc:  firstLine(prop.Schema.Description),
+		})
+	}
+	return fields, nil
+}
+
+func (b *apiBuilder) addType(t *APIType) error {
+	if existing, ok := b.types[t.Name]; ok && existing != t {
+		return fmt.Errorf("type %s is generated more than once; rename the schema or operation", t.Name)
+	}
+	if _, ok := b.types[t.Name]; !ok {
+		b.types[t.Name] = t
+		b.order = append(b.order, t.Name)
+	}
+	return nil
+}
+
+// validationRules maps schema constraints onto binding rules
+func validationRules(s *openAPISchema) []string {
+	var rules []string
+	if s.Ref != "" {
+		return nil
+	}
+
+	switch s.Type.Name {
+	case "string":
+		if s.MinLength != nil {
+			rules = append(rules, fmt.Sprintf("min=%d", *s.MinLength))
+		}
+		if s.MaxLength != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *s.MaxLength))
+		}
+		switch s.Format {
+		case "email", "uuid", "ipv4", "ipv6":
+			rules = append(rules, s.Format)
+		case "uri":
+			rules = append(rules, "url")
+		}
+	case "integer", "number":
+		if s.Minimum != nil {
+			rules = append(rules, "gte="+strconv.FormatFloat(*s.Minimum, 'f', -1, 64))
+		}
+		if s.Maximum != nil {
+			rules = append(rules, "lte="+strconv.FormatFloat(*s.Maximum, 'f', -1, 64))
+		}
+	case "array":
+		if s.MinItems != nil {
+			rules = append(rules, fmt.Sprintf("min=%d", *s.MinItems))
+		}
+		if s.MaxItems != nil {
+			rules = append(rules, fmt.Sprintf("max=%d", *s.MaxItems))
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		values := make([]string, 0, len(s.Enum))
+		for _, v := range s.Enum {
+			value := fmt.Sprint(v)
+			if v == nil || strings.ContainsAny(value, " ,\"'") {
+				return rules
+			}
+			values = append(values, value)
+		}
+		rules = append(rules, "oneof="+strings.Join(values, " "))
+	}
+
+	return rules
+}
+
+func jsonSchema(content map[string]*openAPIMediaType) *openAPISchema {
+	for mediaType, m := range content {
+		if (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) && m != nil && m.Schema != nil {
+			return m.Schema
+		}
+	}
+	return nil
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// routePath names the parameters of path for net/http patterns, which only
+// allow Go identifiers, e.g. /pets/{pet-id} becomes /pets/{pet_id}
+func routePath(path string) string {
+	return pathParamPattern.ReplaceAllStringFunc(path, func(param string) string {
+		return "{" + routeParam(param[1:len(param)-1]) + "}"
+	})
+}
+
+var nonRouteParam = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// routeParam converts a path parameter name to a Go identifier
+func routeParam(name string) string {
+	name = nonRouteParam.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// operationName derives a name for operations without an operationId, such
+// as GetPetsByPetID for GET /pets/{petId}
+func operationName(method, path string) string {
+	name := goIdentifier(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if m := pathParamPattern.FindStringSubmatch(segment); m != nil {
+			name += "By" + goIdentifier(m[1])
+			continue
+		}
+		name += goIdentifier(segment)
+	}
+	return name
+}
+
+var nonIdentifier = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// goIdentifier converts names such as pet-store, petId and pet_store to
+// exported Go identifiers
+func goIdentifier(s string) string {
+	name := toPascalCase(nonIdentifier.ReplaceAllString(s, "_"))
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "N" + name
+	}
+	return name
+}
+
+func refName(ref, prefix string) string {
+	return strings.TrimPrefix(ref, prefix)
+}
+
+// dtoQualified prefixes the DTO type names in a Go type with the dto package
+func dtoQualified(typ string) string {
+	prefix := ""
+	for {
+		switch {
+		case strings.HasPrefix(typ, "*"), strings.HasPrefix(typ, "[]"):
+			n := 1
+			if typ[0] == '[' {
+				n = 2
+			}
+			prefix, typ = prefix+typ[:n], typ[n:]
+			continue
+		case strings.HasPrefix(typ, "map[string]"):
+			prefix, typ = prefix+"map[string]", strings.TrimPrefix(typ, "map[string]")
+			continue
+		}
+		break
+	}
+
+	if typ != "" && typ[0] >= 'A' && typ[0] <= 'Z' {
+		typ = "dto." + typ
+	}
+	return prefix + typ
+}
+
+func zeroValue(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "*"), strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "map["),
+		typ == "interface{}", typ == "json.RawMessage":
+		return "nil"
+	case typ == "string":
+		return `""`
+	case typ == "bool":
+		return "false"
+	case strings.HasPrefix(typ, "int"), strings.HasPrefix(typ, "float"):
+		return "0"
+	}
+	return typ + "{}"
+}
+
+var statusConstants = map[int]string{
+	200: "http.StatusOK",
+	201: "http.StatusCreated",
+	202: "http.StatusAccepted",
+	203: "http.StatusNonAuthoritativeInfo",
+	204: "http.StatusNoContent",
+	205: "http.StatusResetContent",
+	206: "http.StatusPartialContent",
+	207: "http.StatusMultiStatus",
+	208: "http.StatusAlreadyReported",
+	226: "http.StatusIMUsed",
+}
+
+func statusConstant(status int) string {
+	if c, ok := statusConstants[status]; ok {
+		return c
+	}
+	return strconv.Itoa(status)
+}
+
+// firstLine returns the first line of the first non-empty text
+func firstLine(texts ...string) string {
+	for _, text := range texts {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = text[:i]
+		}
+		return strings.TrimSpace(text)
+	}
+	return ""
+}
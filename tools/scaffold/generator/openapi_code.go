@@ -0,0 +1,227 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// openAPIHeader marks files that are rewritten on every import. Files
+// without it are never overwritten.
+const openAPIHeader = "// Code generated by scaffold import openapi. DO NOT EDIT."
+
+const openAPITemplates = "tools/scaffold/templates/openapi/"
+
+// apiStub is the template data for a single stub method
+type apiStub struct {
+	Group     APIGroup
+	Operation APIOperation
+}
+
+func newAPIStub(group APIGroup, op APIOperation) apiStub {
+	return apiStub{Group: group, Operation: op}
+}
+
+// apiGroupData is the template data for the files generated per group
+type apiGroupData struct {
+	Module string
+	Group  APIGroup
+}
+
+// GenerateAPI writes the DTOs, handlers, service interfaces and route
+// registration for an API and returns the paths written. Service
+// implementations are created once; later runs only append stubs for
+// operations that have no method yet, so hand-written code is kept.
+func (g *TemplateGenerator) GenerateAPI(api *API) ([]string, error) {
+	if g.Module == "" {
+		return nil, fmt.Errorf("module path is required to generate imports")
+	}
+
+	var written []string
+	generate := func(file, tmpl string, data interface{}) error {
+		if err := g.writeGenerated(file, openAPITemplates+tmpl, data); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file, err)
+		}
+		written = append(written, filepath.Join(g.ProjectName, file))
+		return nil
+	}
+
+	if err := generate("internal/dto/openapi.go", "dto.go.tmpl", api); err != nil {
+		return written, err
+	}
+
+	routes := struct {
+		Module string
+		API    *API
+	}{g.Module, api}
+	if err := generate("internal/handlers/openapi_routes.go", "routes.go.tmpl", routes); err != nil {
+		return written, err
+	}
+
+	for _, group := range api.Groups {
+		data := apiGroupData{Module: g.Module, Group: group}
+
+		if err := generate(fmt.Sprintf("internal/handlers/%s_api.go", group.File), "handler.go.tmpl", data); err != nil {
+			return written, err
+		}
+		if err := generate(fmt.Sprintf("internal/services/%s_api.go", group.File), "service.go.tmpl", data); err != nil {
+			return written, err
+		}
+
+		impl := fmt.Sprintf("internal/services/%s_api_impl.go", group.File)
+		changed, err := g.mergeStubs(impl, data)
+		if err != nil {
+			return written, fmt.Errorf("failed to update %s: %w", impl, err)
+		}
+		if changed {
+			written = append(written, filepath.Join(g.ProjectName, impl))
+		} else {
+			g.Report.AddExisting(filepath.Join(g.ProjectName, impl), "implements every operation")
+		}
+	}
+
+	sort.Strings(written)
+	return written, nil
+}
+
+// writeGenerated renders a generated file, refusing to replace a file that
+// does not carry the generated header
+func (g *TemplateGenerator) writeGenerated(file, templatePath string, data interface{}) error {
+	if g.exists(file) {
+		content, err := g.readFile(file)
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(content, []byte(openAPIHeader)) {
+			return WithCode(CodeConflict, fmt.Errorf("refusing to overwrite %s: it was not generated by scaffold import openapi", file))
+		}
+	}
+	return g.renderFile(file, templatePath, data)
+}
+
+// mergeStubs creates the service implementation for a group, or appends
+// stubs for operations it does not implement yet. It reports whether the
+// file changed.
+func (g *TemplateGenerator) mergeStubs(file string, data apiGroupData) (bool, error) {
+	tmpl := openAPITemplates + "service_impl.go.tmpl"
+	if !g.exists(file) {
+		return true, g.renderFile(file, tmpl, data)
+	}
+
+	path := filepath.Join(g.ProjectName, file)
+	content, err := g.readFile(file)
+	if err != nil {
+		return false, err
+	}
+
+	implemented, err := methodNames(path, content, data.Group.Receiver())
+	if err != nil {
+		return false, err
+	}
+
+	var missing []APIOperation
+	for _, op := range data.Group.Operations {
+		if !implemented[op.Name] {
+			missing = append(missing, op)
+		}
+	}
+	if len(missing) == 0 {
+		return false, nil
+	}
+
+	imports := []string{"context", data.Module + "/pkg/errors"}
+	for _, op := range missing {
+		stub, err := g.executeTemplate(tmpl, "stub", newAPIStub(data.Group, op))
+		if err != nil {
+			return false, err
+		}
+		content = append(bytes.TrimRight(content, "\n"), stub...)
+		content = append(content, '\n')
+
+		signature := op.Signature()
+		for qualifier, pkg := range map[string]string{"dto.": data.Module + "/internal/dto", "time.": "time", "json.": "encoding/json"} {
+			if strings.Contains(signature, qualifier) {
+				imports = append(imports, pkg)
+			}
+		}
+	}
+
+	content, err = addImports(path, content, imports)
+	if err != nil {
+		return false, err
+	}
+	if formatted, fmtErr := format.Source(content); fmtErr == nil {
+		content = formatted
+	}
+
+	return true, g.writeOutput(file, content)
+}
+
+// methodNames returns the names of the methods declared on receiver
+func methodNames(path string, content []byte, receiver string) (map[string]bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, content, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	names := make(map[string]bool)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+			continue
+		}
+
+		typ := fn.Recv.List[0].Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		if ident, ok := typ.(*ast.Ident); ok && ident.Name == receiver {
+			names[fn.Name.Name] = true
+		}
+	}
+	return names, nil
+}
+
+// addImports adds any of paths the file does not import yet
+func addImports(path string, content []byte, paths []string) ([]byte, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, content, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	present := make(map[string]bool)
+	for _, spec := range file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil {
+			present[p] = true
+		}
+	}
+
+	var lines []string
+	for _, p := range paths {
+		if !present[p] {
+			lines = append(lines, "\t"+strconv.Quote(p)+"\n")
+			present[p] = true
+		}
+	}
+	if len(lines) == 0 {
+		return content, nil
+	}
+
+	if i := bytes.Index(content, []byte("import (\n")); i >= 0 {
+		at := i + len("import (\n")
+		return append(content[:at:at], append([]byte(strings.Join(lines, "")), content[at:]...)...), nil
+	}
+
+	// No import block yet; add one after the package clause
+	end := bytes.IndexByte(content[bytes.Index(content, []byte("package ")):], '\n')
+	at := bytes.Index(content, []byte("package ")) + end + 1
+	block := "\nimport (\n" + strings.Join(lines, "") + ")\n"
+	return append(content[:at:at], append([]byte(block), content[at:]...)...), nil
+}
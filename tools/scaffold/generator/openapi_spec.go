@@ -0,0 +1,482 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPISpecFile is where the OpenAPI document describing the generated
+// resource routes is written
+const OpenAPISpecFile = "api/openapi.yaml"
+
+// specDocument is the subset of an OpenAPI 3.1 document needed to describe
+// the generated CRUD routes
+type specDocument struct {
+	OpenAPI    string                   `yaml:"openapi"`
+	Info       specInfo                 `yaml:"info"`
+	Servers    []specServer             `yaml:"servers"`
+	Security   []map[string][]string    `yaml:"security,omitempty"`
+	Tags       []specTag                `yaml:"tags,omitempty"`
+	Paths      map[string]*specPathItem `yaml:"paths"`
+	Components specComponents           `yaml:"components"`
+}
+
+type specInfo struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type specServer struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type specTag struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type specPathItem struct {
+	Parameters []specParameter `yaml:"parameters,omitempty"`
+	Get        *specOperation  `yaml:"get,omitempty"`
+	Post       *specOperation  `yaml:"post,omitempty"`
+	Put        *specOperation  `yaml:"put,omitempty"`
+	Delete     *specOperation  `yaml:"delete,omitempty"`
+}
+
+type specOperation struct {
+	OperationID string                   `yaml:"operationId"`
+	Summary     string                   `yaml:"summary"`
+	Tags        []string                 `yaml:"tags"`
+	Parameters  []specParameter          `yaml:"parameters,omitempty"`
+	RequestBody *specRequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*specResponse `yaml:"responses"`
+}
+
+type specParameter struct {
+	Name        string      `yaml:"name"`
+	In          string      `yaml:"in"`
+	Description string      `yaml:"description,omitempty"`
+	Required    bool        `yaml:"required,omitempty"`
+	Schema      *specSchema `yaml:"schema"`
+}
+
+type specRequestBody struct {
+	Required bool                 `yaml:"required"`
+	Content  map[string]specMedia `yaml:"content"`
+}
+
+type specResponse struct {
+	Ref         string               `yaml:"$ref,omitempty"`
+	Description string               `yaml:"description,omitempty"`
+	Content     map[string]specMedia `yaml:"content,omitempty"`
+}
+
+type specMedia struct {
+	Schema *specSchema `yaml:"schema"`
+}
+
+type specComponents struct {
+	Schemas         map[string]*specSchema        `yaml:"schemas"`
+	Responses       map[string]*specResponse      `yaml:"responses"`
+	SecuritySchemes map[string]specSecurityScheme `yaml:"securitySchemes,omitempty"`
+}
+
+type specSecurityScheme struct {
+	Type         string `yaml:"type"`
+	Scheme       string `yaml:"scheme"`
+	BearerFormat string `yaml:"bearerFormat,omitempty"`
+}
+
+// specSchema is a JSON Schema. Type is a string, or a list including
+// "null" for nullable values.
+type specSchema struct {
+	Ref              string         `yaml:"$ref,omitempty"`
+	Type             interface{}    `yaml:"type,omitempty"`
+	Format           string         `yaml:"format,omitempty"`
+	Description      string         `yaml:"description,omitempty"`
+	Enum             []interface{}  `yaml:"enum,omitempty"`
+	Default          interface{}    `yaml:"default,omitempty"`
+	Pattern          string         `yaml:"pattern,omitempty"`
+	MinLength        *int           `yaml:"minLength,omitempty"`
+	MaxLength        *int           `yaml:"maxLength,omitempty"`
+	Minimum          *float64       `yaml:"minimum,omitempty"`
+	Maximum          *float64       `yaml:"maximum,omitempty"`
+	ExclusiveMinimum *float64       `yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64       `yaml:"exclusiveMaximum,omitempty"`
+	ReadOnly         bool           `yaml:"readOnly,omitempty"`
+	Items            *specSchema    `yaml:"items,omitempty"`
+	Required         []string       `yaml:"required,omitempty"`
+	Properties       specProperties `yaml:"properties,omitempty"`
+}
+
+// specProperties keeps object properties in field order; a map would be
+// written sorted by name
+type specProperties []specProperty
+
+type specProperty struct {
+	Name   string
+	Schema *specSchema
+}
+
+// MarshalYAML writes the properties as a mapping in declaration order
+func (p specProperties) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, prop := range p {
+		value := &yaml.Node{}
+		if err := value.Encode(prop.Schema); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: prop.Name}, value)
+	}
+	return node, nil
+}
+
+func schemaRef(name string) *specSchema {
+	return &specSchema{Ref: "#/components/schemas/" + name}
+}
+
+func responseRef(name string) *specResponse {
+	return &specResponse{Ref: "#/components/responses/" + name}
+}
+
+func jsonContent(schema *specSchema) map[string]specMedia {
+	return map[string]specMedia{"application/json": {Schema: schema}}
+}
+
+func intPtr(n int) *int { return &n }
+
+func floatPtr(n float64) *float64 { return &n }
+
+// GenerateOpenAPISpec writes the OpenAPI document for resources and returns
+// the path written. The document is rebuilt from every resource each time
+// so it always matches the generated routes.
+func (g *TemplateGenerator) GenerateOpenAPISpec(title string, resources []Resource) (string, error) {
+	content, err := OpenAPISpec(title, resources, g.Features["auth"])
+	if err != nil {
+		return "", err
+	}
+
+	if err := g.writeOutput(OpenAPISpecFile, content); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", OpenAPISpecFile, err)
+	}
+	return filepath.Join(g.ProjectName, OpenAPISpecFile), nil
+}
+
+// OpenAPISpec renders an OpenAPI 3.1 document describing the CRUD routes of
+// resources. Secured documents require a bearer token on every operation.
+func OpenAPISpec(title string, resources []Resource, secured bool) ([]byte, error) {
+	doc := &specDocument{
+		OpenAPI: "3.1.0",
+		Info:    specInfo{Title: title + " API", Version: "1.0.0"},
+		Servers: []specServer{{URL: "/api/v1"}},
+		Paths:   make(map[string]*specPathItem),
+		Components: specComponents{
+			Schemas: map[string]*specSchema{
+				"Error": {
+					Type:     "object",
+					Required: []string{"code", "message"},
+					Properties: specProperties{
+						{"code", &specSchema{Type: "string"}},
+						{"message", &specSchema{Type: "string"}},
+						{"details", &specSchema{Type: "object"}},
+					},
+				},
+				"Pagination": {
+					Type:     "object",
+					Required: []string{"offset", "limit", "total"},
+					Properties: specProperties{
+						{"offset", &specSchema{Type: "integer"}},
+						{"limit", &specSchema{Type: "integer"}},
+						{"total", &specSchema{Type: "integer"}},
+					},
+				},
+			},
+			Responses: map[string]*specResponse{
+				"BadRequest":    {Description: "Invalid input", Content: jsonContent(schemaRef("Error"))},
+				"NotFound":      {Description: "Resource not found", Content: jsonContent(schemaRef("Error"))},
+				"InternalError": {Description: "Internal server error", Content: jsonContent(schemaRef("Error"))},
+			},
+		},
+	}
+
+	if secured {
+		doc.Security = []map[string][]string{{"bearerAuth": {}}}
+		doc.Components.SecuritySchemes = map[string]specSecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+		doc.Components.Responses["Unauthorized"] = &specResponse{
+			Description: "Missing or invalid bearer token",
+			Content:     jsonContent(schemaRef("Error")),
+		}
+	}
+
+	for _, res := range resources {
+		if err := addResourceSpec(doc, res, secured); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// addResourceSpec adds the schemas and paths for one resource, matching the
+// routes registered by the generated handler
+func addResourceSpec(doc *specDocument, res Resource, secured bool) error {
+	name := res.Name
+	if _, exists := doc.Components.Schemas[name]; exists {
+		return fmt.Errorf("resource %s is defined more than once", name)
+	}
+
+	route := res.Route()
+	plural := toPascalCase(pluralize(toSnakeCase(name)))
+
+	model := &specSchema{
+		Type:       "object",
+		Required:   []string{"id"},
+		Properties: specProperties{{"id", &specSchema{Type: "integer", Format: "int64", ReadOnly: true}}},
+	}
+	input := &specSchema{Type: "object"}
+	sortable := []interface{}{"id"}
+
+	for _, f := range res.Fields {
+		model.Properties = append(model.Properties, specProperty{f.Column(), f.specSchema()})
+		input.Properties = append(input.Properties, specProperty{f.Column(), f.specSchema()})
+		if f.Required {
+			model.Required = append(model.Required, f.Column())
+			// A missing bool binds as false, so it is never required
+			if f.Type != "bool" {
+				input.Required = append(input.Required, f.Column())
+			}
+		}
+		sortable = append(sortable, f.Column())
+	}
+
+	for _, col := range []string{"created_at", "updated_at"} {
+		model.Properties = append(model.Properties, specProperty{col, &specSchema{Type: "string", Format: "date-time", ReadOnly: true}})
+		model.Required = append(model.Required, col)
+		sortable = append(sortable, col)
+	}
+
+	doc.Components.Schemas[name] = model
+	doc.Components.Schemas[name+"Input"] = input
+	doc.Components.Schemas[name+"List"] = &specSchema{
+		Type:     "object",
+		Required: []string{"data", "pagination"},
+		Properties: specProperties{
+			{"data", &specSchema{Type: "array", Items: schemaRef(name)}},
+			{"pagination", schemaRef("Pagination")},
+		},
+	}
+
+	doc.Tags = append(doc.Tags, specTag{Name: route, Description: "Manage " + res.TableName()})
+
+	body := &specRequestBody{Required: true, Content: jsonContent(schemaRef(name + "Input"))}
+	responses := func(status, description, schema string, errs ...string) map[string]*specResponse {
+		r := map[string]*specResponse{status: {Description: description}}
+		if schema != "" {
+			r[status].Content = jsonContent(schemaRef(schema))
+		}
+		for _, e := range errs {
+			r[e] = responseRef(errorResponses[e])
+		}
+		if secured {
+			r["401"] = responseRef("Unauthorized")
+		}
+		return r
+	}
+	tags := []string{route}
+
+	doc.Paths["/"+route] = &specPathItem{
+		Get: &specOperation{
+			OperationID: "list" + plural,
+			Summary:     "List " + strings.ToLower(plural),
+			Tags:        tags,
+			Parameters: []specParameter{
+				{Name: "offset", In: "query", Description: "Number of items to skip", Schema: &specSchema{Type: "integer", Minimum: floatPtr(0), Default: 0}},
+				{Name: "limit", In: "query", Description: "Maximum number of items to return", Schema: &specSchema{Type: "integer", Minimum: floatPtr(1), Maximum: floatPtr(100), Default: 10}},
+				{Name: "sort_by", In: "query", Description: "Column to sort by", Schema: &specSchema{Type: "string", Enum: sortable, Default: "id"}},
+				{Name: "sort_dir", In: "query", Description: "Sort direction", Schema: &specSchema{Type: "string", Enum: []interface{}{"asc", "desc"}, Default: "asc"}},
+			},
+			Responses: responses("200", "A page of "+strings.ToLower(plural), name+"List", "400", "500"),
+		},
+		Post: &specOperation{
+			OperationID: "create" + name,
+			Summary:     "Create a " + res.Resource,
+			Tags:        tags,
+			RequestBody: body,
+			Responses:   responses("201", "Created "+res.Resource, name, "400", "500"),
+		},
+	}
+
+	doc.Paths["/"+route+"/{id}"] = &specPathItem{
+		Parameters: []specParameter{{
+			Name:        "id",
+			In:          "path",
+			Description: name + " ID",
+			Required:    true,
+			Schema:      &specSchema{Type: "integer", Format: "int64", Minimum: floatPtr(1)},
+		}},
+		Get: &specOperation{
+			OperationID: "get" + name,
+			Summary:     "Get a " + res.Resource + " by ID",
+			Tags:        tags,
+			Responses:   responses("200", name+" found", name, "400", "404", "500"),
+		},
+		Put: &specOperation{
+			OperationID: "update" + name,
+			Summary:     "Update a " + res.Resource,
+			Tags:        tags,
+			RequestBody: body,
+			Responses:   responses("200", "Updated "+res.Resource, name, "400", "404", "500"),
+		},
+		Delete: &specOperation{
+			OperationID: "delete" + name,
+			Summary:     "Delete a " + res.Resource,
+			Tags:        tags,
+			Responses:   responses("204", "Deleted", "", "400", "404", "500"),
+		},
+	}
+
+	return nil
+}
+
+// errorResponses maps error status codes to their shared responses
+var errorResponses = map[string]string{
+	"400": "BadRequest",
+	"404": "NotFound",
+	"500": "InternalError",
+}
+
+// specSchema returns the JSON Schema for the field, including the
+// constraints its binding rules enforce
+func (f Field) specSchema() *specSchema {
+	s := &specSchema{}
+	var typ string
+	switch f.Type {
+	case "int":
+		typ, s.Format = "integer", "int64"
+	case "float":
+		typ, s.Format = "number", "double"
+	case "bool":
+		typ = "boolean"
+	case "time":
+		typ, s.Format = "string", "date-time"
+	case "uuid":
+		typ, s.Format = "string", "uuid"
+	case "ref":
+		typ, s.Format = "integer", "int64"
+		s.Minimum = floatPtr(1)
+		s.Description = "ID of the referenced " + f.References + " row"
+	case "json":
+		// Any JSON value, including null
+		s.Description = "Arbitrary JSON"
+	default:
+		typ = "string"
+	}
+
+	numeric := typ == "integer" || typ == "number"
+	for _, rule := range f.Validations {
+		key, value, _ := strings.Cut(rule, "=")
+		n, err := strconv.ParseFloat(value, 64)
+		hasNumber := err == nil
+
+		switch {
+		case key == "oneof":
+			for _, v := range f.Enum {
+				s.Enum = append(s.Enum, v)
+			}
+		case key == "email":
+			s.Format = "email"
+		case key == "url" || key == "uri":
+			s.Format = "uri"
+		case key == "uuid" || key == "uuid4":
+			s.Format = "uuid"
+		case key == "ipv4" || key == "ipv6" || key == "hostname":
+			s.Format = key
+		case key == "alpha":
+			s.Pattern = "^[a-zA-Z]+$"
+		case key == "alphanum":
+			s.Pattern = "^[a-zA-Z0-9]+$"
+		case !hasNumber:
+			continue
+		case numeric:
+			switch key {
+			case "min", "gte":
+				s.Minimum = floatPtr(n)
+			case "max", "lte":
+				s.Maximum = floatPtr(n)
+			case "gt":
+				s.ExclusiveMinimum = floatPtr(n)
+			case "lt":
+				s.ExclusiveMaximum = floatPtr(n)
+			}
+		case typ == "string":
+			switch key {
+			case "min", "gte":
+				s.MinLength = intPtr(int(n))
+			case "max", "lte":
+				s.MaxLength = intPtr(int(n))
+			case "gt":
+				s.MinLength = intPtr(int(n) + 1)
+			case "lt":
+				s.MaxLength = intPtr(int(n) - 1)
+			case "len":
+				s.MinLength, s.MaxLength = intPtr(int(n)), intPtr(int(n))
+			}
+		}
+	}
+
+	if f.HasDefault {
+		s.Default = f.specDefault()
+	}
+
+	if typ == "" {
+		return s
+	}
+	if f.Nullable() {
+		s.Type = []string{typ, "null"}
+		if len(s.Enum) > 0 {
+			s.Enum = append(s.Enum, nil)
+		}
+	} else {
+		s.Type = typ
+	}
+	return s
+}
+
+// specDefault converts the field default to a JSON value, or nil when the
+// default cannot be represented (such as a database function)
+func (f Field) specDefault() interface{} {
+	switch f.Type {
+	case "int", "ref":
+		if n, err := strconv.ParseInt(f.Default, 10, 64); err == nil {
+			return n
+		}
+	case "float":
+		if n, err := strconv.ParseFloat(f.Default, 64); err == nil {
+			return n
+		}
+	case "bool":
+		if b, err := strconv.ParseBool(f.Default); err == nil {
+			return b
+		}
+	case "string", "text", "enum", "uuid":
+		return f.Default
+	}
+	return nil
+}
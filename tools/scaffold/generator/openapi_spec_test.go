@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFieldSpecSchema(t *testing.T) {
+	tests := []struct {
+		def  string
+		want map[string]interface{}
+	}{
+		{"name:string:required,min=2,max=100", map[string]interface{}{"type": "string", "minLength": 2, "maxLength": 100}},
+		{"email:string:required,email", map[string]interface{}{"type": "string", "format": "email"}},
+		{"code:string:len=8,alphanum", map[string]interface{}{"type": []interface{}{"string", "null"}, "minLength": 8, "maxLength": 8, "pattern": "^[a-zA-Z0-9]+$"}},
+		{"age:int:required,gte=0,lt=150", map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0, "exclusiveMaximum": 150}},
+		{"price:float:required,gt=0,default=9.5", map[string]interface{}{"type": "number", "format": "double", "exclusiveMinimum": 0, "default": 9.5}},
+		{"status:enum:required,oneof=draft|live,default=draft", map[string]interface{}{"type": "string", "enum": []interface{}{"draft", "live"}, "default": "draft"}},
+		{"kind:enum:oneof=a|b", map[string]interface{}{"type": []interface{}{"string", "null"}, "enum": []interface{}{"a", "b", nil}}},
+		{"active:bool:required,default=true", map[string]interface{}{"type": "boolean", "default": true}},
+		{"owner_id:ref:required,ref=users", map[string]interface{}{"type": "integer", "format": "int64", "minimum": 1, "description": "ID of the referenced users row"}},
+		{"data:json", map[string]interface{}{"description": "Arbitrary JSON"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.def, func(t *testing.T) {
+			f, err := ParseField(tt.def)
+			if err != nil {
+				t.Fatalf("ParseField failed: %v", err)
+			}
+
+			content, err := yaml.Marshal(f.specSchema())
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			var got map[string]interface{}
+			if err := yaml.Unmarshal(content, &got); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	fields, err := ParseFields("name:string:required,max=100 active:bool:required")
+	if err != nil {
+		t.Fatalf("ParseFields failed: %v", err)
+	}
+	category, _ := NewResource("Category", fields)
+	orderItem, _ := NewResource("order_item", fields)
+
+	content, err := OpenAPISpec("shop", []Resource{category, orderItem}, true)
+	if err != nil {
+		t.Fatalf("OpenAPISpec failed: %v", err)
+	}
+
+	var doc struct {
+		OpenAPI    string                          `yaml:"openapi"`
+		Security   []map[string][]string           `yaml:"security"`
+		Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Required   []string  `yaml:"required"`
+				Properties yaml.Node `yaml:"properties"`
+			} `yaml:"schemas"`
+			SecuritySchemes map[string]interface{} `yaml:"securitySchemes"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("Generated document is not valid YAML: %v\n%s", err, content)
+	}
+
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %s", doc.OpenAPI)
+	}
+	if len(doc.Security) != 1 || doc.Components.SecuritySchemes["bearerAuth"] == nil {
+		t.Errorf("Expected bearerAuth security, got %v", doc.Security)
+	}
+
+	operations := []struct{ path, method, id string }{
+		{"/categories", "get", "listCategories"},
+		{"/categories", "post", "createCategory"},
+		{"/categories/{id}", "get", "getCategory"},
+		{"/categories/{id}", "put", "updateCategory"},
+		{"/order-items/{id}", "delete", "deleteOrderItem"},
+	}
+	for _, want := range operations {
+		var op struct {
+			OperationID string                 `yaml:"operationId"`
+			Responses   map[string]interface{} `yaml:"responses"`
+		}
+		node := doc.Paths[want.path][want.method]
+		if err := node.Decode(&op); err != nil || op.OperationID != want.id {
+			t.Errorf("Expected %s %s to be %s, got %+v", want.method, want.path, want.id, op)
+			continue
+		}
+		if _, ok := op.Responses["401"]; !ok {
+			t.Errorf("Expected %s %s to document 401", want.method, want.path)
+		}
+	}
+
+	input := doc.Components.Schemas["CategoryInput"]
+	if !reflect.DeepEqual(input.Required, []string{"name"}) {
+		t.Errorf("Expected only name to be required on input, got %v", input.Required)
+	}
+
+	// Properties keep the field order
+	model := doc.Components.Schemas["Category"]
+	var names []string
+	for i := 0; i < len(model.Properties.Content); i += 2 {
+		names = append(names, model.Properties.Content[i].Value)
+	}
+	want := []string{"id", "name", "active", "created_at", "updated_at"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected properties %v, got %v", want, names)
+	}
+
+	if _, err := OpenAPISpec("shop", []Resource{category, category}, false); err == nil {
+		t.Error("Expected duplicate resources to be rejected")
+	}
+}
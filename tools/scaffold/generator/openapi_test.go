@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+const testOpenAPI = `
+openapi: 3.1.0
+info: {title: Store, version: 1.0.0}
+servers:
+  - url: https://api.example.com/v2/
+paths:
+  /orders/{orderId}:
+    parameters:
+      - {name: orderId, in: path, required: true, schema: {type: integer, format: int64}}
+    get:
+      operationId: get-order
+      tags: [orders]
+      parameters:
+        - {name: expand, in: query, required: true, schema: {type: array, items: {type: string}}}
+        - {name: session, in: cookie, schema: {type: string}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Order'}
+    patch:
+      tags: [orders]
+      requestBody:
+        $ref: '#/components/requestBodies/OrderPatch'
+      responses:
+        '202': {$ref: '#/components/responses/Accepted'}
+components:
+  requestBodies:
+    OrderPatch:
+      required: true
+      content:
+        application/json:
+          schema:
+            type: object
+            required: [status]
+            properties:
+              status: {type: string, enum: [open, closed]}
+              note: {type: [string, "null"], maxLength: 200}
+  responses:
+    Accepted:
+      description: accepted
+  schemas:
+    Order:
+      type: object
+      required: [id, total, paid]
+      properties:
+        id: {type: integer, format: int64}
+        total: {type: number}
+        paid: {type: boolean}
+        lines:
+          type: array
+          items: {$ref: '#/components/schemas/Line'}
+        metadata:
+          oneOf: [{type: string}, {type: integer}]
+    Line:
+      type: object
+      properties:
+        sku: {type: string}
+`
+
+func TestParseOpenAPI(t *testing.T) {
+	api, err := ParseOpenAPI([]byte(testOpenAPI))
+	if err != nil {
+		t.Fatalf("ParseOpenAPI failed: %v", err)
+	}
+
+	if api.BasePath != "/v2" {
+		t.Errorf("Expected base path /v2, got %s", api.BasePath)
+	}
+
+	if len(api.Groups) != 1 || api.Groups[0].Name != "Orders" {
+		t.Fatalf("Expected a single Orders group, got %+v", api.Groups)
+	}
+
+	signatures := []string{
+		"GetOrder(ctx context.Context, params dto.GetOrderParams) (*dto.Order, error)",
+		"PatchOrdersByOrderID(ctx context.Context, params dto.PatchOrdersByOrderIDParams, body *dto.PatchOrdersByOrderIDRequest) error",
+	}
+	for i, op := range api.Groups[0].Operations {
+		if op.Signature() != signatures[i] {
+			t.Errorf("Expected signature %s, got %s", signatures[i], op.Signature())
+		}
+	}
+
+	get := api.Groups[0].Operations[0]
+	if get.RoutePath != "/orders/{orderId}" || !get.PathParams || !get.QueryParams || get.HeaderParams {
+		t.Errorf("Unexpected path or parameter locations: %+v", get)
+	}
+	if strings.Join(get.RequiredQuery, ",") != "expand" {
+		t.Errorf("Expected expand to be a required query parameter, got %v", get.RequiredQuery)
+	}
+
+	patch := api.Groups[0].Operations[1]
+	if patch.Status != "http.StatusAccepted" || patch.ResultType != "" || !patch.BodyRequired {
+		t.Errorf("Unexpected patch operation: %+v", patch)
+	}
+
+	fields := make(map[string]APIField)
+	for _, typ := range api.Types {
+		for _, f := range typ.Fields {
+			fields[typ.Name+"."+f.Name] = f
+		}
+	}
+
+	tests := []struct {
+		field string
+		typ   string
+		tag   string
+	}{
+		{"Order.ID", "int64", `json:"id" binding:"required"`},
+		{"Order.Paid", "bool", `json:"paid"`},
+		{"Order.Lines", "[]Line", `json:"lines,omitempty"`},
+		{"Order.Metadata", "json.RawMessage", `json:"metadata,omitempty"`},
+		{"GetOrderParams.OrderID", "int64", `path:"orderId"`},
+		{"GetOrderParams.Expand", "[]string", `query:"expand"`},
+		{"PatchOrdersByOrderIDRequest.Status", "string", `json:"status" binding:"required,oneof=open closed"`},
+		{"PatchOrdersByOrderIDRequest.Note", "*string", `json:"note,omitempty" binding:"omitempty,max=200"`},
+	}
+	for _, tt := range tests {
+		f, ok := fields[tt.field]
+		if !ok {
+			t.Errorf("Missing field %s", tt.field)
+			continue
+		}
+		if f.Type != tt.typ || f.Tag != tt.tag {
+			t.Errorf("%s: expected %s `%s`, got %s `%s`", tt.field, tt.typ, tt.tag, f.Type, f.Tag)
+		}
+	}
+
+	if _, ok := fields["GetOrderParams.Session"]; ok {
+		t.Error("Expected cookie parameter to be skipped")
+	}
+
+	warnings := strings.Join(api.Warnings, "\n")
+	for _, want := range []string{"cookie parameter session", "OrderMetadata: oneOf and anyOf"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected warning containing %q, got:\n%s", want, warnings)
+		}
+	}
+
+	if !api.HasImport("encoding/json") || api.HasImport("time") {
+		t.Error("Expected DTOs to import encoding/json only")
+	}
+}
+
+func TestParseOpenAPIErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"swagger 2", "swagger: '2.0'\npaths: {}", "only OpenAPI 3"},
+		{"no paths", "openapi: 3.0.0\ninfo: {title: x}", "no paths"},
+		{
+			"unresolved ref",
+			"openapi: 3.0.0\npaths:\n  /a:\n    get:\n      responses:\n        '200':\n          description: ok\n          content:\n            application/json:\n              schema: {$ref: '#/components/schemas/Missing'}",
+			"unresolved schema reference",
+		},
+		{
+			"duplicate operation",
+			"openapi: 3.0.0\npaths:\n  /a:\n    get: {operationId: list, responses: {'204': {description: ok}}}\n  /b:\n    get: {operationId: list, responses: {'204': {description: ok}}}",
+			"already used",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOpenAPI([]byte(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	tests := map[string]string{
+		"GET /pets":                 "GetPets",
+		"DELETE /pets/{petId}":      "DeletePetsByPetID",
+		"POST /v1/user-groups/{id}": "PostV1UserGroupsByID",
+	}
+	for in, want := range tests {
+		method, path, _ := strings.Cut(in, " ")
+		if got := operationName(method, path); got != want {
+			t.Errorf("operationName(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Postman files written next to the OpenAPI document
+const (
+	PostmanCollectionFile  = "api/postman_collection.json"
+	PostmanEnvironmentFile = "api/postman_environment.json"
+)
+
+// exampleUUID is a version 4 UUID, so it also passes uuid4 validation
+const exampleUUID = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// postmanCollection is a Postman v2.1 collection
+type postmanCollection struct {
+	Info postmanInfo   `json:"info"`
+	Auth *postmanAuth  `json:"auth,omitempty"`
+	Item []postmanItem `json:"item"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanVariable `json:"bearer,omitempty"`
+}
+
+// postmanItem is either a folder (Item set) or a request
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item,omitempty"`
+	Request *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header []postmanHeader `json:"header"`
+	Body   *postmanBody    `json:"body,omitempty"`
+	URL    postmanURL      `json:"url"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanBody struct {
+	Mode    string `json:"mode"`
+	Raw     string `json:"raw"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanQuery    `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanQuery struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type postmanVariable struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+// postmanEnvironment is a Postman environment export
+type postmanEnvironment struct {
+	Name   string            `json:"name"`
+	Values []postmanVariable `json:"values"`
+	Scope  string            `json:"_postman_variable_scope"`
+}
+
+// GeneratePostman writes a Postman collection with a folder per resource
+// and an environment for a local server, returning the paths written
+func (g *TemplateGenerator) GeneratePostman(title string, resources []Resource) ([]string, error) {
+	secured := g.Features["auth"]
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{PostmanCollectionFile, newPostmanCollection(title, resources, secured)},
+		{PostmanEnvironmentFile, newPostmanEnvironment(title, secured)},
+	}
+
+	var written []string
+	for _, f := range files {
+		// Keep & in query strings readable
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.value); err != nil {
+			return written, fmt.Errorf("failed to encode %s: %w", f.name, err)
+		}
+		if err := g.writeOutput(f.name, buf.Bytes()); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+		written = append(written, filepath.Join(g.ProjectName, f.name))
+	}
+	return written, nil
+}
+
+// newPostmanCollection builds the collection for resources. Secured
+// collections send the {{token}} environment variable as a bearer token.
+func newPostmanCollection(title string, resources []Resource, secured bool) *postmanCollection {
+	c := &postmanCollection{
+		Info: postmanInfo{Name: title + " API", Schema: postmanSchema},
+		Item: []postmanItem{},
+	}
+	if secured {
+		c.Auth = &postmanAuth{
+			Type:   "bearer",
+			Bearer: []postmanVariable{{Key: "token", Value: "{{token}}", Type: "string"}},
+		}
+	}
+
+	for _, res := range resources {
+		c.Item = append(c.Item, postmanFolder(res))
+	}
+	return c
+}
+
+// newPostmanEnvironment returns the variables used by the collection, pointed
+// at a local server
+func newPostmanEnvironment(title string, secured bool) *postmanEnvironment {
+	enabled := true
+	env := &postmanEnvironment{
+		Name:   title + " local",
+		Values: []postmanVariable{{Key: "baseUrl", Value: "http://localhost:8080/api/v1", Type: "default", Enabled: &enabled}},
+		Scope:  "environment",
+	}
+	if secured {
+		env.Values = append(env.Values, postmanVariable{Key: "token", Value: "", Type: "secret", Enabled: &enabled})
+	}
+	return env
+}
+
+// postmanFolder returns the CRUD requests for a resource
+func postmanFolder(res Resource) postmanItem {
+	route := res.Route()
+	body := exampleBody(res.Fields)
+
+	collection := postmanURL{
+		Raw:  "{{baseUrl}}/" + route,
+		Host: []string{"{{baseUrl}}"},
+		Path: []string{route},
+	}
+	member := postmanURL{
+		Raw:      "{{baseUrl}}/" + route + "/:id",
+		Host:     []string{"{{baseUrl}}"},
+		Path:     []string{route, ":id"},
+		Variable: []postmanVariable{{Key: "id", Value: "1"}},
+	}
+	list := collection
+	list.Raw += "?offset=0&limit=10"
+	list.Query = []postmanQuery{
+		{Key: "offset", Value: "0"},
+		{Key: "limit", Value: "10"},
+		{Key: "sort_by", Value: "id", Disabled: true},
+		{Key: "sort_dir", Value: "asc", Disabled: true},
+	}
+
+	request := func(name, method string, url postmanURL, withBody bool) postmanItem {
+		req := &postmanRequest{Method: method, Header: []postmanHeader{}, URL: url}
+		if withBody {
+			req.Header = append(req.Header, postmanHeader{Key: "Content-Type", Value: "application/json"})
+			req.Body = &postmanBody{Mode: "raw", Raw: body}
+			req.Body.Options.Raw.Language = "json"
+		}
+		return postmanItem{Name: name, Request: req}
+	}
+
+	return postmanItem{
+		Name: route,
+		Item: []postmanItem{
+			request("List "+strings.ReplaceAll(route, "-", " "), "GET", list, false),
+			request("Create "+res.Resource, "POST", collection, true),
+			request("Get "+res.Resource, "GET", member, false),
+			request("Update "+res.Resource, "PUT", member, true),
+			request("Delete "+res.Resource, "DELETE", member, false),
+		},
+	}
+}
+
+// exampleBody renders a JSON request body with an example value for every
+// field, in field order
+func exampleBody(fields []Field) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i, f := range fields {
+		value, err := json.Marshal(f.exampleValue())
+		if err != nil {
+			value = []byte("null")
+		}
+		fmt.Fprintf(&b, "  %q: %s", f.Column(), value)
+		if i < len(fields)-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// exampleValue returns a value for the field that passes its validation
+// rules, preferring the field default
+func (f Field) exampleValue() interface{} {
+	if f.HasDefault {
+		if v := f.specDefault(); v != nil {
+			return v
+		}
+	}
+
+	switch f.Type {
+	case "int":
+		if n, ok := f.ruleNumber("min", "gte"); ok {
+			return int64(n)
+		}
+		if n, ok := f.ruleNumber("gt"); ok {
+			return int64(n) + 1
+		}
+		return 1
+	case "float":
+		if n, ok := f.ruleNumber("min", "gte", "gt"); ok {
+			return n + 0.5
+		}
+		return 9.99
+	case "bool":
+		return true
+	case "time":
+		return "2024-01-01T00:00:00Z"
+	case "enum":
+		return f.Enum[0]
+	case "uuid":
+		return exampleUUID
+	case "json":
+		return map[string]interface{}{}
+	case "ref":
+		return 1
+	}
+
+	for _, rule := range f.Validations {
+		switch rule {
+		case "email":
+			return "user@example.com"
+		case "url", "uri":
+			return "https://example.com"
+		case "uuid", "uuid4":
+			return exampleUUID
+		}
+	}
+
+	// Fit the field name to any length rules
+	value := strings.ReplaceAll(f.Column(), "_", "")
+	if n, ok := f.ruleNumber("len"); ok {
+		return strings.Repeat("a", int(n))
+	}
+	if n, ok := f.ruleNumber("min", "gte"); ok && len(value) < int(n) {
+		value += strings.Repeat("a", int(n)-len(value))
+	}
+	if n, ok := f.ruleNumber("max", "lte"); ok && len(value) > int(n) {
+		value = value[:int(n)]
+	}
+	return value
+}
+
+// ruleNumber returns the numeric argument of the first of keys present in
+// the field's validations
+func (f Field) ruleNumber(keys ...string) (float64, bool) {
+	for _, rule := range f.Validations {
+		key, value, ok := strings.Cut(rule, "=")
+		if !ok {
+			continue
+		}
+		for _, k := range keys {
+			if key == k {
+				n, err := strconv.ParseFloat(value, 64)
+				return n, err == nil
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExampleValue(t *testing.T) {
+	tests := []struct {
+		def  string
+		want interface{}
+	}{
+		{"name:string:required,max=4", "name"},
+		{"title:string:min=8", "titleaaa"},
+		{"code:string:len=3", "aaa"},
+		{"email:string:required,email", "user@example.com"},
+		{"site:string:url", "https://example.com"},
+		{"age:int:gte=18", int64(18)},
+		{"rank:int:gt=0", int64(1)},
+		{"price:float:gt=0", 0.5},
+		{"stock:int:default=5", int64(5)},
+		{"status:enum:oneof=draft|live", "draft"},
+		{"status:enum:oneof=draft|live,default=live", "live"},
+		{"active:bool:default=false", false},
+		{"owner_id:ref:ref=users", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.def, func(t *testing.T) {
+			f, err := ParseField(tt.def)
+			if err != nil {
+				t.Fatalf("ParseField failed: %v", err)
+			}
+			if got := f.exampleValue(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPostmanCollection(t *testing.T) {
+	fields, err := ParseFields("name:string:required data:json")
+	if err != nil {
+		t.Fatalf("ParseFields failed: %v", err)
+	}
+	res, _ := NewResource("OrderItem", fields)
+
+	c := newPostmanCollection("shop", []Resource{res}, true)
+	if c.Auth == nil || c.Auth.Bearer[0].Value != "{{token}}" {
+		t.Errorf("Expected bearer auth from the token variable, got %+v", c.Auth)
+	}
+	if len(c.Item) != 1 || c.Item[0].Name != "order-items" {
+		t.Fatalf("Expected an order-items folder, got %+v", c.Item)
+	}
+
+	var names []string
+	for _, item := range c.Item[0].Item {
+		names = append(names, item.Request.Method+" "+item.Request.URL.Raw)
+	}
+	want := []string{
+		"GET {{baseUrl}}/order-items?offset=0&limit=10",
+		"POST {{baseUrl}}/order-items",
+		"GET {{baseUrl}}/order-items/:id",
+		"PUT {{baseUrl}}/order-items/:id",
+		"DELETE {{baseUrl}}/order-items/:id",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected requests:\n%v\ngot:\n%v", want, names)
+	}
+
+	body := c.Item[0].Item[1].Request.Body.Raw
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		t.Fatalf("Example body is not valid JSON: %v\n%s", err, body)
+	}
+	if decoded["name"] != "name" || !reflect.DeepEqual(decoded["data"], map[string]interface{}{}) {
+		t.Errorf("Unexpected example body:\n%s", body)
+	}
+
+	env := newPostmanEnvironment("shop", true)
+	if len(env.Values) != 2 || env.Values[1].Key != "token" || env.Values[1].Type != "secret" {
+		t.Errorf("Expected a secret token variable, got %+v", env.Values)
+	}
+
+	if open := newPostmanCollection("shop", []Resource{res}, false); open.Auth != nil {
+		t.Errorf("Expected no auth without the auth feature, got %+v", open.Auth)
+	}
+	if env := newPostmanEnvironment("shop", false); len(env.Values) != 1 {
+		t.Errorf("Expected only baseUrl without the auth feature, got %+v", env.Values)
+	}
+}
@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProtoNumbers records the numbers of a resource's fields in its protobuf
+// messages. They are kept in the manifest, so a field keeps its number when
+// others are added or removed, and the number of a removed field is
+// reserved rather than reused.
+type ProtoNumbers struct {
+	Fields   []ProtoField `json:"fields"`
+	Reserved []ProtoField `json:"reserved,omitempty"`
+}
+
+// ProtoField is a field numbered in the input message of a resource. The
+// resource message numbers it after its id and timestamps.
+type ProtoField struct {
+	Name   string `json:"name"`
+	Number int    `json:"number"`
+	Type   string `json:"type"`
+}
+
+// protoMessageOffset is the number of common fields the resource message
+// declares before the resource's own fields
+const protoMessageOffset = 3
+
+// NumberProtoFields numbers fields following previous, the numbering the
+// resource was last generated with, or nil for a new resource. A field
+// keeps its number while its column and wire type stay the same; other
+// fields get numbers never used before. Fields that are removed or change
+// type have their numbers reserved.
+func NumberProtoFields(fields []Field, previous *ProtoNumbers) *ProtoNumbers {
+	numbers := &ProtoNumbers{Fields: make([]ProtoField, 0, len(fields))}
+	next := 1
+	used := make(map[string]ProtoField)
+	if previous != nil {
+		for _, f := range previous.Reserved {
+			next = max(next, f.Number+1)
+		}
+		for _, f := range previous.Fields {
+			next = max(next, f.Number+1)
+			used[f.Name] = f
+		}
+	}
+
+	kept := make(map[int]bool)
+	for _, f := range fields {
+		field := ProtoField{Name: f.Column(), Type: protoWireType(f)}
+		if prev, ok := used[field.Name]; ok && prev.Type == field.Type {
+			field.Number = prev.Number
+		} else {
+			field.Number = next
+			next++
+		}
+		kept[field.Number] = true
+		numbers.Fields = append(numbers.Fields, field)
+	}
+
+	if previous != nil {
+		numbers.Reserved = append(numbers.Reserved, previous.Reserved...)
+		for _, f := range previous.Fields {
+			if !kept[f.Number] {
+				numbers.Reserved = append(numbers.Reserved, f)
+			}
+		}
+	}
+	sort.Slice(numbers.Reserved, func(i, j int) bool { return numbers.Reserved[i].Number < numbers.Reserved[j].Number })
+	return numbers
+}
+
+// protoWireType returns the protobuf type of the field without its optional
+// label, which changes presence but not the wire format
+func protoWireType(f Field) string {
+	return strings.TrimPrefix(f.ProtoType(), "optional ")
+}
+
+// number returns the number of the column in the input message
+func (n *ProtoNumbers) number(column string) int {
+	for _, f := range n.Fields {
+		if f.Name == column {
+			return f.Number
+		}
+	}
+	return 0
+}
+
+// reserved returns the reserved statements of a message numbering the
+// fields offset after the input message. Names of removed fields are
+// reserved unless a current field has taken the name again.
+func (n *ProtoNumbers) reserved(offset int) []string {
+	if len(n.Reserved) == 0 {
+		return nil
+	}
+
+	current := make(map[string]bool)
+	for _, f := range n.Fields {
+		current[f.Name] = true
+	}
+	var nums, names []string
+	seen := make(map[string]bool)
+	for _, f := range n.Reserved {
+		nums = append(nums, strconv.Itoa(f.Number+offset))
+		if !current[f.Name] && !seen[f.Name] {
+			names = append(names, strconv.Quote(f.Name))
+			seen[f.Name] = true
+		}
+	}
+
+	statements := []string{"reserved " + strings.Join(nums, ", ")}
+	if len(names) > 0 {
+		statements = append(statements, "reserved "+strings.Join(names, ", "))
+	}
+	return statements
+}
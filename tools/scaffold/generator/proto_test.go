@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNumberProtoFields(t *testing.T) {
+	parse := func(spec string) []Field {
+		return mustResource(t, "User", spec).Fields
+	}
+	numbers := func(n *ProtoNumbers) (fields, reserved map[string]int) {
+		fields, reserved = make(map[string]int), make(map[string]int)
+		for _, f := range n.Fields {
+			fields[f.Name] = f.Number
+		}
+		for _, f := range n.Reserved {
+			reserved[f.Name] = f.Number
+		}
+		return fields, reserved
+	}
+
+	initial := NumberProtoFields(parse("name:string:required age:int email:string"), nil)
+
+	tests := []struct {
+		name         string
+		fields       string
+		wantFields   map[string]int
+		wantReserved map[string]int
+	}{
+		{
+			name:         "Unchanged",
+			fields:       "name:string:required age:int email:string",
+			wantFields:   map[string]int{"name": 1, "age": 2, "email": 3},
+			wantReserved: map[string]int{},
+		},
+		{
+			name:         "Reordered and optional",
+			fields:       "email:string name:string age:int",
+			wantFields:   map[string]int{"name": 1, "age": 2, "email": 3},
+			wantReserved: map[string]int{},
+		},
+		{
+			name:         "Removed and added",
+			fields:       "name:string:required email:string nickname:string",
+			wantFields:   map[string]int{"name": 1, "email": 3, "nickname": 4},
+			wantReserved: map[string]int{"age": 2},
+		},
+		{
+			name:         "Type changed",
+			fields:       "name:string:required age:float email:string",
+			wantFields:   map[string]int{"name": 1, "age": 4, "email": 3},
+			wantReserved: map[string]int{"age": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, reserved := numbers(NumberProtoFields(parse(tt.fields), initial))
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("Fields = %v, want %v", fields, tt.wantFields)
+			}
+			if !reflect.DeepEqual(reserved, tt.wantReserved) {
+				t.Errorf("Reserved = %v, want %v", reserved, tt.wantReserved)
+			}
+		})
+	}
+
+	// Numbers reserved earlier stay reserved and are never handed out again
+	removed := NumberProtoFields(parse("name:string:required email:string"), initial)
+	readded := NumberProtoFields(parse("name:string:required email:string age:int"), removed)
+	fields, reserved := numbers(readded)
+	if fields["age"] != 4 || reserved["age"] != 2 {
+		t.Errorf("Expected a re-added field to get a new number, got fields %v, reserved %v", fields, reserved)
+	}
+
+	// A name taken again is no longer reserved, but its old number is
+	got := readded.reserved(protoMessageOffset)
+	if want := []string{"reserved 5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reserved() = %v, want %v", got, want)
+	}
+	got = removed.reserved(0)
+	if want := []string{"reserved 2", `reserved "age"`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reserved() = %v, want %v", got, want)
+	}
+}
+
+func TestManifestProtoNumbers(t *testing.T) {
+	res := mustResource(t, "User", "name:string email:string")
+
+	// A resource recorded without numbers was numbered by its stored fields
+	m := &Manifest{Resources: []ResourceDefinition{{Name: "User", Fields: []string{"name:string", "age:int", "email:string"}}}}
+	numbers, err := m.ProtoNumbers(res)
+	if err != nil {
+		t.Fatalf("ProtoNumbers failed: %v", err)
+	}
+	if numbers.number("email") != 3 || len(numbers.Reserved) != 1 || numbers.Reserved[0].Name != "age" {
+		t.Errorf("Expected email to keep number 3 and age to be reserved, got %+v", numbers)
+	}
+
+	res.Proto = numbers
+	m.SetResource(res)
+	stored, err := m.Resource("User").Resource()
+	if err != nil {
+		t.Fatalf("Failed to parse stored resource: %v", err)
+	}
+	if !reflect.DeepEqual(stored.Proto, numbers) {
+		t.Errorf("Expected the numbers to be stored, got %+v", stored.Proto)
+	}
+
+	g := NewTemplateGenerator("shop", "example.com/shop", nil, nil)
+	g.Output = make(map[string][]byte)
+	g.Transports[TransportGRPC] = true
+	if _, err := g.GenerateResource(stored); err != nil {
+		t.Fatalf("Failed to generate resource: %v", err)
+	}
+	proto := string(g.Output["api/proto/user/v1/user.proto"])
+	for _, want := range []string{"optional string email = 6;", "reserved 5;", `reserved "age";`} {
+		if !strings.Contains(proto, want) {
+			t.Errorf("Expected proto to contain %q, got:\n%s", want, proto)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileAction is what a generation run did to a file
+type FileAction string
+
+// File actions recorded in a report
+const (
+	FileCreated     FileAction = "created"
+	FileOverwritten FileAction = "overwritten"
+	FileSkipped     FileAction = "skipped"
+)
+
+// Error codes let tools consuming a report tell failures apart without
+// matching messages
+const (
+	// CodeInvalidArguments means the command line was incomplete or invalid
+	CodeInvalidArguments = "invalid_arguments"
+	// CodeInvalidInput means a field spec, DDL file or OpenAPI document
+	// could not be used
+	CodeInvalidInput = "invalid_input"
+	// CodeConflict means a file the run would write is already present or
+	// not owned by the generator
+	CodeConflict = "conflict"
+	// CodeAborted means a required confirmation was declined
+	CodeAborted = "aborted"
+	// CodeGenerationFailed covers every other failure
+	CodeGenerationFailed = "generation_failed"
+)
+
+// CodedError is an error carrying a report error code
+type CodedError struct {
+	Code string
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// WithCode tags err with a report error code. A nil err stays nil.
+func WithCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// ErrorCode returns the code err was tagged with, or CodeGenerationFailed
+func ErrorCode(err error) string {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return CodeGenerationFailed
+}
+
+// ReportFile is a file written or left alone by a run
+type ReportFile struct {
+	Path   string     `json:"path"`
+	Action FileAction `json:"action"`
+	// SHA256 is the checksum of the file's content after the run
+	SHA256 string `json:"sha256,omitempty"`
+	Size   int64  `json:"size"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReportTiming is how long a phase of the run took
+type ReportTiming struct {
+	Phase      string  `json:"phase"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// ReportError is a failure with its code
+type ReportError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ReportProject describes the project settings the run applied
+type ReportProject struct {
+	Name          string   `json:"name"`
+	Module        string   `json:"module,omitempty"`
+	Database      string   `json:"database,omitempty"`
+	Router        string   `json:"router,omitempty"`
+	Logger        string   `json:"logger,omitempty"`
+	Transports    []string `json:"transports,omitempty"`
+	Deployment    string   `json:"deployment,omitempty"`
+	CI            string   `json:"ci,omitempty"`
+	DockerProfile string   `json:"docker_profile,omitempty"`
+	Features      []string `json:"features"`
+}
+
+// Report is a machine-readable account of a generation run. A nil *Report
+// records nothing, so generators without one skip the bookkeeping.
+type Report struct {
+	mu sync.Mutex
+
+	Command    string         `json:"command"`
+	Success    bool           `json:"success"`
+	Project    *ReportProject `json:"project,omitempty"`
+	Files      []ReportFile   `json:"files"`
+	Warnings   []string       `json:"warnings"`
+	Errors     []ReportError  `json:"errors"`
+	Timings    []ReportTiming `json:"timings"`
+	StartedAt  time.Time      `json:"started_at"`
+	DurationMS float64        `json:"duration_ms"`
+
+	now func() time.Time
+}
+
+// NewReport starts the report of a command
+func NewReport(command string) *Report {
+	return &Report{
+		Command:   command,
+		Files:     []ReportFile{},
+		Warnings:  []string{},
+		Errors:    []ReportError{},
+		Timings:   []ReportTiming{},
+		StartedAt: time.Now().UTC(),
+		now:       time.Now,
+	}
+}
+
+// SetProject records the settings of the generator's project
+func (r *Report) SetProject(g *TemplateGenerator) {
+	if r == nil {
+		return
+	}
+
+	features := make([]string, 0, len(g.Features))
+	for f, on := range g.Features {
+		if on && f != "" {
+			features = append(features, f)
+		}
+	}
+	sort.Strings(features)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Project = &ReportProject{
+		Name:          filepath.Base(g.ProjectName),
+		Module:        g.Module,
+		Database:      g.Database,
+		Router:        g.Router,
+		Logger:        g.Logger,
+		Transports:    g.transportList(),
+		Deployment:    g.Deployment,
+		CI:            g.CI,
+		DockerProfile: g.DockerProfile,
+		Features:      features,
+	}
+}
+
+// AddFile records a file the run wrote or skipped. content is the file's
+// content after the run.
+func (r *Report) AddFile(path string, action FileAction, content []byte, reason string) {
+	if r == nil {
+		return
+	}
+
+	sum := sha256.Sum256(content)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Files = append(r.Files, ReportFile{
+		Path:   path,
+		Action: action,
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(content)),
+		Reason: reason,
+	})
+}
+
+// AddExisting records a file left as it was, reading it for its checksum
+func (r *Report) AddExisting(path, reason string) {
+	r.addFromDisk(path, FileSkipped, reason)
+}
+
+// AddWritten records a file written outside the generator, such as the
+// manifest or go.mod. existed reports whether it was present before.
+func (r *Report) AddWritten(path string, existed bool) {
+	action := FileCreated
+	if existed {
+		action = FileOverwritten
+	}
+	r.addFromDisk(path, action, "")
+}
+
+// addFromDisk records a file with the checksum of its current content
+func (r *Report) addFromDisk(path string, action FileAction, reason string) {
+	if r == nil {
+		return
+	}
+
+	// #nosec G304 - path is a file inside the project being generated
+	content, err := os.ReadFile(path)
+	if err != nil {
+		// Record the file without a checksum rather than fail the run
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.Files = append(r.Files, ReportFile{Path: path, Action: action, Reason: reason})
+		return
+	}
+	r.AddFile(path, action, content, reason)
+}
+
+// Warn records a warning
+func (r *Report) Warn(format string, args ...interface{}) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Time starts timing a phase and returns the function ending it
+func (r *Report) Time(phase string) func() {
+	if r == nil {
+		return func() {}
+	}
+
+	start := r.now()
+	return func() {
+		elapsed := r.now().Sub(start)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.Timings = append(r.Timings, ReportTiming{Phase: phase, DurationMS: milliseconds(elapsed)})
+	}
+}
+
+// Finish records the outcome of the run
+func (r *Report) Finish(err error) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Success = err == nil
+	if err != nil {
+		r.Errors = append(r.Errors, ReportError{Code: ErrorCode(err), Message: err.Error()})
+	}
+	r.DurationMS = milliseconds(r.now().Sub(r.StartedAt))
+	sort.SliceStable(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if _, err := w.Write(append(content, '\n')); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Uncoded", errors.New("boom"), CodeGenerationFailed},
+		{"Coded", WithCode(CodeConflict, errors.New("exists")), CodeConflict},
+		{"Wrapped", fmt.Errorf("failed: %w", WithCode(CodeAborted, errors.New("no"))), CodeAborted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCode(tt.err); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if WithCode(CodeConflict, nil) != nil {
+		t.Error("Expected a nil error to stay nil")
+	}
+}
+
+func TestReportRecordsWrites(t *testing.T) {
+	project := t.TempDir()
+
+	g := NewTemplateGenerator(project, "example.com/app", nil, nil)
+	g.Report = NewReport("test")
+
+	write := func() {
+		if err := g.writeOutput("main.go", []byte("package main\n")); err != nil {
+			t.Fatalf("writeOutput failed: %v", err)
+		}
+	}
+	write()
+	write()
+
+	clock := g.Report.StartedAt
+	g.Report.now = func() time.Time { return clock.Add(1500 * time.Microsecond) }
+	g.Report.Finish(nil)
+
+	path := filepath.Join(project, "main.go")
+	sum := sha256.Sum256([]byte("package main\n"))
+	want := []ReportFile{
+		{Path: path, Action: FileCreated, SHA256: hex.EncodeToString(sum[:]), Size: 13},
+		{Path: path, Action: FileOverwritten, SHA256: hex.EncodeToString(sum[:]), Size: 13},
+	}
+	if len(g.Report.Files) != len(want) {
+		t.Fatalf("Expected %d files, got %+v", len(want), g.Report.Files)
+	}
+	for i := range want {
+		if g.Report.Files[i] != want[i] {
+			t.Errorf("File %d: expected %+v, got %+v", i, want[i], g.Report.Files[i])
+		}
+	}
+	if !g.Report.Success || g.Report.DurationMS != 1.5 {
+		t.Errorf("Expected a successful 1.5ms run, got success=%v duration=%v", g.Report.Success, g.Report.DurationMS)
+	}
+
+	// A missing file is recorded without a checksum
+	g.Report.AddExisting(filepath.Join(project, "missing.go"), "gone")
+	if f := g.Report.Files[len(g.Report.Files)-1]; f.SHA256 != "" {
+		t.Errorf("Expected no checksum for a missing file, got %q", f.SHA256)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the file to be written: %v", err)
+	}
+}
+
+func TestNilReport(t *testing.T) {
+	var r *Report
+	r.AddFile("a", FileCreated, nil, "")
+	r.Warn("ignored")
+	r.Time("phase")()
+	r.Finish(errors.New("boom"))
+}
@@ -0,0 +1,230 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resourceTemplates maps the generated files for a resource to their
+// templates. Models, repositories, protos and gRPC servers follow the schema
+// and are rewritten on every run; services and handlers hold hand-written
+// logic and are only created when missing. Files with a transport are only
+// generated when the project serves that transport.
+var resourceTemplates = []struct {
+	file      string
+	template  string
+	overwrite bool
+	transport string
+}{
+	{"internal/models/%s.go", "tools/scaffold/templates/resource/model.go.tmpl", true, ""},
+	{"internal/repository/%s_repository.go", "tools/scaffold/templates/resource/repository.go.tmpl", true, ""},
+	{"internal/services/%s_service.go", "tools/scaffold/templates/resource/service.go.tmpl", false, ""},
+	{"internal/handlers/%s_handler.go", "tools/scaffold/templates/handler.go.tmpl", false, TransportHTTP},
+	{"api/proto/%[1]s/v1/%[1]s.proto", "tools/scaffold/templates/grpc/resource.proto.tmpl", true, TransportGRPC},
+	{"internal/grpcserver/%s_server.go", "tools/scaffold/templates/grpc/resource_server.go.tmpl", true, TransportGRPC},
+}
+
+// sharedResourceTemplates are generated once per project for all resources
+var sharedResourceTemplates = map[string]string{
+	"internal/models/pagination.go":  "tools/scaffold/templates/resource/pagination.go.tmpl",
+	"internal/repository/helpers.go": "tools/scaffold/templates/resource/repository_helpers.go.tmpl",
+}
+
+// resourceData is the template data for resource files
+type resourceData struct {
+	Module   string
+	Database string
+	Name     string
+	Resource string
+	Table    string
+	Route    string
+	Base     string
+	Fields   []Field
+	Proto    *ProtoNumbers
+}
+
+// Plural returns the PascalCase plural name, used for list operations
+func (d resourceData) Plural() string {
+	return toPascalCase(pluralize(d.Base))
+}
+
+// LowerPlural returns the lower camelCase plural name used for list queries
+func (d resourceData) LowerPlural() string {
+	plural := d.Plural()
+	return strings.ToLower(plural[:1]) + plural[1:]
+}
+
+// ProtoPackage returns the versioned protobuf package for the resource
+func (d resourceData) ProtoPackage() string {
+	return d.Base + ".v1"
+}
+
+// GoProtoPackage returns the Go package name of the generated protobuf code
+func (d resourceData) GoProtoPackage() string {
+	return strings.ReplaceAll(d.Base, "_", "") + "v1"
+}
+
+// ProtoField returns the Go name of the response field holding the resource
+func (d resourceData) ProtoField() string {
+	return protoGoName(d.Base)
+}
+
+// ProtoNumber returns the number of the field in the input message
+func (d resourceData) ProtoNumber(f Field) int {
+	return d.Proto.number(f.Column())
+}
+
+// ProtoMessageNumber returns the number of the field in the resource
+// message, which numbers it after its id and timestamps
+func (d resourceData) ProtoMessageNumber(f Field) int {
+	return d.ProtoNumber(f) + protoMessageOffset
+}
+
+// ProtoReserved returns the reserved statements of the input message, or of
+// the resource message when message is true
+func (d resourceData) ProtoReserved(message bool) []string {
+	if message {
+		return d.Proto.reserved(protoMessageOffset)
+	}
+	return d.Proto.reserved(0)
+}
+
+// HasType reports whether any field has the given type
+func (d resourceData) HasType(typ string) bool {
+	for _, f := range d.Fields {
+		if f.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Quote quotes an identifier for the project's database, so columns named
+// after reserved words such as order or user work. The result is escaped to
+// sit inside a Go string literal in the generated code.
+func (d resourceData) Quote(ident string) (string, error) {
+	dialect, err := DialectFor(d.Database)
+	if err != nil {
+		return "", err
+	}
+	quoted := strconv.Quote(dialect.Quote(ident))
+	return quoted[1 : len(quoted)-1], nil
+}
+
+// ColumnList returns the comma separated insertable columns
+func (d resourceData) ColumnList() (string, error) {
+	cols := make([]string, len(d.Fields))
+	for i, f := range d.Fields {
+		col, err := d.Quote(f.Column())
+		if err != nil {
+			return "", err
+		}
+		cols[i] = col
+	}
+	return strings.Join(cols, ", "), nil
+}
+
+// Placeholders returns one bind placeholder per insertable column
+func (d resourceData) Placeholders() string {
+	return strings.TrimSuffix(strings.Repeat("?, ", len(d.Fields)), ", ")
+}
+
+// Assignments returns the SET clause for updating every field
+func (d resourceData) Assignments() (string, error) {
+	sets := make([]string, 0, len(d.Fields)+1)
+	for _, f := range d.Fields {
+		col, err := d.Quote(f.Column())
+		if err != nil {
+			return "", err
+		}
+		sets = append(sets, col+" = ?")
+	}
+	sets = append(sets, "updated_at = CURRENT_TIMESTAMP")
+	return strings.Join(sets, ", "), nil
+}
+
+// GenerateResource writes the model, repository, service and handler for a
+// resource and returns the paths of the files written
+func (g *TemplateGenerator) GenerateResource(res Resource) ([]string, error) {
+	if len(res.Fields) == 0 {
+		return nil, fmt.Errorf("resource %s has no fields", res.Name)
+	}
+
+	data := g.resourceData(res)
+
+	var written []string
+	for file, tmpl := range sharedResourceTemplates {
+		if g.exists(file) {
+			g.Report.AddExisting(filepath.Join(g.ProjectName, file), "already exists")
+			continue
+		}
+		if err := g.renderFile(file, tmpl, data); err != nil {
+			return written, fmt.Errorf("failed to generate %s: %w", file, err)
+		}
+		written = append(written, filepath.Join(g.ProjectName, file))
+	}
+
+	for _, t := range resourceTemplates {
+		file := fmt.Sprintf(t.file, data.Base)
+		if t.transport != "" && !g.Transports[t.transport] {
+			continue
+		}
+		if !t.overwrite && g.exists(file) {
+			g.Report.AddExisting(filepath.Join(g.ProjectName, file), "holds hand-written code")
+			continue
+		}
+		if err := g.renderFile(file, t.template, data); err != nil {
+			return written, fmt.Errorf("failed to generate %s: %w", file, err)
+		}
+		written = append(written, filepath.Join(g.ProjectName, file))
+	}
+
+	sort.Strings(written)
+	return written, nil
+}
+
+// resourceData returns the template data for a resource
+func (g *TemplateGenerator) resourceData(res Resource) resourceData {
+	proto := res.Proto
+	if proto == nil {
+		proto = NumberProtoFields(res.Fields, nil)
+	}
+	return resourceData{
+		Module:   g.Module,
+		Database: g.Database,
+		Name:     res.Name,
+		Resource: res.Resource,
+		Table:    res.TableName(),
+		Route:    res.Route(),
+		Base:     toSnakeCase(res.Name),
+		Fields:   res.Fields,
+		Proto:    proto,
+	}
+}
+
+// exists reports whether a file is already present in the project
+func (g *TemplateGenerator) exists(file string) bool {
+	if g.Output != nil {
+		_, ok := g.Output[filepath.ToSlash(file)]
+		return ok
+	}
+	_, err := os.Stat(filepath.Join(g.ProjectName, file))
+	return err == nil
+}
+
+// readFile returns the content of a file in the project
+func (g *TemplateGenerator) readFile(file string) ([]byte, error) {
+	if g.Output != nil {
+		content, ok := g.Output[filepath.ToSlash(file)]
+		if !ok {
+			return nil, fmt.Errorf("failed to read %s: %w", file, os.ErrNotExist)
+		}
+		return content, nil
+	}
+	// #nosec G304 - file is a fixed name inside the project directory
+	return os.ReadFile(filepath.Join(g.ProjectName, file))
+}
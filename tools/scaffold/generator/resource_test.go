@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestRepositoryQuotesIdentifiers(t *testing.T) {
+	tests := []struct {
+		database string
+		want     []string
+	}{
+		{
+			database: "postgres",
+			want: []string{
+				`"INSERT INTO \"orders\" (\"user\", \"order\") "`,
+				`"UPDATE \"orders\" SET \"user\" = ?, \"order\" = ?, updated_at = CURRENT_TIMESTAMP "`,
+				`"order":      "\"order\"",`,
+			},
+		},
+		{
+			database: "mysql",
+			want: []string{
+				"\"INSERT INTO `orders` (`user`, `order`) \"",
+				"\"UPDATE `orders` SET `user` = ?, `order` = ?, updated_at = CURRENT_TIMESTAMP \"",
+				"\"order\":      \"`order`\",",
+			},
+		},
+		{
+			database: "sqlite",
+			want: []string{
+				`"INSERT INTO \"orders\" (\"user\", \"order\") "`,
+				`"SELECT * FROM \"orders\" WHERE id = ? AND deleted_at IS NULL"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.database, func(t *testing.T) {
+			g := NewTemplateGenerator("shop", "example.com/shop", nil, nil)
+			g.Output = make(map[string][]byte)
+			g.Database = tt.database
+
+			res := mustResource(t, "Order", "user:string:required order:int")
+			if _, err := g.GenerateResource(res); err != nil {
+				t.Fatalf("GenerateResource() error = %v", err)
+			}
+
+			const file = "internal/repository/order_repository.go"
+			repo := g.Output[file]
+			if _, err := parser.ParseFile(token.NewFileSet(), file, repo, 0); err != nil {
+				t.Fatalf("Generated repository is not valid Go: %v\n%s", err, repo)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(repo), want) {
+					t.Errorf("Expected repository to contain %s, got:\n%s", want, repo)
+				}
+			}
+		})
+	}
+}
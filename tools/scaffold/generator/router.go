@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported HTTP routers
+const (
+	RouterGin    = "gin"
+	RouterStdlib = "stdlib"
+	RouterChi    = "chi"
+	RouterEcho   = "echo"
+)
+
+// routerTemplates maps each router to the template mounting the shared
+// net/http handlers on it
+var routerTemplates = map[string]string{
+	RouterGin:    "tools/scaffold/templates/router/gin.go.tmpl",
+	RouterStdlib: "tools/scaffold/templates/router/stdlib.go.tmpl",
+	RouterChi:    "tools/scaffold/templates/router/chi.go.tmpl",
+	RouterEcho:   "tools/scaffold/templates/router/echo.go.tmpl",
+}
+
+// routerFile is the project file holding the router adapter
+const routerFile = "internal/handlers/router.go"
+
+// ParseRouter validates a router name. An empty name selects gin.
+func ParseRouter(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return RouterGin, nil
+	}
+	if _, ok := routerTemplates[name]; !ok {
+		return "", fmt.Errorf("unsupported router %q (supported: gin, stdlib, chi, echo)", name)
+	}
+	return name, nil
+}
+
+// SetRouter selects the router the HTTP handlers are mounted on. An empty
+// name selects gin, which projects created before the option used.
+func (g *TemplateGenerator) SetRouter(name string) error {
+	router, err := ParseRouter(name)
+	if err != nil {
+		return err
+	}
+
+	g.Router = router
+	g.Templates[routerFile] = routerTemplates[router]
+	return nil
+}
@@ -0,0 +1,51 @@
+package generator
+
+import "testing"
+
+func TestParseRouter(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", RouterGin, false},
+		{"gin", RouterGin, false},
+		{"Chi", RouterChi, false},
+		{" stdlib ", RouterStdlib, false},
+		{"echo", RouterEcho, false},
+		{"fiber", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRouter(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetRouter(t *testing.T) {
+	g := NewTemplateGenerator("app", "example.com/app", nil, nil)
+	if got := g.Templates[routerFile]; got != routerTemplates[RouterGin] {
+		t.Errorf("Expected gin by default, got %s", got)
+	}
+
+	if err := g.SetRouter("chi"); err != nil {
+		t.Fatalf("SetRouter failed: %v", err)
+	}
+	if got := g.Templates[routerFile]; got != routerTemplates[RouterChi] {
+		t.Errorf("Expected the chi template, got %s", got)
+	}
+	if m := g.NewManifest(); m.Router != RouterChi {
+		t.Errorf("Expected the manifest to record chi, got %q", m.Router)
+	}
+
+	if err := g.SetRouter("fiber"); err == nil {
+		t.Error("Expected an error for an unsupported router")
+	}
+}
@@ -0,0 +1,484 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ChangeKind identifies the type of a column change
+type ChangeKind string
+
+// Column change kinds
+const (
+	ChangeAdd    ChangeKind = "add"
+	ChangeDrop   ChangeKind = "drop"
+	ChangeRename ChangeKind = "rename"
+	ChangeAlter  ChangeKind = "alter"
+)
+
+// ColumnChange describes a single change between two versions of a resource
+type ColumnChange struct {
+	Kind ChangeKind
+	From Field
+	To   Field
+	// Destructive is set when the change can lose data or fail on existing rows
+	Destructive bool
+	// Reasons explains why a change is destructive or otherwise risky
+	Reasons []string
+}
+
+// String returns a short human readable summary of the change
+func (c ColumnChange) String() string {
+	switch c.Kind {
+	case ChangeAdd:
+		return fmt.Sprintf("add column %s (%s)", c.To.Column(), c.To.Type)
+	case ChangeDrop:
+		return fmt.Sprintf("drop column %s", c.From.Column())
+	case ChangeRename:
+		return fmt.Sprintf("rename column %s to %s", c.From.Column(), c.To.Column())
+	default:
+		return fmt.Sprintf("alter column %s", c.To.Column())
+	}
+}
+
+// SchemaDiff is the ordered list of column changes for a resource
+type SchemaDiff struct {
+	Resource Resource
+	Changes  []ColumnChange
+}
+
+// Empty reports whether the diff has no changes
+func (d SchemaDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Destructive returns the changes that can lose data or fail on existing rows
+func (d SchemaDiff) Destructive() []ColumnChange {
+	var changes []ColumnChange
+	for _, c := range d.Changes {
+		if c.Destructive {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// RenameCandidates returns dropped/added column pairs with the same type
+// that may be a rename rather than a drop and add. Callers should confirm
+// each candidate before passing it to DiffResources as a rename.
+func (d SchemaDiff) RenameCandidates() [][2]Field {
+	var dropped, added []Field
+	for _, c := range d.Changes {
+		switch c.Kind {
+		case ChangeDrop:
+			dropped = append(dropped, c.From)
+		case ChangeAdd:
+			added = append(added, c.To)
+		}
+	}
+
+	var candidates [][2]Field
+	used := make(map[string]bool)
+	for _, from := range dropped {
+		for _, to := range added {
+			if from.Type == to.Type && !used[to.Column()] {
+				candidates = append(candidates, [2]Field{from, to})
+				used[to.Column()] = true
+				break
+			}
+		}
+	}
+	return candidates
+}
+
+// DiffResources compares two versions of a resource. renames maps old column
+// names to new column names for changes confirmed as renames.
+func DiffResources(from, to Resource, renames map[string]string) (SchemaDiff, error) {
+	diff := SchemaDiff{Resource: to}
+
+	oldFields := make(map[string]Field)
+	for _, f := range from.Fields {
+		oldFields[f.Column()] = f
+	}
+	newFields := make(map[string]Field)
+	for _, f := range to.Fields {
+		newFields[f.Column()] = f
+	}
+
+	renamedTo := make(map[string]string)
+	for oldName, newName := range renames {
+		if _, ok := oldFields[oldName]; !ok {
+			return diff, fmt.Errorf("cannot rename %s: no such column in %s", oldName, from.TableName())
+		}
+		if _, ok := newFields[newName]; !ok {
+			return diff, fmt.Errorf("cannot rename %s to %s: %s is not in the new definition", oldName, newName, newName)
+		}
+		if _, ok := newFields[oldName]; ok {
+			return diff, fmt.Errorf("cannot rename %s: the column still exists in the new definition", oldName)
+		}
+		if _, ok := oldFields[newName]; ok {
+			return diff, fmt.Errorf("cannot rename to %s: the column already exists", newName)
+		}
+		renamedTo[newName] = oldName
+	}
+
+	// Drops come first so renamed or re-added columns do not collide
+	for _, f := range from.Fields {
+		if _, ok := newFields[f.Column()]; ok {
+			continue
+		}
+		if _, ok := renames[f.Column()]; ok {
+			continue
+		}
+		diff.Changes = append(diff.Changes, ColumnChange{
+			Kind:        ChangeDrop,
+			From:        f,
+			Destructive: true,
+			Reasons:     []string{"all data in the column is lost"},
+		})
+	}
+
+	for _, f := range to.Fields {
+		if oldName, ok := renamedTo[f.Column()]; ok {
+			old := oldFields[oldName]
+			diff.Changes = append(diff.Changes, ColumnChange{
+				Kind:    ChangeRename,
+				From:    old,
+				To:      f,
+				Reasons: []string{"clients using the old column name will break"},
+			})
+
+			// Alter the renamed column if anything besides the name changed
+			old.Name = f.Name
+			if change, ok := alterChange(old, f); ok {
+				diff.Changes = append(diff.Changes, change)
+			}
+			continue
+		}
+
+		old, ok := oldFields[f.Column()]
+		if !ok {
+			change := ColumnChange{Kind: ChangeAdd, To: f}
+			if f.Required && !f.HasDefault {
+				change.Destructive = true
+				change.Reasons = append(change.Reasons, "NOT NULL column without a default fails on tables with existing rows")
+			}
+			diff.Changes = append(diff.Changes, change)
+			continue
+		}
+
+		if change, ok := alterChange(old, f); ok {
+			diff.Changes = append(diff.Changes, change)
+		}
+	}
+
+	return diff, nil
+}
+
+// alterChange compares the schema attributes of two versions of a column
+func alterChange(from, to Field) (ColumnChange, bool) {
+	change := ColumnChange{Kind: ChangeAlter, From: from, To: to}
+
+	if !schemaEqual(from, to) {
+		if from.Type != to.Type {
+			change.Destructive = !widens(from, to)
+			change.Reasons = append(change.Reasons, fmt.Sprintf("type changes from %s to %s", from.Type, to.Type))
+		} else if size(from) > size(to) {
+			change.Destructive = true
+			change.Reasons = append(change.Reasons, fmt.Sprintf("size shrinks from %d to %d", size(from), size(to)))
+		}
+
+		if from.Type == "enum" && to.Type == "enum" && !subset(from.Enum, to.Enum) {
+			change.Destructive = true
+			change.Reasons = append(change.Reasons, "enum values are removed")
+		}
+
+		if !from.Required && to.Required {
+			change.Destructive = true
+			change.Reasons = append(change.Reasons, "NOT NULL fails if existing rows contain NULL")
+		}
+
+		if !from.Unique && to.Unique {
+			change.Reasons = append(change.Reasons, "unique constraint fails if existing rows contain duplicates")
+		}
+
+		if to.References != "" && from.References != to.References {
+			change.Reasons = append(change.Reasons, "foreign key fails if existing rows reference missing records")
+		}
+
+		return change, true
+	}
+
+	return change, false
+}
+
+// schemaEqual compares the attributes of a field that affect the schema
+func schemaEqual(a, b Field) bool {
+	return a.Type == b.Type &&
+		size(a) == size(b) &&
+		a.Required == b.Required &&
+		a.HasDefault == b.HasDefault &&
+		a.Default == b.Default &&
+		a.Unique == b.Unique &&
+		a.Index == b.Index &&
+		a.References == b.References &&
+		a.OnDelete == b.OnDelete &&
+		reflect.DeepEqual(a.Enum, b.Enum)
+}
+
+// size returns the effective column size for string fields
+func size(f Field) int {
+	if f.Type == "string" && f.Size == 0 {
+		return 255
+	}
+	return f.Size
+}
+
+// widens reports whether converting from one type to another keeps every value
+func widens(from, to Field) bool {
+	switch {
+	case from.Type == "string" && to.Type == "text":
+		return true
+	case from.Type == "enum" && (to.Type == "string" || to.Type == "text"):
+		return true
+	case from.Type == "uuid" && (to.Type == "string" || to.Type == "text"):
+		return true
+	case from.Type == "ref" && to.Type == "int", from.Type == "int" && to.Type == "ref":
+		return true
+	default:
+		return false
+	}
+}
+
+func subset(values, of []string) bool {
+	set := make(map[string]bool, len(of))
+	for _, v := range of {
+		set[v] = true
+	}
+	for _, v := range values {
+		if !set[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// AlterTableMigration builds the migration applying a schema diff. The down
+// migration reverses each change in the opposite order; dropped columns are
+// re-created empty because their data cannot be restored.
+func AlterTableMigration(diff SchemaDiff, d Dialect) (Migration, error) {
+	table := diff.Resource.TableName()
+	m := Migration{
+		Name:    "update_" + table + "_table",
+		Dialect: d.Name(),
+	}
+
+	var downs [][]Statement
+	for _, c := range diff.Changes {
+		up, down, err := changeStatements(d, table, c)
+		if err != nil {
+			return Migration{}, err
+		}
+		m.Up = append(m.Up, up...)
+		downs = append(downs, down)
+
+		if c.Destructive {
+			for _, reason := range c.Reasons {
+				m.Warnings = append(m.Warnings, fmt.Sprintf("%s: %s", c, reason))
+			}
+		}
+	}
+
+	for i := len(downs) - 1; i >= 0; i-- {
+		m.Down = append(m.Down, downs[i]...)
+	}
+
+	return m, m.Validate()
+}
+
+// changeStatements returns the up and down statements for a single change
+func changeStatements(d Dialect, table string, c ColumnChange) (up, down []Statement, err error) {
+	switch c.Kind {
+	case ChangeAdd:
+		addUp, addDown, err := addColumnStatements(d, table, c.To)
+		if err != nil {
+			return nil, nil, err
+		}
+		return addUp, addDown, nil
+
+	case ChangeDrop:
+		addUp, addDown, err := addColumnStatements(d, table, c.From)
+		if err != nil {
+			return nil, nil, err
+		}
+		return addDown, addUp, nil
+
+	case ChangeRename:
+		// The constraints still match the old definition; any change to
+		// them follows as an alter of the renamed column
+		renamed := c.From
+		renamed.Name = c.To.Name
+		up = append([]Statement{{SQL: d.RenameColumn(table, c.From.Column(), c.To.Column())}}, d.RenameConstraints(table, c.From, renamed)...)
+		down = append([]Statement{{SQL: d.RenameColumn(table, c.To.Column(), c.From.Column())}}, d.RenameConstraints(table, renamed, c.From)...)
+		return up, down, nil
+
+	default:
+		return alterStatements(d, table, c.From, c.To)
+	}
+}
+
+// addColumnStatements returns the statements adding a column with its
+// constraints and indexes, and the statements removing them again
+func addColumnStatements(d Dialect, table string, f Field) (up, down []Statement, err error) {
+	def, err := columnDefinition(d, f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	up = append(up, Statement{SQL: d.AddColumn(table, def)})
+	cUp, cDown, err := constraintStatements(d, table, Field{}, f)
+	if err != nil {
+		return nil, nil, err
+	}
+	up = append(up, statements(cUp)...)
+
+	down = append(down, statements(cDown)...)
+	down = append(down, Statement{SQL: d.DropColumn(table, f.Column())})
+	return up, down, nil
+}
+
+// alterStatements returns the statements changing a column in place
+func alterStatements(d Dialect, table string, from, to Field) (up, down []Statement, err error) {
+	if err := d.ValidateField(to); err != nil {
+		return nil, nil, err
+	}
+
+	// Constraints are removed before the column changes and re-added after
+	cUp, cDown, err := constraintStatements(d, table, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	dropUp, addUp := splitDrops(cUp)
+	dropDown, addDown := splitDrops(cDown)
+
+	colUp, colDown, err := columnAlterStatements(d, table, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	up = append(append(append(up, dropUp...), colUp...), addUp...)
+	down = append(append(append(down, dropDown...), colDown...), addDown...)
+	return up, down, nil
+}
+
+func columnAlterStatements(d Dialect, table string, from, to Field) (up, down []Statement, err error) {
+	if columnEqual(d, from, to) {
+		return nil, nil, nil
+	}
+
+	up, err = d.AlterColumn(table, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	down, err = d.AlterColumn(table, to, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	return up, down, nil
+}
+
+// columnEqual reports whether the column definition itself is unchanged
+func columnEqual(d Dialect, a, b Field) bool {
+	return d.ColumnType(a) == d.ColumnType(b) &&
+		d.ColumnCheck(a) == d.ColumnCheck(b) &&
+		a.Required == b.Required &&
+		a.HasDefault == b.HasDefault &&
+		a.Default == b.Default
+}
+
+// dropStatement marks constraint statements that must run before a column
+// is altered
+type dropStatement struct {
+	Statement
+	drop bool
+}
+
+func statements(stmts []dropStatement) []Statement {
+	out := make([]Statement, len(stmts))
+	for i, s := range stmts {
+		out[i] = s.Statement
+	}
+	return out
+}
+
+func splitDrops(stmts []dropStatement) (drops, adds []Statement) {
+	for _, s := range stmts {
+		if s.drop {
+			drops = append(drops, s.Statement)
+		} else {
+			adds = append(adds, s.Statement)
+		}
+	}
+	return drops, adds
+}
+
+// constraintStatements returns the statements moving a column's unique,
+// index and foreign key constraints from one definition to another. A zero
+// from field means the column is new.
+func constraintStatements(d Dialect, table string, from, to Field) (up, down []dropStatement, err error) {
+	column := to.Column()
+
+	if from.Unique != to.Unique {
+		// Both statements name the constraint after the definition that has it
+		unique := from
+		if to.Unique {
+			unique = to
+		}
+		add := dropStatement{Statement: Statement{SQL: d.AddUnique(uniqueName(table, unique), table, column)}}
+		drop := dropStatement{Statement: Statement{SQL: d.DropUnique(uniqueName(table, unique), table)}, drop: true}
+		if to.Unique {
+			up, down = append(up, add), append(down, drop)
+		} else {
+			up, down = append(up, drop), append(down, add)
+		}
+	}
+
+	if from.References != to.References || from.OnDelete != to.OnDelete {
+		if from.References != "" {
+			stmt, err := d.DropForeignKey(foreignKeyName(table, from), table)
+			if err != nil {
+				return nil, nil, err
+			}
+			add, err := d.AddForeignKey(table, foreignKeyConstraint(d, table, from))
+			if err != nil {
+				return nil, nil, err
+			}
+			up = append(up, dropStatement{Statement: Statement{SQL: stmt}, drop: true})
+			down = append(down, dropStatement{Statement: Statement{SQL: add}})
+		}
+		if to.References != "" {
+			stmt, err := d.AddForeignKey(table, foreignKeyConstraint(d, table, to))
+			if err != nil {
+				return nil, nil, err
+			}
+			drop, err := d.DropForeignKey(foreignKeyName(table, to), table)
+			if err != nil {
+				return nil, nil, err
+			}
+			up = append(up, dropStatement{Statement: Statement{SQL: stmt}})
+			down = append(down, dropStatement{Statement: Statement{SQL: drop}, drop: true})
+		}
+	}
+
+	if indexed(from) != indexed(to) {
+		add := dropStatement{Statement: Statement{SQL: d.CreateIndex(indexName(table, column), table, column)}}
+		drop := dropStatement{Statement: Statement{SQL: d.DropIndex(indexName(table, column), table)}, drop: true}
+		if indexed(to) {
+			up, down = append(up, add), append(down, drop)
+		} else {
+			up, down = append(up, drop), append(down, add)
+		}
+	}
+
+	return up, down, nil
+}
@@ -0,0 +1,336 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustResource(t *testing.T, name, spec string) Resource {
+	t.Helper()
+
+	fields, err := ParseFields(spec)
+	if err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+	res, err := NewResource(name, fields)
+	if err != nil {
+		t.Fatalf("Failed to create resource: %v", err)
+	}
+	return res
+}
+
+func TestDiffResources(t *testing.T) {
+	old := mustResource(t, "User", "name:string:required email:string:required,unique age:int role:enum:oneof=admin|user")
+
+	tests := []struct {
+		name        string
+		spec        string
+		renames     map[string]string
+		wantChanges []string
+		destructive []string
+	}{
+		{
+			name:        "No changes",
+			spec:        "name:string:required email:string:required,unique age:int role:enum:oneof=admin|user",
+			wantChanges: nil,
+		},
+		{
+			name:        "Binding rules only",
+			spec:        "name:string:required,min=2 email:string:required,email,unique age:int:min=0 role:enum:oneof=admin|user",
+			wantChanges: nil,
+		},
+		{
+			name:        "Add nullable column",
+			spec:        "name:string:required email:string:required,unique age:int role:enum:oneof=admin|user bio:text",
+			wantChanges: []string{"add column bio (text)"},
+		},
+		{
+			name:        "Add required column without default",
+			spec:        "name:string:required email:string:required,unique age:int role:enum:oneof=admin|user bio:text:required",
+			wantChanges: []string{"add column bio (text)"},
+			destructive: []string{"add column bio (text)"},
+		},
+		{
+			name:        "Drop column",
+			spec:        "name:string:required email:string:required,unique role:enum:oneof=admin|user",
+			wantChanges: []string{"drop column age"},
+			destructive: []string{"drop column age"},
+		},
+		{
+			name:        "Confirmed rename",
+			spec:        "full_name:string:required email:string:required,unique age:int role:enum:oneof=admin|user",
+			renames:     map[string]string{"name": "full_name"},
+			wantChanges: []string{"rename column name to full_name"},
+		},
+		{
+			name:        "Widen and extend enum",
+			spec:        "name:text:required email:string:required,unique age:int role:enum:oneof=admin|user|guest",
+			wantChanges: []string{"alter column name", "alter column role"},
+		},
+		{
+			name:        "Narrowing changes",
+			spec:        "name:string:required,max=50 email:string:required,unique age:string:required role:enum:oneof=admin",
+			wantChanges: []string{"alter column name", "alter column age", "alter column role"},
+			destructive: []string{"alter column name", "alter column age", "alter column role"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := DiffResources(old, mustResource(t, "User", tt.spec), tt.renames)
+			if err != nil {
+				t.Fatalf("DiffResources() error = %v", err)
+			}
+
+			var got []string
+			for _, c := range diff.Changes {
+				got = append(got, c.String())
+			}
+			if strings.Join(got, "|") != strings.Join(tt.wantChanges, "|") {
+				t.Errorf("Expected changes %v, got %v", tt.wantChanges, got)
+			}
+
+			var destructive []string
+			for _, c := range diff.Destructive() {
+				destructive = append(destructive, c.String())
+			}
+			if strings.Join(destructive, "|") != strings.Join(tt.destructive, "|") {
+				t.Errorf("Expected destructive changes %v, got %v", tt.destructive, destructive)
+			}
+		})
+	}
+}
+
+func TestDiffResourcesInvalidRename(t *testing.T) {
+	old := mustResource(t, "User", "name:string")
+	updated := mustResource(t, "User", "full_name:string")
+
+	if _, err := DiffResources(old, updated, map[string]string{"missing": "full_name"}); err == nil {
+		t.Error("Expected error for unknown rename source, got nil")
+	}
+}
+
+func TestRenameCandidates(t *testing.T) {
+	old := mustResource(t, "User", "name:string age:int")
+	updated := mustResource(t, "User", "full_name:string years:int")
+
+	diff, err := DiffResources(old, updated, nil)
+	if err != nil {
+		t.Fatalf("DiffResources() error = %v", err)
+	}
+
+	candidates := diff.RenameCandidates()
+	if len(candidates) != 2 {
+		t.Fatalf("Expected 2 rename candidates, got %d", len(candidates))
+	}
+	if candidates[0][0].Name != "name" || candidates[0][1].Name != "full_name" {
+		t.Errorf("Unexpected first candidate: %v -> %v", candidates[0][0].Name, candidates[0][1].Name)
+	}
+}
+
+func TestAlterTableMigration(t *testing.T) {
+	old := mustResource(t, "Post", "title:string:required views:int author_id:ref:ref=users")
+	updated := mustResource(t, "Post", "headline:string:required,max=100,unique author_id:ref:ref=users,ondelete=cascade")
+
+	diff, err := DiffResources(old, updated, map[string]string{"title": "headline"})
+	if err != nil {
+		t.Fatalf("DiffResources() error = %v", err)
+	}
+
+	tests := []struct {
+		dialect string
+		up      []string
+		down    []string
+		wantErr bool
+	}{
+		{
+			dialect: "postgres",
+			up: []string{
+				`ALTER TABLE "posts" DROP COLUMN "views";`,
+				`ALTER TABLE "posts" RENAME COLUMN "title" TO "headline";`,
+				`ALTER TABLE "posts" ALTER COLUMN "headline" TYPE VARCHAR(100) USING "headline"::VARCHAR(100);`,
+				`ALTER TABLE "posts" ADD CONSTRAINT "uq_posts_headline" UNIQUE ("headline");`,
+				`ALTER TABLE "posts" DROP CONSTRAINT IF EXISTS "fk_posts_author_id";`,
+				`ALTER TABLE "posts" ADD CONSTRAINT "fk_posts_author_id" FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE CASCADE;`,
+			},
+			down: []string{
+				`ALTER TABLE "posts" DROP CONSTRAINT IF EXISTS "fk_posts_author_id";`,
+				`ALTER TABLE "posts" ADD CONSTRAINT "fk_posts_author_id" FOREIGN KEY ("author_id") REFERENCES "users" ("id");`,
+				`ALTER TABLE "posts" DROP CONSTRAINT IF EXISTS "uq_posts_headline";`,
+				`ALTER TABLE "posts" ALTER COLUMN "headline" TYPE VARCHAR(255) USING "headline"::VARCHAR(255);`,
+				`ALTER TABLE "posts" RENAME COLUMN "headline" TO "title";`,
+				`ALTER TABLE "posts" ADD COLUMN "views" BIGINT;`,
+			},
+		},
+		{
+			dialect: "mysql",
+			up: []string{
+				"ALTER TABLE `posts` DROP COLUMN `views`;",
+				"ALTER TABLE `posts` RENAME COLUMN `title` TO `headline`;",
+				"ALTER TABLE `posts` MODIFY COLUMN `headline` VARCHAR(100) NOT NULL;",
+				"ALTER TABLE `posts` ADD CONSTRAINT `uq_posts_headline` UNIQUE (`headline`);",
+				"ALTER TABLE `posts` DROP FOREIGN KEY `fk_posts_author_id`;",
+				"ALTER TABLE `posts` ADD CONSTRAINT `fk_posts_author_id` FOREIGN KEY (`author_id`) REFERENCES `users` (`id`) ON DELETE CASCADE;",
+			},
+		},
+		{
+			dialect: "sqlite",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			d, _ := DialectFor(tt.dialect)
+			m, err := AlterTableMigration(diff, d)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AlterTableMigration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := joinStatements(m.Up); got != strings.Join(tt.up, "\n") {
+				t.Errorf("Unexpected up migration.\nGot:\n%s\nWant:\n%s", got, strings.Join(tt.up, "\n"))
+			}
+			if tt.down != nil {
+				if got := joinStatements(m.Down); got != strings.Join(tt.down, "\n") {
+					t.Errorf("Unexpected down migration.\nGot:\n%s\nWant:\n%s", got, strings.Join(tt.down, "\n"))
+				}
+			}
+			if len(m.Warnings) == 0 {
+				t.Error("Expected destructive warnings for dropped column")
+			}
+		})
+	}
+}
+
+// TestRenameThenDropConstraints checks that constraints follow a renamed
+// column, so a later migration dropping them by the new name finds them
+func TestRenameThenDropConstraints(t *testing.T) {
+	v1 := mustResource(t, "User", "email:string:required,unique owner_id:ref:ref=users tag:string:index")
+	v2 := mustResource(t, "User", "contact:string:required,unique account_id:ref:ref=users label:string:index")
+	renames := map[string]string{"email": "contact", "owner_id": "account_id", "tag": "label"}
+
+	tests := []struct {
+		dialect string
+		v3      string
+		rename  []string
+		drop    []string
+	}{
+		{
+			dialect: "postgres",
+			v3:      "contact:string:required account_id:int",
+			rename: []string{
+				`ALTER TABLE "users" RENAME COLUMN "email" TO "contact";`,
+				`ALTER TABLE "users" RENAME CONSTRAINT "uq_users_email" TO "uq_users_contact";`,
+				`ALTER TABLE "users" RENAME COLUMN "owner_id" TO "account_id";`,
+				`ALTER TABLE "users" RENAME CONSTRAINT "fk_users_owner_id" TO "fk_users_account_id";`,
+				`ALTER INDEX IF EXISTS "idx_users_owner_id" RENAME TO "idx_users_account_id";`,
+				`ALTER TABLE "users" RENAME COLUMN "tag" TO "label";`,
+				`ALTER INDEX IF EXISTS "idx_users_tag" RENAME TO "idx_users_label";`,
+			},
+			drop: []string{
+				`DROP INDEX IF EXISTS "idx_users_label";`,
+				`ALTER TABLE "users" DROP COLUMN "label";`,
+				`ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "uq_users_contact";`,
+				`ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "fk_users_account_id";`,
+				`DROP INDEX IF EXISTS "idx_users_account_id";`,
+			},
+		},
+		{
+			dialect: "mysql",
+			v3:      "contact:string:required account_id:int",
+			rename: []string{
+				"ALTER TABLE `users` RENAME COLUMN `email` TO `contact`;",
+				"ALTER TABLE `users` RENAME INDEX `uq_users_email` TO `uq_users_contact`;",
+				"ALTER TABLE `users` RENAME COLUMN `owner_id` TO `account_id`;",
+				"ALTER TABLE `users` DROP FOREIGN KEY `fk_users_owner_id`;",
+				"ALTER TABLE `users` RENAME INDEX `idx_users_owner_id` TO `idx_users_account_id`;",
+				"ALTER TABLE `users` ADD CONSTRAINT `fk_users_account_id` FOREIGN KEY (`account_id`) REFERENCES `users` (`id`);",
+				"ALTER TABLE `users` RENAME COLUMN `tag` TO `label`;",
+				"ALTER TABLE `users` RENAME INDEX `idx_users_tag` TO `idx_users_label`;",
+			},
+			drop: []string{
+				"DROP INDEX `idx_users_label` ON `users`;",
+				"ALTER TABLE `users` DROP COLUMN `label`;",
+				"ALTER TABLE `users` DROP INDEX `uq_users_contact`;",
+				"ALTER TABLE `users` DROP FOREIGN KEY `fk_users_account_id`;",
+				"DROP INDEX `idx_users_account_id` ON `users`;",
+				"ALTER TABLE `users` MODIFY COLUMN `account_id` BIGINT;",
+			},
+		},
+		{
+			// SQLite cannot drop a foreign key, so only the unique and
+			// indexed columns are dropped; both need their index gone first
+			dialect: "sqlite",
+			v3:      "account_id:ref:ref=users",
+			rename: []string{
+				`ALTER TABLE "users" RENAME COLUMN "email" TO "contact";`,
+				`DROP INDEX IF EXISTS "uq_users_email";`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "uq_users_contact" ON "users" ("contact");`,
+				`ALTER TABLE "users" RENAME COLUMN "owner_id" TO "account_id";`,
+				`DROP INDEX IF EXISTS "idx_users_owner_id";`,
+				`CREATE INDEX IF NOT EXISTS "idx_users_account_id" ON "users" ("account_id");`,
+				`ALTER TABLE "users" RENAME COLUMN "tag" TO "label";`,
+				`DROP INDEX IF EXISTS "idx_users_tag";`,
+				`CREATE INDEX IF NOT EXISTS "idx_users_label" ON "users" ("label");`,
+			},
+			drop: []string{
+				`DROP INDEX IF EXISTS "uq_users_contact";`,
+				`ALTER TABLE "users" DROP COLUMN "contact";`,
+				`DROP INDEX IF EXISTS "idx_users_label";`,
+				`ALTER TABLE "users" DROP COLUMN "label";`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			d, _ := DialectFor(tt.dialect)
+
+			diff, err := DiffResources(v1, v2, renames)
+			if err != nil {
+				t.Fatalf("DiffResources() error = %v", err)
+			}
+			m, err := AlterTableMigration(diff, d)
+			if err != nil {
+				t.Fatalf("AlterTableMigration() error = %v", err)
+			}
+			if got := joinStatements(m.Up); got != strings.Join(tt.rename, "\n") {
+				t.Errorf("Unexpected rename migration.\nGot:\n%s\nWant:\n%s", got, strings.Join(tt.rename, "\n"))
+			}
+
+			diff, err = DiffResources(v2, mustResource(t, "User", tt.v3), nil)
+			if err != nil {
+				t.Fatalf("DiffResources() error = %v", err)
+			}
+			m, err = AlterTableMigration(diff, d)
+			if err != nil {
+				t.Fatalf("AlterTableMigration() error = %v", err)
+			}
+			if got := joinStatements(m.Up); got != strings.Join(tt.drop, "\n") {
+				t.Errorf("Unexpected drop migration.\nGot:\n%s\nWant:\n%s", got, strings.Join(tt.drop, "\n"))
+			}
+		})
+	}
+}
+
+func TestFieldDefinitionRoundTrip(t *testing.T) {
+	defs := []string{
+		"name:string",
+		"email:string:required,email,max=100,unique",
+		"role:enum:required,oneof=admin|user,default=user",
+		"owner_id:ref:index,ref=users,ondelete=setnull",
+	}
+
+	for _, def := range defs {
+		f, err := ParseField(def)
+		if err != nil {
+			t.Fatalf("ParseField(%q) error = %v", def, err)
+		}
+		if got := f.Definition(); got != def {
+			t.Errorf("Definition() = %q, want %q", got, def)
+		}
+	}
+}
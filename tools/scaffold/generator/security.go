@@ -0,0 +1,15 @@
+package generator
+
+// securityTemplateDir holds the security middleware templates
+const securityTemplateDir = "tools/scaffold/templates/security/"
+
+// securityFiles returns the security middleware of the enabled features.
+// CORS, headers and the in-memory rate limiter are base templates; the
+// Redis rate limiter is only generated with the cache feature.
+func (g *TemplateGenerator) securityFiles() map[string]string {
+	files := make(map[string]string)
+	if g.Features["cache"] {
+		files["pkg/security/ratelimit_redis.go"] = securityTemplateDir + "ratelimit_redis.go.tmpl"
+	}
+	return files
+}
@@ -1,12 +1,22 @@
 package generator
 
 import (
+	"bytes"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"go/format"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
+
+	"github.com/jwill9999/scaffold-go/pkg/deps"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/templates"
 )
 
 type TemplateGenerator struct {
@@ -16,13 +26,75 @@ type TemplateGenerator struct {
 	Config      interface{}
 	Resources   []Resource
 	Templates   map[string]string
+	// Database selects the SQL dialect used for generated migrations
+	Database string
+	// Migrations controls migration file format and naming
+	Migrations MigrationSettings
+	// Transports selects how resources are served (http, grpc, graphql)
+	Transports map[string]bool
+	// Router is the HTTP router the handlers are mounted on
+	Router string
+	// Logger is the handler backend of the generated slog logger
+	Logger string
+	// Toolchain, when set, adds a toolchain line such as go1.22.5 to go.mod
+	Toolchain string
+	// Deployment is the target the project is deployed to
+	Deployment string
+	// CI is the provider the project's pipeline is generated for
+	CI string
+	// DockerProfile selects the default image the Dockerfile builds
+	DockerProfile string
+	// Report, when set, records every file written or skipped
+	Report *Report
+	// Output, when set, receives the generated files keyed by their slash
+	// separated path in the project instead of them being written to disk
+	Output map[string][]byte
 	// Base directory to prevent path traversal
 	BaseDir string
+
+	// now is the clock used to timestamp migrations
+	now func() time.Time
 }
 
 type Resource struct {
 	Name     string
 	Resource string
+	Fields   []Field
+	// Table overrides the table name derived from Name, used when the
+	// resource wraps an existing table
+	Table string
+	// Proto numbers the fields in the resource's protobuf messages. When
+	// nil, fields are numbered in definition order.
+	Proto *ProtoNumbers
+}
+
+// NewResource returns a resource with a PascalCase name and its lower
+// camelCase identifier
+func NewResource(name string, fields []Field) (Resource, error) {
+	if !fieldNamePattern.MatchString(name) {
+		return Resource{}, fmt.Errorf("invalid resource name %q", name)
+	}
+
+	pascal := toPascalCase(name)
+	return Resource{
+		Name:     pascal,
+		Resource: strings.ToLower(pascal[:1]) + pascal[1:],
+		Fields:   fields,
+	}, nil
+}
+
+// TableName returns the plural snake_case table name for the resource
+func (r Resource) TableName() string {
+	if r.Table != "" {
+		return r.Table
+	}
+	return pluralize(toSnakeCase(r.Name))
+}
+
+// Route returns the plural kebab-case path segment the resource is served
+// under
+func (r Resource) Route() string {
+	return strings.ReplaceAll(pluralize(toSnakeCase(r.Name)), "_", "-")
 }
 
 func NewTemplateGenerator(name, module string, features []string, config interface{}) *TemplateGenerator {
@@ -38,23 +110,41 @@ func NewTemplateGenerator(name, module string, features []string, config interfa
 	}
 
 	return &TemplateGenerator{
-		ProjectName: name,
-		Module:      module,
-		Features:    featureMap,
-		Config:      config,
-		Resources:   []Resource{},
-		BaseDir:     baseDir,
+		ProjectName:   name,
+		Module:        module,
+		Features:      featureMap,
+		Config:        config,
+		Resources:     []Resource{},
+		Database:      "postgres",
+		Migrations:    DefaultMigrationSettings(),
+		Transports:    map[string]bool{TransportHTTP: true},
+		Router:        RouterGin,
+		Logger:        LoggerSlog,
+		Deployment:    DeploymentDocker,
+		CI:            CIGitHub,
+		DockerProfile: DockerProfileProd,
+		BaseDir:       baseDir,
+		now:           time.Now,
 		Templates: map[string]string{
 			// Core application files
 			"cmd/api/main.go":           "tools/scaffold/templates/main.go.tmpl",
 			"internal/config/config.go": "tools/scaffold/templates/config.go.tmpl",
 			"Dockerfile":                "tools/scaffold/templates/Dockerfile.tmpl",
-			"docker-compose.yml":        "tools/scaffold/templates/docker-compose.yml.tmpl",
 
 			// Essential packages for a basic application
-			"pkg/logger/logger.go":              "tools/scaffold/templates/logger.go.tmpl",
+			"pkg/logger/logger.go":              "tools/scaffold/templates/logger/logger.go.tmpl",
+			"pkg/logger/handler.go":             "tools/scaffold/templates/logger/slog.go.tmpl",
 			"pkg/database/database.go":          "tools/scaffold/templates/database.go.tmpl",
+			"pkg/errors/errors.go":              "tools/scaffold/templates/errors.go.tmpl",
+			"pkg/health/health.go":              "tools/scaffold/templates/health/health.go.tmpl",
+			"pkg/health/checks.go":              "tools/scaffold/templates/health/checks.go.tmpl",
+			"pkg/lifecycle/lifecycle.go":        "tools/scaffold/templates/lifecycle/lifecycle.go.tmpl",
+			"pkg/security/cors.go":              "tools/scaffold/templates/security/cors.go.tmpl",
+			"pkg/security/headers.go":           "tools/scaffold/templates/security/headers.go.tmpl",
+			"pkg/security/ratelimit.go":         "tools/scaffold/templates/security/ratelimit.go.tmpl",
 			"internal/handlers/handlers.go":     "tools/scaffold/templates/handlers.go.tmpl",
+			"internal/handlers/middleware.go":   "tools/scaffold/templates/middleware.go.tmpl",
+			"internal/handlers/router.go":       "tools/scaffold/templates/router/gin.go.tmpl",
 			"internal/repository/repository.go": "tools/scaffold/templates/repository.go.tmpl",
 			"internal/services/services.go":     "tools/scaffold/templates/service.go.tmpl",
 			"internal/models/models.go":         "tools/scaffold/templates/model.go.tmpl",
@@ -63,14 +153,79 @@ func NewTemplateGenerator(name, module string, features []string, config interfa
 }
 
 func (g *TemplateGenerator) Generate() error {
-	for filename, templatePath := range g.Templates {
-		if err := g.generateFile(filename, templatePath); err != nil {
-			return fmt.Errorf("failed to generate %s: %w", filename, err)
+	if err := g.GenerateModFile(); err != nil {
+		return err
+	}
+
+	for _, files := range []map[string]string{g.Templates, g.healthFiles(), g.securityFiles(), g.deploymentFiles(), g.ciFiles()} {
+		for filename, templatePath := range files {
+			if err := g.generateFile(filename, templatePath); err != nil {
+				return fmt.Errorf("failed to generate %s: %w", filename, err)
+			}
+		}
+	}
+	if err := g.GenerateCompose(); err != nil {
+		return err
+	}
+
+	createdAt := g.now().UTC()
+	for _, res := range g.Resources {
+		if _, err := g.GenerateMigration(res, createdAt); err != nil {
+			return fmt.Errorf("failed to generate migration for %s: %w", res.Name, err)
 		}
 	}
+
+	if g.Transports[TransportGraphQL] {
+		if _, err := g.GenerateGraphQL(g.Resources); err != nil {
+			return err
+		}
+	}
+
+	if !g.Transports[TransportHTTP] {
+		return nil
+	}
+
+	title := filepath.Base(g.ProjectName)
+	if _, err := g.GenerateOpenAPISpec(title, g.Resources); err != nil {
+		return err
+	}
+	if _, err := g.GeneratePostman(title, g.Resources); err != nil {
+		return err
+	}
 	return nil
 }
 
+// GenerateMigration writes the create-table migration for a resource using
+// the dialect selected by g.Database and the format in g.Migrations
+func (g *TemplateGenerator) GenerateMigration(res Resource, createdAt time.Time) ([]string, error) {
+	dialect, err := DialectFor(g.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	migration, err := CreateTableMigration(res, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.WriteMigration(migration, createdAt)
+}
+
+// GenerateAlterMigration writes the migration applying a schema diff
+func (g *TemplateGenerator) GenerateAlterMigration(diff SchemaDiff, createdAt time.Time) ([]string, error) {
+	dialect, err := DialectFor(g.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	migration, err := AlterTableMigration(diff, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.WriteMigration(migration, createdAt)
+}
+
 // sanitizePath prevents path traversal attacks by checking for ../ patterns and absolute paths
 func (g *TemplateGenerator) sanitizePath(path string) (string, error) {
 	// Check for .. patterns that might indicate path traversal
@@ -99,23 +254,160 @@ func (g *TemplateGenerator) sanitizePath(path string) (string, error) {
 }
 
 func (g *TemplateGenerator) generateFile(filename, templatePath string) error {
+	data := struct {
+		ProjectName string
+		Name        string
+		// AppName is the project name as a DNS label
+		AppName    string
+		Module     string
+		Features   map[string]bool
+		Transports map[string]bool
+		Router     string
+		Logger     string
+		Database   string
+		Deployment string
+		// DockerProfile is the default image of the Dockerfile
+		DockerProfile string
+		// CGO reports whether the database driver needs cgo
+		CGO       bool
+		GoVersion string
+		Config    interface{}
+		Resources []Resource
+	}{
+		ProjectName:   g.ProjectName,
+		Name:          g.ProjectName,
+		AppName:       appName(g.ProjectName),
+		Module:        g.Module,
+		Features:      g.Features,
+		Transports:    g.Transports,
+		Router:        g.Router,
+		Logger:        g.Logger,
+		Database:      g.Database,
+		Deployment:    g.Deployment,
+		DockerProfile: g.DockerProfile,
+		CGO:           g.cgoEnabled(),
+		GoVersion:     deps.GoVersion,
+		Config:        g.Config,
+		Resources:     g.Resources,
+	}
+
+	return g.renderFile(filename, templatePath, data)
+}
+
+// bracketTemplateDirs hold templates of files that use {{ }} themselves,
+// which are rendered with [[ ]] delimiters instead
+var bracketTemplateDirs = []string{helmTemplateDir, ciTemplateDir}
+
+// templateFuncs are available to every template
+var templateFuncs = template.FuncMap{
+	// title converts GET to Get
+	"title": func(s string) string {
+		if s == "" {
+			return s
+		}
+		return strings.ToUpper(s[:1]) + strings.ToLower(s[1:])
+	},
+	// stringSlice renders a Go []string literal
+	"stringSlice": func(values []string) string {
+		if len(values) == 0 {
+			return "nil"
+		}
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = strconv.Quote(v)
+		}
+		return "[]string{" + strings.Join(quoted, ", ") + "}"
+	},
+	"stub": newAPIStub,
+	// add is used to number protobuf fields
+	"add": func(a, b int) int { return a + b },
+}
+
+// renderFile executes the template at templatePath with data and atomically
+// writes the result to filename inside the project directory
+func (g *TemplateGenerator) renderFile(filename, templatePath string, data interface{}) error {
+	content, err := g.executeTemplate(templatePath, "", data)
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(filename) == ".go" {
+		// Keep the unformatted output if it does not parse so the
+		// problem is visible in the generated file
+		if formatted, fmtErr := format.Source(content); fmtErr == nil {
+			content = formatted
+		}
+	}
+
+	return g.writeOutput(filename, content)
+}
+
+// executeTemplate executes the template at templatePath, or the named
+// template defined in it, with data
+func (g *TemplateGenerator) executeTemplate(templatePath, name string, data interface{}) ([]byte, error) {
+	content, err := g.readTemplate(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := template.New(path.Base(filepath.ToSlash(templatePath))).Funcs(templateFuncs)
+	for _, dir := range bracketTemplateDirs {
+		if strings.HasPrefix(filepath.ToSlash(templatePath), dir) {
+			tmpl = tmpl.Delims("[[", "]]")
+		}
+	}
+	tmpl, err = tmpl.Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", templatePath, err)
+	}
+
+	if name == "" {
+		name = tmpl.Name()
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", templatePath, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// readTemplate returns the content of the template at templatePath.
+// Templates below templateRoot are read from the copy embedded in the
+// binary, so generation does not depend on the working directory; other
+// templates, added with AddTemplate, are read from disk.
+func (g *TemplateGenerator) readTemplate(templatePath string) ([]byte, error) {
+	if name, ok := strings.CutPrefix(filepath.ToSlash(templatePath), templateRoot); ok {
+		content, err := fs.ReadFile(templates.FS, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("template file does not exist: %s", templatePath)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", templatePath, err)
+		}
+		return content, nil
+	}
+
 	// Sanitize paths
 	sanitizedTemplatePath, err := g.sanitizePath(templatePath)
 	if err != nil {
-		return fmt.Errorf("invalid template path: %w", err)
+		return nil, fmt.Errorf("invalid template path: %w", err)
 	}
 
-	// Validate that template exists
-	if _, err := os.Stat(sanitizedTemplatePath); os.IsNotExist(err) {
-		return fmt.Errorf("template file does not exist: %s", sanitizedTemplatePath)
+	// #nosec G304 - the path is sanitized above
+	content, err := os.ReadFile(sanitizedTemplatePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("template file does not exist: %s", sanitizedTemplatePath)
 	}
-
-	// Read template
-	tmpl, err := template.ParseFiles(sanitizedTemplatePath)
 	if err != nil {
-		return fmt.Errorf("failed to parse template %s: %w", sanitizedTemplatePath, err)
+		return nil, fmt.Errorf("failed to read template %s: %w", sanitizedTemplatePath, err)
 	}
+	return content, nil
+}
 
+// writeOutput atomically writes content to filename inside the project
+// directory
+func (g *TemplateGenerator) writeOutput(filename string, content []byte) (err error) {
 	// Create output file
 	// Ensure we create paths relative to project name to avoid path traversal
 	if strings.Contains(filename, "..") || filepath.IsAbs(filename) {
@@ -123,6 +415,18 @@ func (g *TemplateGenerator) generateFile(filename, templatePath string) error {
 	}
 
 	outputPath := filepath.Join(g.ProjectName, filename)
+	if g.Output != nil {
+		if len(content) == 0 {
+			return fmt.Errorf("template generated empty file, generation failed")
+		}
+		action := FileCreated
+		if g.exists(filename) {
+			action = FileOverwritten
+		}
+		g.Output[filepath.ToSlash(filename)] = content
+		g.Report.AddFile(outputPath, action, content, "")
+		return nil
+	}
 	outputDir := filepath.Dir(outputPath)
 
 	// Create directories with secure permissions
@@ -161,25 +465,8 @@ func (g *TemplateGenerator) generateFile(filename, templatePath string) error {
 		}
 	}()
 
-	// Execute template
-	data := struct {
-		ProjectName string
-		Name        string
-		Module      string
-		Features    map[string]bool
-		Config      interface{}
-		Resources   []Resource
-	}{
-		ProjectName: g.ProjectName,
-		Name:        g.ProjectName,
-		Module:      g.Module,
-		Features:    g.Features,
-		Config:      g.Config,
-		Resources:   g.Resources,
-	}
-
-	if err := tmpl.Execute(tempOut, data); err != nil {
-		return fmt.Errorf("failed to execute template %s: %w", sanitizedTemplatePath, err)
+	if _, err := tempOut.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
 	}
 
 	// Close the temp file before renaming
@@ -200,6 +487,11 @@ func (g *TemplateGenerator) generateFile(filename, templatePath string) error {
 		return fmt.Errorf("template generated empty file, generation failed")
 	}
 
+	action := FileCreated
+	if _, err := os.Stat(outputPath); err == nil {
+		action = FileOverwritten
+	}
+
 	// Rename the temp file to the final output file (atomic operation)
 	if err := os.Rename(tempFile, outputPath); err != nil {
 		// Try to remove the temp file if rename fails
@@ -209,6 +501,7 @@ func (g *TemplateGenerator) generateFile(filename, templatePath string) error {
 		return fmt.Errorf("failed to finalize file %s: %w", outputPath, err)
 	}
 
+	g.Report.AddFile(outputPath, action, content, "")
 	return nil
 }
 
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Supported transports for generated resources
+const (
+	TransportHTTP    = "http"
+	TransportGRPC    = "grpc"
+	TransportGraphQL = "graphql"
+)
+
+// supportedTransports lists the transports a project can serve resources over
+var supportedTransports = map[string]bool{
+	TransportHTTP:    true,
+	TransportGRPC:    true,
+	TransportGraphQL: true,
+}
+
+// grpcTemplates are the project files shared by every gRPC resource server
+var grpcTemplates = map[string]string{
+	"api/proto/buf.yaml":             "tools/scaffold/templates/grpc/buf.yaml.tmpl",
+	"api/proto/buf.gen.yaml":         "tools/scaffold/templates/grpc/buf.gen.yaml.tmpl",
+	"api/proto/generate.go":          "tools/scaffold/templates/grpc/generate.go.tmpl",
+	"internal/grpcserver/server.go":  "tools/scaffold/templates/grpc/server.go.tmpl",
+	"internal/grpcserver/convert.go": "tools/scaffold/templates/grpc/convert.go.tmpl",
+}
+
+// ParseTransports parses a comma separated transport list. "both" selects
+// HTTP and gRPC, and an empty list selects HTTP. GraphQL is served beside
+// the HTTP API, so selecting it also selects HTTP.
+func ParseTransports(spec string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(spec, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch {
+		case t == "":
+			continue
+		case t == "both":
+			seen[TransportHTTP] = true
+			seen[TransportGRPC] = true
+		case supportedTransports[t]:
+			seen[t] = true
+		default:
+			return nil, fmt.Errorf("unsupported transport %q (supported: http, grpc, graphql, both)", t)
+		}
+	}
+
+	if len(seen) == 0 || seen[TransportGraphQL] {
+		seen[TransportHTTP] = true
+	}
+
+	transports := make([]string, 0, len(seen))
+	for t := range seen {
+		transports = append(transports, t)
+	}
+	sort.Strings(transports)
+	return transports, nil
+}
+
+// SetTransports selects the transports resources are generated for and
+// adds the project files each transport needs
+func (g *TemplateGenerator) SetTransports(transports []string) {
+	if len(transports) == 0 {
+		transports = []string{TransportHTTP}
+	}
+
+	g.Transports = make(map[string]bool, len(transports))
+	for _, t := range transports {
+		g.Transports[t] = true
+	}
+
+	if g.Transports[TransportGRPC] {
+		for file, tmpl := range grpcTemplates {
+			g.Templates[file] = tmpl
+		}
+	}
+	if g.Transports[TransportGraphQL] {
+		for file, tmpl := range graphqlProjectTemplates {
+			g.Templates[file] = tmpl
+		}
+	}
+}
+
+// transportList returns the enabled transports in a stable order
+func (g *TemplateGenerator) transportList() []string {
+	var transports []string
+	for t, enabled := range g.Transports {
+		if enabled {
+			transports = append(transports, t)
+		}
+	}
+	sort.Strings(transports)
+	return transports
+}
+
+// ProtoType returns the protobuf type of the field, marked optional when
+// the column is nullable so that unset can be told apart from zero
+func (f Field) ProtoType() string {
+	var typ string
+	switch f.Type {
+	case "int":
+		typ = "int64"
+	case "float":
+		typ = "double"
+	case "bool":
+		typ = "bool"
+	case "ref":
+		typ = "uint64"
+	case "time":
+		// Messages already have presence
+		return "google.protobuf.Timestamp"
+	case "json":
+		// Bytes have presence through nil
+		return "bytes"
+	default:
+		typ = "string"
+	}
+
+	if f.Nullable() {
+		return "optional " + typ
+	}
+	return typ
+}
+
+// ProtoGoName returns the Go field name protoc-gen-go generates for the
+// field
+func (f Field) ProtoGoName() string {
+	return protoGoName(f.Column())
+}
+
+// ToProto returns the expression converting the model value expr to its
+// protobuf field value
+func (f Field) ToProto(expr string) string {
+	switch {
+	case f.Type == "ref" && f.Nullable():
+		return "toUint64Ptr(" + expr + ")"
+	case f.Type == "ref":
+		return "uint64(" + expr + ")"
+	case f.Type == "time" && f.Nullable():
+		return "toTimestamp(" + expr + ")"
+	case f.Type == "time":
+		return "timestamppb.New(" + expr + ")"
+	case f.Type == "json" && f.Nullable():
+		return "toJSONBytes(" + expr + ")"
+	default:
+		// json.RawMessage and []byte are assignable to each other
+		return expr
+	}
+}
+
+// FromProto returns the expression converting the protobuf value expr to
+// the model field value
+func (f Field) FromProto(expr string) string {
+	switch {
+	case f.Type == "ref" && f.Nullable():
+		return "toUintPtr(" + expr + ")"
+	case f.Type == "ref":
+		return "uint(" + expr + ")"
+	case f.Type == "time" && f.Nullable():
+		return "toTimePtr(" + expr + ")"
+	case f.Type == "time":
+		return "toTime(" + expr + ")"
+	case f.Type == "json" && f.Nullable():
+		return "toRawJSON(" + expr + ")"
+	default:
+		return expr
+	}
+}
+
+// protoGoName mirrors protoc-gen-go's conversion of snake_case names to Go
+// identifiers: owner_id becomes OwnerId and field_2 becomes Field_2
+func protoGoName(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Dropped; the next letter is upper cased
+		case c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
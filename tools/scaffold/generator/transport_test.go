@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTransports(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []string
+		wantErr bool
+	}{
+		{"", []string{"http"}, false},
+		{"grpc", []string{"grpc"}, false},
+		{"both", []string{"grpc", "http"}, false},
+		{"http, GRPC", []string{"grpc", "http"}, false},
+		// GraphQL is served by the HTTP server
+		{"graphql", []string{"graphql", "http"}, false},
+		{"soap", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseTransports(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProtoGoName(t *testing.T) {
+	tests := map[string]string{
+		"name":        "Name",
+		"owner_id":    "OwnerId",
+		"api_key":     "ApiKey",
+		"field_2":     "Field_2",
+		"line2_total": "Line2Total",
+	}
+
+	for in, want := range tests {
+		if got := protoGoName(in); got != want {
+			t.Errorf("protoGoName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFieldProtoConversions(t *testing.T) {
+	tests := []struct {
+		def       string
+		protoType string
+		toProto   string
+		fromProto string
+	}{
+		{"name:string:required", "string", "m.Name", "in.Name"},
+		{"bio:text", "optional string", "m.Bio", "in.Bio"},
+		{"price:float:required", "double", "m.Price", "in.Price"},
+		{"owner_id:ref:required,ref=users", "uint64", "uint64(m.OwnerID)", "uint(in.OwnerId)"},
+		{"owner_id:ref:ref=users", "optional uint64", "toUint64Ptr(m.OwnerID)", "toUintPtr(in.OwnerId)"},
+		{"due:time:required", "google.protobuf.Timestamp", "timestamppb.New(m.Due)", "toTime(in.Due)"},
+		{"due:time", "google.protobuf.Timestamp", "toTimestamp(m.Due)", "toTimePtr(in.Due)"},
+		{"data:json", "bytes", "toJSONBytes(m.Data)", "toRawJSON(in.Data)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.def, func(t *testing.T) {
+			f, err := ParseField(tt.def)
+			if err != nil {
+				t.Fatalf("ParseField failed: %v", err)
+			}
+			if got := f.ProtoType(); got != tt.protoType {
+				t.Errorf("ProtoType() = %q, want %q", got, tt.protoType)
+			}
+			if got := f.ToProto("m." + f.GoName()); got != tt.toProto {
+				t.Errorf("ToProto() = %q, want %q", got, tt.toProto)
+			}
+			if got := f.FromProto("in." + f.ProtoGoName()); got != tt.fromProto {
+				t.Errorf("FromProto() = %q, want %q", got, tt.fromProto)
+			}
+		})
+	}
+}
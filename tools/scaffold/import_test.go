@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+)
+
+const petstoreSpec = `
+openapi: 3.0.3
+info: {title: Petstore, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - {name: limit, in: query, schema: {type: integer, minimum: 1}}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      operationId: createPet
+      tags: [pets]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Pet'}
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        born_at: {type: string, format: date-time}
+`
+
+func TestImportOpenAPI(t *testing.T) {
+	chdirRepoRoot(t)
+	project := newProject(t)
+	spec := filepath.Join(t.TempDir(), "openapi.yaml")
+
+	run := func(doc string) string {
+		t.Helper()
+		if err := os.WriteFile(spec, []byte(doc), 0600); err != nil {
+			t.Fatalf("Failed to write spec: %v", err)
+		}
+		var out bytes.Buffer
+		if err := runImportCommand([]string{"openapi", "-project", project, "-module", "example.com/petstore", spec}, logger.New(&out), newReporter("test")); err != nil {
+			t.Fatalf("Import failed: %v\n%s", err, out.String())
+		}
+		return out.String()
+	}
+
+	run(petstoreSpec)
+
+	files := []string{
+		"internal/dto/openapi.go",
+		"internal/handlers/openapi_routes.go",
+		"internal/handlers/pets_api.go",
+		"internal/services/pets_api.go",
+		"internal/services/pets_api_impl.go",
+	}
+	for _, file := range files {
+		if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(project, file), nil, 0); err != nil {
+			t.Errorf("Generated %s is not valid Go: %v", file, err)
+		}
+	}
+
+	// Implement an operation by hand, then add an operation to the spec
+	impl := filepath.Join(project, "internal/services/pets_api_impl.go")
+	content, err := os.ReadFile(impl)
+	if err != nil {
+		t.Fatalf("Failed to read implementation: %v", err)
+	}
+	handWritten := strings.Replace(string(content),
+		"func (s *petsAPI) ListPets(ctx context.Context, params dto.ListPetsParams) ([]dto.Pet, error) {\n\treturn nil, errors.ErrNotImplemented",
+		"func (s *petsAPI) ListPets(ctx context.Context, params dto.ListPetsParams) ([]dto.Pet, error) {\n\treturn []dto.Pet{{Name: \"rex\"}}, nil", 1)
+	if handWritten == string(content) {
+		t.Fatalf("ListPets stub not found in:\n%s", content)
+	}
+	if err := os.WriteFile(impl, []byte(handWritten), 0600); err != nil {
+		t.Fatalf("Failed to write implementation: %v", err)
+	}
+
+	updated := strings.Replace(petstoreSpec, "components:", `  /pets/{petId}:
+    delete:
+      operationId: deletePet
+      tags: [pets]
+      parameters:
+        - {name: petId, in: path, required: true, schema: {type: integer}}
+      responses:
+        '204': {description: deleted}
+components:`, 1)
+	out := run(updated)
+	if !strings.Contains(out, "pets_api_impl.go") {
+		t.Errorf("Expected implementation to be updated, got:\n%s", out)
+	}
+
+	content, err = os.ReadFile(impl)
+	if err != nil {
+		t.Fatalf("Failed to read implementation: %v", err)
+	}
+	if !strings.Contains(string(content), `return []dto.Pet{{Name: "rex"}}, nil`) {
+		t.Errorf("Hand-written implementation was overwritten:\n%s", content)
+	}
+	if !strings.Contains(string(content), "func (s *petsAPI) DeletePet(ctx context.Context, params dto.DeletePetParams) error {") {
+		t.Errorf("Expected a stub for the new operation:\n%s", content)
+	}
+
+	// Unchanged operations leave the implementation alone
+	if out := run(updated); strings.Contains(out, "pets_api_impl.go") {
+		t.Errorf("Expected implementation to be left unchanged, got:\n%s", out)
+	}
+
+	// Files without the generated header are never overwritten
+	handler := filepath.Join(project, "internal/handlers/pets_api.go")
+	if err := os.WriteFile(handler, []byte("package handlers\n"), 0600); err != nil {
+		t.Fatalf("Failed to write handler: %v", err)
+	}
+	var buf bytes.Buffer
+	err = runImportCommand([]string{"openapi", "-project", project, "-module", "example.com/petstore", spec}, logger.New(&buf), newReporter("test"))
+	if err == nil || !strings.Contains(err.Error(), "refusing to overwrite") {
+		t.Errorf("Expected refusal to overwrite a hand-written file, got %v", err)
+	}
+}
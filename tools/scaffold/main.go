@@ -3,22 +3,28 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/jwill9999/scaffold-go/pkg/logger"
 	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
 )
 
 type ProjectScaffold struct {
-	Name      string
-	Module    string
-	Features  []string
-	Structure ProjectStructure
-	Config    ProjectConfig
+	Name       string
+	Module     string
+	Features   []string
+	Transports []string
+	Router     string
+	Logger     string
+	Toolchain  string
+	Report     *generator.Report
+	Structure  ProjectStructure
+	Config     ProjectConfig
 }
 
 type ProjectStructure struct {
@@ -31,6 +37,7 @@ type ProjectConfig struct {
 	Environment string
 	Database    DatabaseConfig
 	Deployment  DeploymentConfig
+	Migrations  generator.MigrationSettings
 }
 
 type DatabaseConfig struct {
@@ -44,9 +51,11 @@ type DatabaseConfig struct {
 }
 
 type DeploymentConfig struct {
-	Docker     bool
-	Kubernetes bool
-	CI         string // github, gitlab
+	Type          string // docker, kubernetes, helm
+	Docker        bool
+	Kubernetes    bool
+	CI            string // github, gitlab, none
+	DockerProfile string // dev, prod
 }
 
 // Base project directories based on core.mdc
@@ -75,25 +84,135 @@ var baseDirectories = []string{
 	"tools",
 }
 
+// subcommands maps command names to their handlers. Without a subcommand
+// the flags describe a new project to create.
+var subcommands = map[string]func(args []string, log *logger.Logger, rep *reporter) error{
+	"import":    runImportCommand,
+	"migration": runMigrationCommand,
+	"resource": func(args []string, log *logger.Logger, rep *reporter) error {
+		return runResourceCommand(args, os.Stdin, log, rep, time.Now())
+	},
+}
+
 func main() {
+	log := logger.New(os.Stderr)
+
+	// Dispatch subcommands before parsing project flags
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			rep := newReporter(os.Args[1])
+			err := run(os.Args[2:], log, rep)
+			if reportErr := rep.finish(err, os.Stdout); reportErr != nil {
+				log.Error("%v", reportErr)
+			}
+			if err != nil {
+				log.Fatal("%v", err)
+			}
+			return
+		}
+	}
+
+	rep := newReporter("create")
+	fail := func(err error) {
+		if reportErr := rep.finish(err, os.Stdout); reportErr != nil {
+			log.Error("%v", reportErr)
+		}
+		log.Fatal("%v", err)
+	}
+
 	// Parse command line flags
-	name := flag.String("name", "", "Project name")
+	name := flag.String("name", "", "Project name and output directory (default: last element of the module path)")
 	module := flag.String("module", "", "Go module path")
-	features := flag.String("features", "", "Comma-separated list of features (auth,metrics,tracing)")
-	dbType := flag.String("db", "postgres", "Database type (postgres, mysql)")
-	deployment := flag.String("deployment", "docker", "Deployment type (docker, kubernetes)")
+	features := flag.String("features", "", "Comma-separated list of features (auth, cache, metrics, tracing)")
+	dbType := flag.String("db", "postgres", "Database type (postgres, mysql, sqlite)")
+	deployment := flag.String("deployment", generator.DeploymentDocker, "Deployment type (docker, kubernetes, helm)")
+	migrations := flag.String("migrations", generator.MigrationFormatGoose, "Migration format (goose, golang-migrate)")
+	migrationSeq := flag.Bool("migrations-seq", false, "Prefix migrations with a sequence number instead of a timestamp")
+	transport := flag.String("transport", generator.TransportHTTP, "Resource transports (http, grpc, graphql, both)")
+	router := flag.String("router", generator.RouterGin, "HTTP router (gin, stdlib, chi, echo)")
+	loggerBackend := flag.String("logger", generator.LoggerSlog, "Logging backend behind log/slog (slog, zap, zerolog)")
+	ciProvider := flag.String("ci", generator.CIGitHub, "CI pipeline to generate (github, gitlab, none)")
+	dockerProfile := flag.String("docker-profile", generator.DockerProfileProd, "Default Docker image (dev, prod)")
+	toolchain := flag.String("toolchain", "", "Optional toolchain line for go.mod, e.g. go1.22.5")
+
+	log.RegisterFlags(flag.CommandLine)
+	rep.registerFlag(flag.CommandLine)
 
 	flag.Parse()
 
-	if *name == "" || *module == "" {
-		log.Fatal("Project name and module path are required")
+	if *module == "" {
+		fail(generator.WithCode(generator.CodeInvalidArguments, fmt.Errorf("module path is required")))
+	}
+	if err := validateModuleName(*module); err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+	// Without -name the project is named, and written to a directory, after
+	// the module path
+	if *name == "" {
+		*name = projectNameFromModule(*module)
+	}
+
+	featureList, err := generator.ParseFeatures(*features)
+	if err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+
+	// Aliases such as postgresql are stored by the dialect's name, which the
+	// templates and the version catalog are keyed by
+	dialect, err := generator.DialectFor(*dbType)
+	if err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+
+	transports, err := generator.ParseTransports(*transport)
+	if err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+
+	routerName, err := generator.ParseRouter(*router)
+	if err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+
+	loggerName, err := generator.ParseLogger(*loggerBackend)
+	if err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+
+	deploymentType, err := generator.ParseDeployment(*deployment)
+	if err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+
+	ciName, err := generator.ParseCI(*ciProvider)
+	if err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+
+	dockerProfileName, err := generator.ParseDockerProfile(*dockerProfile)
+	if err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
+	}
+
+	migrationSettings := generator.DefaultMigrationSettings()
+	migrationSettings.Format = *migrations
+	if *migrationSeq {
+		migrationSettings.Prefix = generator.MigrationPrefixSequence
+	}
+	if err := migrationSettings.Validate(); err != nil {
+		fail(generator.WithCode(generator.CodeInvalidArguments, err))
 	}
 
 	// Create project scaffold
 	scaffold := &ProjectScaffold{
-		Name:     *name,
-		Module:   *module,
-		Features: strings.Split(*features, ","),
+		Name:       *name,
+		Module:     *module,
+		Features:   featureList,
+		Transports: transports,
+		Router:     routerName,
+		Logger:     loggerName,
+		Toolchain:  *toolchain,
+		Report:     rep.Report,
 		Structure: ProjectStructure{
 			Directories: baseDirectories,
 			BaseFiles:   make(map[string]string),
@@ -102,7 +221,7 @@ func main() {
 		Config: ProjectConfig{
 			Environment: "development",
 			Database: DatabaseConfig{
-				Type:      *dbType,
+				Type:      dialect.Name(),
 				Username:  "postgres",
 				Password:  "postgres",
 				Host:      "localhost",
@@ -111,39 +230,56 @@ func main() {
 				EnableORM: true,
 			},
 			Deployment: DeploymentConfig{
-				Docker:     *deployment == "docker",
-				Kubernetes: *deployment == "kubernetes",
-				CI:         "github",
+				Type:          deploymentType,
+				Docker:        deploymentType == generator.DeploymentDocker,
+				Kubernetes:    deploymentType == generator.DeploymentKubernetes,
+				CI:            ciName,
+				DockerProfile: dockerProfileName,
 			},
+			Migrations: migrationSettings,
 		},
 	}
 
+	log.Debug("Creating %s (%s) with the %s router, %s logger and %s transports",
+		scaffold.Name, scaffold.Module, scaffold.Router, scaffold.Logger, strings.Join(scaffold.Transports, ","))
+
 	// Create project
 	if err := scaffold.Create(); err != nil {
-		log.Fatal(err)
+		fail(err)
 	}
 
-	fmt.Printf("Successfully created project %s\n", *name)
+	log.Info("Successfully created project %s", *name)
+	if err := rep.finish(nil, os.Stdout); err != nil {
+		log.Fatal("%v", err)
+	}
 }
 
 func (p *ProjectScaffold) Create() error {
+	if err := validateModuleName(p.Module); err != nil {
+		return generator.WithCode(generator.CodeInvalidArguments, err)
+	}
+	if err := checkCaseCollision(p.Name); err != nil {
+		return generator.WithCode(generator.CodeInvalidArguments, err)
+	}
+
 	// Create base directory
 	if err := os.MkdirAll(p.Name, 0750); err != nil {
 		return fmt.Errorf("failed to create project directory: %w", err)
 	}
 
 	// Create project structure
-	if err := p.createDirectories(); err != nil {
+	done := p.Report.Time("directories")
+	err := p.createDirectories()
+	done()
+	if err != nil {
 		return fmt.Errorf("failed to create directories: %w", err)
 	}
 
-	// Initialize go module
-	if err := p.initGoModule(); err != nil {
-		return fmt.Errorf("failed to initialize go module: %w", err)
-	}
-
-	// Generate base files using template generator
-	if err := p.generateBaseFiles(); err != nil {
+	// Generate base files, including go.mod, using template generator
+	done = p.Report.Time("generate")
+	err = p.generateBaseFiles()
+	done()
+	if err != nil {
 		return fmt.Errorf("failed to generate base files: %w", err)
 	}
 
@@ -160,52 +296,6 @@ func (p *ProjectScaffold) createDirectories() error {
 	return nil
 }
 
-func (p *ProjectScaffold) initGoModule() error {
-	// Use filepath.Join instead of string concatenation for path safety
-	projectPath := filepath.Join(".", p.Name)
-
-	// Validate module name to prevent command injection
-	if err := validateModuleName(p.Module); err != nil {
-		return err
-	}
-
-	// Execute command with explicit arguments instead of through shell
-	// The module name has been validated above to prevent command injection
-	// #nosec G204 - p.Module is validated by validateModuleName
-	cmd := exec.Command("go", "mod", "init", p.Module)
-	cmd.Dir = projectPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to initialize Go module: %w", err)
-	}
-
-	return nil
-}
-
-// validateModuleName checks if the module name is valid
-// Allows typical Go module path characters but prevents any special shell chars
-func validateModuleName(name string) error {
-	// Disallow empty name
-	if name == "" {
-		return fmt.Errorf("module name cannot be empty")
-	}
-
-	// Create a whitelist of allowed characters
-	validModulePattern := `^[a-zA-Z0-9][a-zA-Z0-9\.\-_/]+$`
-	matched, err := regexp.MatchString(validModulePattern, name)
-	if err != nil {
-		return fmt.Errorf("error validating module name: %w", err)
-	}
-
-	if !matched {
-		return fmt.Errorf("invalid module name. Module name must start with a letter or number and contain only letters, numbers, periods, hyphens, underscores, and slashes")
-	}
-
-	return nil
-}
-
 func (p *ProjectScaffold) generateBaseFiles() error {
 	// Create template generator
 	tmplGen := generator.NewTemplateGenerator(
@@ -214,12 +304,38 @@ func (p *ProjectScaffold) generateBaseFiles() error {
 		p.Features,
 		p.Config,
 	)
+	tmplGen.Database = p.Config.Database.Type
+	tmplGen.Migrations = p.Config.Migrations
+	tmplGen.SetTransports(p.Transports)
+	if err := tmplGen.SetRouter(p.Router); err != nil {
+		return err
+	}
+	if err := tmplGen.SetLogger(p.Logger); err != nil {
+		return err
+	}
+	if err := tmplGen.SetDeployment(p.Config.Deployment.Type); err != nil {
+		return err
+	}
+	if err := tmplGen.SetCI(p.Config.Deployment.CI); err != nil {
+		return err
+	}
+	if err := tmplGen.SetDockerProfile(p.Config.Deployment.DockerProfile); err != nil {
+		return err
+	}
+	tmplGen.Toolchain = p.Toolchain
+	tmplGen.Report = p.Report
+	p.Report.SetProject(tmplGen)
 
 	// Generate files
 	if err := tmplGen.Generate(); err != nil {
 		return fmt.Errorf("failed to generate files: %w", err)
 	}
 
+	// Record the generation choices for later commands
+	if err := saveManifest(tmplGen.NewManifest(), p.Name, p.Report); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+)
+
+// runMigrationCommand handles `scaffold migration <subcommand>`
+func runMigrationCommand(args []string, log *logger.Logger, rep *reporter) error {
+	if len(args) == 0 {
+		return generator.WithCode(generator.CodeInvalidArguments, fmt.Errorf("usage: scaffold migration create [flags] <name>"))
+	}
+
+	switch args[0] {
+	case "create":
+		return createMigration(args[1:], log, rep, time.Now())
+	default:
+		return generator.WithCode(generator.CodeInvalidArguments, fmt.Errorf("unknown migration command: %s", args[0]))
+	}
+}
+
+// createMigration writes an empty, correctly ordered migration into the
+// project's migrations directory using the format from the project manifest
+// unless overridden by flags
+func createMigration(args []string, log *logger.Logger, rep *reporter, now time.Time) error {
+	fs := flag.NewFlagSet("migration create", flag.ContinueOnError)
+	fs.SetOutput(log.Writer())
+	log.RegisterFlags(fs)
+	rep.registerFlag(fs)
+	rep.Command = fs.Name()
+	project := fs.String("project", ".", "Project directory containing "+generator.ManifestFile)
+	format := fs.String("format", "", "Migration format (goose, golang-migrate); defaults to the project manifest")
+	seq := fs.Bool("seq", false, "Use a sequence number prefix instead of a timestamp")
+	dir := fs.String("dir", "", "Migrations directory relative to the project; defaults to the project manifest")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return generator.WithCode(generator.CodeInvalidArguments, fmt.Errorf("usage: scaffold migration create [flags] <name>"))
+	}
+
+	manifest, err := loadManifest(*project)
+	if err != nil {
+		return err
+	}
+
+	settings := manifest.Migrations
+	if *format != "" {
+		settings.Format = *format
+	}
+	if *seq {
+		settings.Prefix = generator.MigrationPrefixSequence
+	}
+	if *dir != "" {
+		settings.Dir = *dir
+	}
+
+	tmplGen, err := newProjectGenerator(*project, manifest)
+	if err != nil {
+		return err
+	}
+	tmplGen.Migrations = settings
+	tmplGen.Report = rep.Report
+	rep.SetProject(tmplGen)
+
+	files, err := tmplGen.WriteMigration(generator.Migration{Name: fs.Arg(0), Dialect: manifest.Database}, now)
+	if err != nil {
+		return fmt.Errorf("failed to create migration: %w", err)
+	}
+
+	for _, f := range files {
+		log.Info("Created %s", f)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+)
+
+// chdirRepoRoot switches to the repository root so testdata paths resolve
+func chdirRepoRoot(t *testing.T) {
+	t.Helper()
+	chdir(t, filepath.Join("..", ".."))
+}
+
+// chdir switches to dir for the rest of the test, the way a user runs a
+// subcommand from inside their project
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change to %s: %v", dir, err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Errorf("Failed to restore working directory: %v", err)
+		}
+	})
+}
+
+// newProject returns a project directory holding a manifest with the
+// default settings, for subcommands that run on an existing project
+func newProject(t *testing.T) string {
+	t.Helper()
+
+	project := t.TempDir()
+	manifest := &generator.Manifest{Database: "postgres", Migrations: generator.DefaultMigrationSettings()}
+	if err := manifest.Save(project); err != nil {
+		t.Fatalf("Failed to save manifest: %v", err)
+	}
+	return project
+}
+
+// generateProject generates a complete project with the default choices,
+// for subcommands that must work on what a user actually has on disk
+func generateProject(t *testing.T) string {
+	t.Helper()
+
+	project := filepath.Join(t.TempDir(), "shop")
+	scaffold := &ProjectScaffold{
+		Name:      project,
+		Module:    "github.com/test/shop",
+		Structure: ProjectStructure{Directories: baseDirectories},
+		Config: ProjectConfig{
+			Database:   DatabaseConfig{Type: "postgres"},
+			Migrations: generator.DefaultMigrationSettings(),
+		},
+	}
+	if err := scaffold.Create(); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return project
+}
+
+func listMigrations(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read migrations: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestCreateMigration(t *testing.T) {
+	chdirRepoRoot(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		manifest *generator.Manifest
+		args     []string
+		want     []string
+	}{
+		{
+			name:     "Goose timestamp by default",
+			manifest: &generator.Manifest{Database: "postgres"},
+			args:     []string{"add_users_table"},
+			want: []string{
+				"20240501120000_add_users_table.sql",
+				"20240501120001_add_users_table.sql",
+			},
+		},
+		{
+			name: "Golang-migrate sequence from manifest",
+			manifest: &generator.Manifest{
+				Database: "postgres",
+				Migrations: generator.MigrationSettings{
+					Format: generator.MigrationFormatGolangMigrate,
+					Prefix: generator.MigrationPrefixSequence,
+					Dir:    "db/migrations",
+				},
+			},
+			args: []string{"AddUsersTable"},
+			want: []string{
+				"000001_add_users_table.down.sql",
+				"000001_add_users_table.up.sql",
+				"000002_add_users_table.down.sql",
+				"000002_add_users_table.up.sql",
+			},
+		},
+		{
+			name: "Flags override manifest",
+			manifest: &generator.Manifest{
+				Migrations: generator.MigrationSettings{Format: generator.MigrationFormatGolangMigrate},
+			},
+			args: []string{"-format", "goose", "-seq", "add_users_table"},
+			want: []string{
+				"00001_add_users_table.sql",
+				"00002_add_users_table.sql",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := t.TempDir()
+			if err := tt.manifest.Save(project); err != nil {
+				t.Fatalf("Failed to save manifest: %v", err)
+			}
+			dir := "migrations"
+			if tt.manifest.Migrations.Dir != "" {
+				dir = tt.manifest.Migrations.Dir
+			}
+
+			args := append([]string{"-project", project}, tt.args...)
+			var out bytes.Buffer
+
+			// Create twice at the same instant to check ordering is preserved
+			for i := 0; i < 2; i++ {
+				if err := createMigration(args, logger.New(&out), newReporter("test"), now); err != nil {
+					t.Fatalf("createMigration() error = %v", err)
+				}
+			}
+
+			got := listMigrations(t, filepath.Join(project, dir))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected migrations %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected migrations %v, got %v", tt.want, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestCreateMigrationInProject runs the command from inside a generated
+// project, where neither the repository's templates nor testdata are on disk
+func TestCreateMigrationInProject(t *testing.T) {
+	chdir(t, generateProject(t))
+
+	before := listMigrations(t, "migrations")
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := createMigration([]string{"add_orders"}, logger.New(&bytes.Buffer{}), newReporter("test"), now); err != nil {
+		t.Fatalf("createMigration() error = %v", err)
+	}
+
+	got := listMigrations(t, "migrations")
+	if len(got) != len(before)+1 || got[len(got)-1] != "20300101000000_add_orders.sql" {
+		t.Errorf("Expected 20300101000000_add_orders.sql to be added to %v, got %v", before, got)
+	}
+}
+
+func TestCreateMigrationErrors(t *testing.T) {
+	chdirRepoRoot(t)
+
+	tests := []struct {
+		name    string
+		project func(t *testing.T) string
+		args    []string
+	}{
+		{name: "Missing name", project: newProject, args: []string{}},
+		{name: "Invalid name", project: newProject, args: []string{"add users;drop"}},
+		{name: "Unknown format", project: newProject, args: []string{"-format", "flyway", "add_users"}},
+		{name: "Missing manifest", project: func(t *testing.T) string { return t.TempDir() }, args: []string{"add_users"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-project", tt.project(t)}, tt.args...)
+			if err := createMigration(args, logger.New(&bytes.Buffer{}), newReporter("test"), time.Now()); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+
+	if err := runMigrationCommand([]string{"apply"}, logger.New(&bytes.Buffer{}), newReporter("test")); err == nil {
+		t.Error("Expected unknown command error, got nil")
+	}
+}
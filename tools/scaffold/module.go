@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jwill9999/scaffold-go/pkg/deps"
+	"golang.org/x/mod/module"
+)
+
+// reservedModulePaths cannot name a user's module: go and toolchain are
+// the toolchain's own module paths, std and cmd match the standard library
+// patterns of the go command
+var reservedModulePaths = map[string]bool{
+	"go":        true,
+	"toolchain": true,
+	"std":       true,
+	"cmd":       true,
+}
+
+// validateModuleName checks that name is a module path go mod init would
+// accept: a valid import path that is not reserved, whose first element is
+// a valid host name if dotted, whose major version suffix, if any, is /v2
+// or later (.v0 or later for gopkg.in), and which does not collide with a
+// module the generated project requires
+func validateModuleName(name string) error {
+	if name == "" {
+		return fmt.Errorf("module name cannot be empty")
+	}
+	if err := module.CheckImportPath(name); err != nil {
+		return fmt.Errorf("invalid module name: %w", err)
+	}
+
+	first, _, _ := strings.Cut(name, "/")
+	if reservedModulePaths[first] {
+		return fmt.Errorf("invalid module name %q: %s is reserved by the go command", name, first)
+	}
+
+	// A dotted first element is a host name the module can be fetched from
+	if strings.Contains(first, ".") {
+		if err := module.CheckPath(name); err != nil {
+			return fmt.Errorf("invalid module name: %w", err)
+		}
+		for _, label := range strings.Split(first, ".") {
+			if label == "" {
+				return fmt.Errorf("invalid module name %q: empty label in host name %s", name, first)
+			}
+		}
+	}
+
+	if _, _, ok := module.SplitPathVersion(name); !ok {
+		if strings.HasPrefix(name, "gopkg.in/") {
+			return fmt.Errorf("invalid module name %q: module paths beginning with gopkg.in/ must end in a major version suffix such as .v1", name)
+		}
+		return fmt.Errorf("invalid module name %q: major version suffixes must be in the form /vN and are only allowed for v2 or later", name)
+	}
+
+	// Module paths differing only in case collide in the module cache and
+	// on case-insensitive file systems
+	for dep := range deps.Versions {
+		if strings.EqualFold(name, dep) {
+			return fmt.Errorf("invalid module name %q: collides with required module %s", name, dep)
+		}
+	}
+
+	return nil
+}
+
+// projectNameFromModule returns the project name implied by a module path:
+// its last element without a major version suffix, so
+// github.com/acme/shop/v2 is named shop
+func projectNameFromModule(name string) string {
+	prefix, _, ok := module.SplitPathVersion(name)
+	if !ok {
+		prefix = name
+	}
+	return path.Base(prefix)
+}
+
+// checkCaseCollision fails if dir does not exist but a sibling whose name
+// differs only in case does, as both would be the same directory on a
+// case-insensitive file system
+func checkCaseCollision(dir string) error {
+	entries, err := os.ReadDir(filepath.Dir(dir))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Dir(dir), err)
+	}
+
+	base := filepath.Base(dir)
+	for _, entry := range entries {
+		if entry.Name() != base && strings.EqualFold(entry.Name(), base) {
+			return fmt.Errorf("project directory %s collides with existing %s on case-insensitive file systems", base, entry.Name())
+		}
+	}
+	return nil
+}
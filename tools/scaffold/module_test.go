@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectNameFromModule(t *testing.T) {
+	tests := []struct {
+		module string
+		want   string
+	}{
+		{"shop", "shop"},
+		{"github.com/acme/shop", "shop"},
+		{"github.com/acme/shop/v2", "shop"},
+		{"gopkg.in/acme/shop.v3", "shop"},
+		{"example.com/acme/shop-api", "shop-api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			if got := projectNameFromModule(tt.module); got != tt.want {
+				t.Errorf("projectNameFromModule(%q) = %q, want %q", tt.module, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCaseCollision(t *testing.T) {
+	parent := t.TempDir()
+	if err := os.Mkdir(filepath.Join(parent, "Shop"), 0750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	tests := []struct {
+		dir     string
+		wantErr bool
+	}{
+		{filepath.Join(parent, "Shop"), false},
+		{filepath.Join(parent, "cart"), false},
+		{filepath.Join(parent, "missing", "shop"), false},
+		{filepath.Join(parent, "shop"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.dir), func(t *testing.T) {
+			err := checkCaseCollision(tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkCaseCollision(%s) error = %v, wantErr %v", tt.dir, err, tt.wantErr)
+			}
+		})
+	}
+}
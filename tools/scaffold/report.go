@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+)
+
+// reportJSON is the only -report format
+const reportJSON = "json"
+
+// reporter collects the report of a command, written to stdout once the
+// command finishes when -report is set
+type reporter struct {
+	*generator.Report
+	format string
+}
+
+func newReporter(command string) *reporter {
+	return &reporter{Report: generator.NewReport(command)}
+}
+
+// registerFlag adds the -report flag to fs
+func (r *reporter) registerFlag(fs *flag.FlagSet) {
+	fs.Func("report", "Write a machine-readable report of the run to stdout (json)", func(value string) error {
+		if value != reportJSON {
+			return fmt.Errorf("unsupported report format %q (supported: json)", value)
+		}
+		r.format = value
+		return nil
+	})
+}
+
+// finish records the outcome of the command and writes the report if one
+// was requested
+func (r *reporter) finish(err error, out io.Writer) error {
+	r.Finish(err)
+	if r.format == "" {
+		return nil
+	}
+	return r.WriteJSON(out)
+}
+
+// parseFlags parses a command's flags, tagging failures as invalid arguments
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	return generator.WithCode(generator.CodeInvalidArguments, fs.Parse(args))
+}
+
+// loadManifest reads the manifest of the project a subcommand runs on.
+// Subcommands follow the choices the project was generated with, so a
+// directory without a manifest is rejected rather than given defaults.
+func loadManifest(project string) (*generator.Manifest, error) {
+	manifest, err := generator.LoadManifest(project)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, generator.WithCode(generator.CodeInvalidArguments, fmt.Errorf(
+			"%s is not a generated project: %s not found; run the command in the project directory or pass -project",
+			project, generator.ManifestFile))
+	}
+	return manifest, err
+}
+
+// saveManifest writes the project manifest and records it in the report
+func saveManifest(manifest *generator.Manifest, project string, report *generator.Report) error {
+	path := filepath.Join(project, generator.ManifestFile)
+	_, statErr := os.Stat(path)
+
+	if err := manifest.Save(project); err != nil {
+		return err
+	}
+	report.AddWritten(path, statErr == nil)
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+)
+
+func TestResourceCommandReport(t *testing.T) {
+	chdirRepoRoot(t)
+	project := newProject(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	run := func(args ...string) (*generator.Report, string) {
+		rep := newReporter("resource")
+		args = append([]string{"-project", project, "-report", "json"}, args...)
+		err := runResourceCommand(args, strings.NewReader(""), logger.New(&bytes.Buffer{}), rep, now)
+
+		var out bytes.Buffer
+		if reportErr := rep.finish(err, &out); reportErr != nil {
+			t.Fatalf("Failed to write report: %v", reportErr)
+		}
+		var report generator.Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatalf("Report is not valid JSON: %v\n%s", err, out.String())
+		}
+		return &report, out.String()
+	}
+
+	actions := func(report *generator.Report) map[string]generator.ReportFile {
+		files := make(map[string]generator.ReportFile)
+		for _, f := range report.Files {
+			rel, err := filepath.Rel(project, f.Path)
+			if err != nil {
+				t.Fatalf("Unexpected path %s: %v", f.Path, err)
+			}
+			files[filepath.ToSlash(rel)] = f
+		}
+		return files
+	}
+
+	report, out := run("-name", "User", "-fields", "name:string:required")
+	if !report.Success || report.Command != "resource" {
+		t.Fatalf("Expected a successful resource report, got:\n%s", out)
+	}
+	files := actions(report)
+	handler := files["internal/handlers/user_handler.go"]
+	if handler.Action != generator.FileCreated {
+		t.Errorf("Expected the handler to be created, got %q", handler.Action)
+	}
+	content, err := os.ReadFile(filepath.Join(project, "internal/handlers/user_handler.go"))
+	if err != nil {
+		t.Fatalf("Failed to read handler: %v", err)
+	}
+	sum := sha256.Sum256(content)
+	if handler.SHA256 != hex.EncodeToString(sum[:]) || handler.Size != int64(len(content)) {
+		t.Errorf("Expected the handler checksum and size to match the file, got %+v", handler)
+	}
+	if files[generator.ManifestFile].Action != generator.FileOverwritten {
+		t.Errorf("Expected the manifest update to be recorded, got:\n%s", out)
+	}
+	if report.Project == nil || report.Project.Router != generator.RouterGin {
+		t.Errorf("Expected the project settings, got %+v", report.Project)
+	}
+	if len(report.Timings) == 0 {
+		t.Error("Expected phase timings")
+	}
+
+	// Regenerating keeps hand-written code and rewrites generated files
+	report, out = run("-name", "User", "-fields", "name:string:required age:int")
+	files = actions(report)
+	if f := files["internal/handlers/user_handler.go"]; f.Action != generator.FileSkipped || f.Reason == "" {
+		t.Errorf("Expected the handler to be skipped with a reason, got %+v", f)
+	}
+	if f := files["internal/models/user.go"]; f.Action != generator.FileOverwritten {
+		t.Errorf("Expected the model to be overwritten, got %+v", f)
+	}
+
+	report, out = run("-name", "User")
+	if report.Success || len(report.Errors) != 1 || report.Errors[0].Code != generator.CodeInvalidArguments {
+		t.Errorf("Expected an invalid_arguments error, got:\n%s", out)
+	}
+
+	report, out = run("-name", "User", "-fields", "name:blob")
+	if len(report.Errors) != 1 || report.Errors[0].Code != generator.CodeInvalidInput {
+		t.Errorf("Expected an invalid_input error, got:\n%s", out)
+	}
+
+	// Destructive changes are warnings of the run, whether or not they are
+	// confirmed
+	wantWarning := "destructive change to users: drop column age"
+	report, out = run("-name", "User", "-fields", "name:string:required")
+	if report.Success || len(report.Errors) != 1 || report.Errors[0].Code != generator.CodeAborted {
+		t.Errorf("Expected an aborted error, got:\n%s", out)
+	}
+	if len(report.Warnings) != 1 || !strings.HasPrefix(report.Warnings[0], wantWarning) {
+		t.Errorf("Expected a warning about the unconfirmed drop, got:\n%s", out)
+	}
+
+	report, out = run("-name", "User", "-fields", "name:string:required", "-yes")
+	if !report.Success {
+		t.Fatalf("Expected the confirmed drop to succeed, got:\n%s", out)
+	}
+	if len(report.Warnings) != 1 || !strings.HasPrefix(report.Warnings[0], wantWarning) {
+		t.Errorf("Expected a warning about the confirmed drop, got:\n%s", out)
+	}
+}
+
+func TestReportFlag(t *testing.T) {
+	var out bytes.Buffer
+	rep := newReporter("resource")
+	err := runResourceCommand([]string{"-report", "yaml"}, strings.NewReader(""), logger.New(&out), rep, time.Now())
+	if err == nil || generator.ErrorCode(err) != generator.CodeInvalidArguments {
+		t.Fatalf("Expected an invalid_arguments error for an unknown format, got %v", err)
+	}
+
+	// Without -report nothing is written
+	var report bytes.Buffer
+	if err := rep.finish(err, &report); err != nil {
+		t.Fatalf("finish failed: %v", err)
+	}
+	if report.Len() != 0 {
+		t.Errorf("Expected no report, got:\n%s", report.String())
+	}
+}
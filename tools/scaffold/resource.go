@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+)
+
+// runResourceCommand handles `scaffold resource`. New resources get a
+// create-table migration; resources already recorded in the project manifest
+// get a migration for the difference between the stored and new fields. The
+// model, repository, service and handler are generated in both cases.
+func runResourceCommand(args []string, in io.Reader, log *logger.Logger, rep *reporter, now time.Time) error {
+	if len(args) > 0 && args[0] == "import" {
+		return importResources(args[1:], log, rep, now)
+	}
+
+	fs := flag.NewFlagSet("resource", flag.ContinueOnError)
+	fs.SetOutput(log.Writer())
+	log.RegisterFlags(fs)
+	rep.registerFlag(fs)
+	rep.Command = fs.Name()
+	project := fs.String("project", ".", "Project directory containing "+generator.ManifestFile)
+	name := fs.String("name", "", "Resource name (e.g. User, Product)")
+	fieldSpec := fs.String("fields", "", "Field definitions (name:type:validation[,validation] ...)")
+	renames := fs.String("rename", "", "Comma-separated column renames (old=new)")
+	yes := fs.Bool("yes", false, "Confirm destructive changes without prompting; renames still need -rename")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *name == "" || *fieldSpec == "" {
+		return generator.WithCode(generator.CodeInvalidArguments, fmt.Errorf("resource name and fields are required"))
+	}
+
+	fields, err := generator.ParseFields(*fieldSpec)
+	if err != nil {
+		return generator.WithCode(generator.CodeInvalidInput, err)
+	}
+
+	res, err := generator.NewResource(*name, fields)
+	if err != nil {
+		return generator.WithCode(generator.CodeInvalidInput, err)
+	}
+
+	renameMap, err := parseRenames(*renames)
+	if err != nil {
+		return generator.WithCode(generator.CodeInvalidArguments, err)
+	}
+
+	manifest, err := loadManifest(*project)
+	if err != nil {
+		return err
+	}
+
+	tmplGen, err := newProjectGenerator(*project, manifest)
+	if err != nil {
+		return err
+	}
+	tmplGen.Report = rep.Report
+	rep.SetProject(tmplGen)
+	prompt := &prompter{in: bufio.NewReader(in), out: log.Writer(), yes: *yes}
+
+	var files []string
+	done := rep.Time("migration")
+	if existing := manifest.Resource(res.Name); existing == nil {
+		files, err = tmplGen.GenerateMigration(res, now)
+		if err != nil {
+			return fmt.Errorf("failed to generate migration: %w", err)
+		}
+	} else {
+		previous, err := existing.Resource()
+		if err != nil {
+			return err
+		}
+
+		diff, err := diffWithRenames(previous, res, renameMap, prompt)
+		if err != nil {
+			return err
+		}
+
+		if diff.Empty() {
+			log.Info("No schema changes for %s", res.TableName())
+		} else {
+			if err := confirmDestructive(diff, prompt, rep); err != nil {
+				return err
+			}
+
+			files, err = tmplGen.GenerateAlterMigration(diff, now)
+			if err != nil {
+				return fmt.Errorf("failed to generate migration: %w", err)
+			}
+		}
+	}
+	done()
+
+	res.Proto, err = manifest.ProtoNumbers(res)
+	if err != nil {
+		return err
+	}
+
+	done = rep.Time("code")
+	code, err := tmplGen.GenerateResource(res)
+	done()
+	if err != nil {
+		return fmt.Errorf("failed to generate resource: %w", err)
+	}
+	files = append(files, code...)
+
+	manifest.SetResource(res)
+	done = rep.Time("docs")
+	docs, err := generateAPIDocs(*project, manifest, tmplGen)
+	done()
+	if err != nil {
+		return err
+	}
+	files = append(files, docs...)
+
+	done = rep.Time("graphql")
+	gql, err := generateGraphQL(manifest, tmplGen)
+	done()
+	if err != nil {
+		return err
+	}
+	files = append(files, gql...)
+
+	if err := saveManifest(manifest, *project, rep.Report); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		log.Info("Created %s", f)
+	}
+
+	return nil
+}
+
+// diffWithRenames diffs two resource versions, asking whether each dropped
+// and added column pair of the same type is a rename. A guessed rename is
+// never applied under -yes: that only confirms destructive changes, so the
+// pair stays a drop and an add unless it is given with -rename.
+func diffWithRenames(previous, res generator.Resource, renames map[string]string, prompt *prompter) (generator.SchemaDiff, error) {
+	diff, err := generator.DiffResources(previous, res, renames)
+	if err != nil {
+		return diff, err
+	}
+
+	confirmed := false
+	for _, pair := range diff.RenameCandidates() {
+		from, to := pair[0].Column(), pair[1].Column()
+		if prompt.yes {
+			fmt.Fprintf(prompt.out, "Column %s was removed and %s was added; pass -rename %s=%s to rename it instead\n", from, to, from, to)
+			continue
+		}
+		question := fmt.Sprintf("Column %s was removed and %s was added. Rename %s to %s instead?", from, to, from, to)
+		if prompt.confirm(question) {
+			renames[from] = to
+			confirmed = true
+		}
+	}
+
+	if !confirmed {
+		return diff, nil
+	}
+	return generator.DiffResources(previous, res, renames)
+}
+
+// confirmDestructive reports destructive changes loudly, records them as
+// warnings of the run, and requires them to be confirmed before a migration
+// is written
+func confirmDestructive(diff generator.SchemaDiff, prompt *prompter, rep *reporter) error {
+	destructive := diff.Destructive()
+	if len(destructive) == 0 {
+		return nil
+	}
+
+	table := diff.Resource.TableName()
+	fmt.Fprintf(prompt.out, "\n!!! DESTRUCTIVE CHANGES TO %s !!!\n", strings.ToUpper(table))
+	for _, c := range destructive {
+		reasons := strings.Join(c.Reasons, "; ")
+		fmt.Fprintf(prompt.out, "  - %s: %s\n", c, reasons)
+		rep.Warn("destructive change to %s: %s: %s", table, c, reasons)
+	}
+	fmt.Fprintln(prompt.out)
+
+	if !prompt.confirm("Generate a migration with these destructive changes?") {
+		return generator.WithCode(generator.CodeAborted, fmt.Errorf("aborted: destructive changes were not confirmed"))
+	}
+	return nil
+}
+
+// parseRenames parses old=new pairs separated by commas
+func parseRenames(spec string) (map[string]string, error) {
+	renames := make(map[string]string)
+	if spec == "" {
+		return renames, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid rename %q: expected old=new", pair)
+		}
+		renames[from] = to
+	}
+	return renames, nil
+}
+
+// generateAPIDocs rewrites the project's OpenAPI document and Postman
+// collection from every resource in the manifest. Projects that do not
+// serve HTTP have neither.
+func generateAPIDocs(project string, manifest *generator.Manifest, tmplGen *generator.TemplateGenerator) ([]string, error) {
+	if !tmplGen.Transports[generator.TransportHTTP] {
+		return nil, nil
+	}
+
+	resources, err := manifest.AllResources()
+	if err != nil {
+		return nil, err
+	}
+
+	title := manifest.Name
+	if title == "" {
+		if abs, err := filepath.Abs(project); err == nil {
+			title = abs
+		}
+	}
+	title = filepath.Base(title)
+
+	spec, err := tmplGen.GenerateOpenAPISpec(title, resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate OpenAPI document: %w", err)
+	}
+
+	postman, err := tmplGen.GeneratePostman(title, resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Postman collection: %w", err)
+	}
+	return append([]string{spec}, postman...), nil
+}
+
+// generateGraphQL rewrites the GraphQL schema and resolvers from every
+// resource in the manifest when the project serves GraphQL
+func generateGraphQL(manifest *generator.Manifest, tmplGen *generator.TemplateGenerator) ([]string, error) {
+	if !tmplGen.Transports[generator.TransportGraphQL] {
+		return nil, nil
+	}
+
+	resources, err := manifest.AllResources()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := tmplGen.GenerateGraphQL(resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate GraphQL schema: %w", err)
+	}
+	return files, nil
+}
+
+// newProjectGenerator returns a template generator configured from the
+// project manifest
+func newProjectGenerator(project string, manifest *generator.Manifest) (*generator.TemplateGenerator, error) {
+	tmplGen := generator.NewTemplateGenerator(project, manifest.Module, manifest.Features, nil)
+	tmplGen.Database = manifest.Database
+	tmplGen.Migrations = manifest.Migrations
+	tmplGen.SetTransports(manifest.Transports)
+	if err := tmplGen.SetRouter(manifest.Router); err != nil {
+		return nil, err
+	}
+	if err := tmplGen.SetLogger(manifest.Logger); err != nil {
+		return nil, err
+	}
+	return tmplGen, nil
+}
+
+// prompter asks yes/no questions, answering yes automatically when -yes is set
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	yes bool
+}
+
+func (p *prompter) confirm(question string) bool {
+	if p.yes {
+		fmt.Fprintf(p.out, "%s [y/N] y\n", question)
+		return true
+	}
+
+	fmt.Fprintf(p.out, "%s [y/N] ", question)
+	answer, _ := p.in.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+)
+
+// importResources handles `scaffold resource import`. It builds resources
+// from the CREATE TABLE statements in a DDL file and generates their code.
+// The tables already exist, so migrations are only written with -migrate.
+func importResources(args []string, log *logger.Logger, rep *reporter, now time.Time) error {
+	fs := flag.NewFlagSet("resource import", flag.ContinueOnError)
+	fs.SetOutput(log.Writer())
+	log.RegisterFlags(fs)
+	rep.registerFlag(fs)
+	rep.Command = fs.Name()
+	project := fs.String("project", ".", "Project directory containing "+generator.ManifestFile)
+	ddl := fs.String("ddl", "", "SQL file containing CREATE TABLE statements")
+	tables := fs.String("tables", "", "Comma-separated tables to import (default all)")
+	migrate := fs.Bool("migrate", false, "Also write create-table migrations for the imported tables")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *ddl == "" {
+		return generator.WithCode(generator.CodeInvalidArguments, fmt.Errorf("a DDL file is required"))
+	}
+
+	// #nosec G304 - the DDL file is chosen by the user running the command
+	content, err := os.ReadFile(filepath.Clean(*ddl))
+	if err != nil {
+		return generator.WithCode(generator.CodeInvalidInput, fmt.Errorf("failed to read DDL file: %w", err))
+	}
+
+	done := rep.Time("parse")
+	schema, err := generator.ParseDDL(string(content))
+	done()
+	if err != nil {
+		return generator.WithCode(generator.CodeInvalidInput, fmt.Errorf("failed to parse %s: %w", *ddl, err))
+	}
+
+	resources, err := selectTables(schema.Resources, *tables)
+	if err != nil {
+		return generator.WithCode(generator.CodeInvalidArguments, err)
+	}
+
+	for _, w := range schema.Warnings {
+		log.Warn("%s", w)
+		rep.Warn("%s", w)
+	}
+
+	manifest, err := loadManifest(*project)
+	if err != nil {
+		return err
+	}
+	tmplGen, err := newProjectGenerator(*project, manifest)
+	if err != nil {
+		return err
+	}
+	tmplGen.Report = rep.Report
+	rep.SetProject(tmplGen)
+
+	var files []string
+	done = rep.Time("code")
+	for _, res := range resources {
+		if manifest.Resource(res.Name) != nil {
+			log.Info("Skipping %s: resource already exists", res.Name)
+			rep.Warn("skipped %s: resource already exists", res.Name)
+			continue
+		}
+
+		if *migrate {
+			written, err := tmplGen.GenerateMigration(res, now)
+			if err != nil {
+				return fmt.Errorf("failed to generate migration for %s: %w", res.Name, err)
+			}
+			files = append(files, written...)
+		}
+
+		res.Proto = generator.NumberProtoFields(res.Fields, nil)
+		written, err := tmplGen.GenerateResource(res)
+		if err != nil {
+			return fmt.Errorf("failed to generate resource %s: %w", res.Name, err)
+		}
+		files = append(files, written...)
+
+		manifest.SetResource(res)
+		log.Info("Imported %s from table %s (%d fields)", res.Name, res.TableName(), len(res.Fields))
+	}
+	done()
+
+	done = rep.Time("docs")
+	docs, err := generateAPIDocs(*project, manifest, tmplGen)
+	done()
+	if err != nil {
+		return err
+	}
+	files = append(files, docs...)
+
+	done = rep.Time("graphql")
+	gql, err := generateGraphQL(manifest, tmplGen)
+	done()
+	if err != nil {
+		return err
+	}
+	files = append(files, gql...)
+
+	if err := saveManifest(manifest, *project, rep.Report); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		log.Info("Created %s", f)
+	}
+
+	return nil
+}
+
+// selectTables filters resources to the named tables
+func selectTables(resources []generator.Resource, spec string) ([]generator.Resource, error) {
+	if spec == "" {
+		return resources, nil
+	}
+
+	var selected []generator.Resource
+	for _, name := range strings.Split(spec, ",") {
+		found := false
+		for _, res := range resources {
+			if strings.EqualFold(res.TableName(), name) {
+				selected = append(selected, res)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("table %s was not found in the DDL file", name)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+)
+
+func TestResourceCommandSchemaChanges(t *testing.T) {
+	chdirRepoRoot(t)
+	project := newProject(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	run := func(input string, args ...string) (string, error) {
+		var out bytes.Buffer
+		args = append([]string{"-project", project, "-name", "User"}, args...)
+		err := runResourceCommand(args, strings.NewReader(input), logger.New(&out), newReporter("test"), now)
+		return out.String(), err
+	}
+
+	if _, err := run("", "-fields", "name:string:required age:int"); err != nil {
+		t.Fatalf("Initial resource generation failed: %v", err)
+	}
+
+	// Declining the rename turns it into a drop, which must be confirmed
+	out, err := run("n\nn\n", "-fields", "full_name:string:required age:int")
+	if err == nil {
+		t.Fatal("Expected unconfirmed destructive change to abort")
+	}
+	if !strings.Contains(out, "DESTRUCTIVE CHANGES TO USERS") {
+		t.Errorf("Expected destructive warning, got:\n%s", out)
+	}
+
+	// Confirming the rename avoids the destructive drop
+	if _, err := run("y\n", "-fields", "full_name:string:required age:int"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+
+	out, err = run("", "-fields", "full_name:string:required age:int")
+	if err != nil {
+		t.Fatalf("Unchanged resource failed: %v", err)
+	}
+	if !strings.Contains(out, "No schema changes") {
+		t.Errorf("Expected no schema changes, got:\n%s", out)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(project, "migrations"))
+	if err != nil {
+		t.Fatalf("Failed to read migrations: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected create and update migrations, got %d files", len(entries))
+	}
+
+	update, err := os.ReadFile(filepath.Join(project, "migrations", entries[1].Name()))
+	if err != nil {
+		t.Fatalf("Failed to read update migration: %v", err)
+	}
+	if !strings.Contains(string(update), `RENAME COLUMN "name" TO "full_name"`) {
+		t.Errorf("Expected rename in update migration, got:\n%s", update)
+	}
+
+	manifest, err := generator.LoadManifest(project)
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+	def := manifest.Resource("User")
+	if def == nil || def.Fields[0] != "full_name:string:required" {
+		t.Errorf("Expected manifest to record the new fields, got %+v", def)
+	}
+
+	spec, err := os.ReadFile(filepath.Join(project, generator.OpenAPISpecFile))
+	if err != nil {
+		t.Fatalf("Failed to read OpenAPI document: %v", err)
+	}
+	if !strings.Contains(string(spec), "full_name:") || strings.Contains(string(spec), "\n        name:") {
+		t.Errorf("Expected OpenAPI document to follow the renamed field, got:\n%s", spec)
+	}
+}
+
+func TestResourceCommandYes(t *testing.T) {
+	chdirRepoRoot(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		notWant string
+	}{
+		{
+			name:    "Guessed rename stays a drop",
+			args:    []string{"-yes"},
+			want:    `DROP COLUMN "name"`,
+			notWant: "RENAME COLUMN",
+		},
+		{
+			name:    "Explicit rename",
+			args:    []string{"-yes", "-rename", "name=full_name"},
+			want:    `RENAME COLUMN "name" TO "full_name"`,
+			notWant: "DROP COLUMN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := newProject(t)
+			run := func(args ...string) (string, error) {
+				var out bytes.Buffer
+				args = append([]string{"-project", project, "-name", "User"}, args...)
+				// -yes must not read answers, so any input would go unused
+				err := runResourceCommand(args, strings.NewReader("y\ny\n"), logger.New(&out), newReporter("test"), now)
+				return out.String(), err
+			}
+
+			if _, err := run("-fields", "name:string:required age:int"); err != nil {
+				t.Fatalf("Initial resource generation failed: %v", err)
+			}
+			if out, err := run(append(tt.args, "-fields", "full_name:string:required age:int")...); err != nil {
+				t.Fatalf("Resource update failed: %v\n%s", err, out)
+			}
+
+			entries, err := os.ReadDir(filepath.Join(project, "migrations"))
+			if err != nil {
+				t.Fatalf("Failed to read migrations: %v", err)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("Expected create and update migrations, got %d files", len(entries))
+			}
+			update, err := os.ReadFile(filepath.Join(project, "migrations", entries[1].Name()))
+			if err != nil {
+				t.Fatalf("Failed to read update migration: %v", err)
+			}
+			if !strings.Contains(string(update), tt.want) || strings.Contains(string(update), tt.notWant) {
+				t.Errorf("Expected update migration with %q and without %q, got:\n%s", tt.want, tt.notWant, update)
+			}
+		})
+	}
+}
+
+func TestParseRenames(t *testing.T) {
+	renames, err := parseRenames("name=full_name,age=years")
+	if err != nil {
+		t.Fatalf("parseRenames() error = %v", err)
+	}
+	if renames["name"] != "full_name" || renames["age"] != "years" {
+		t.Errorf("Unexpected renames: %v", renames)
+	}
+
+	if _, err := parseRenames("name"); err == nil {
+		t.Error("Expected error for rename without target, got nil")
+	}
+}
+
+func TestImportResources(t *testing.T) {
+	chdirRepoRoot(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ddl := filepath.Join(t.TempDir(), "schema.sql")
+	schema := `
+CREATE TABLE users (
+    id bigserial PRIMARY KEY,
+    email varchar(255) NOT NULL UNIQUE,
+    role varchar(10) NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'admin')),
+    last_seen_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    deleted_at timestamptz
+);
+CREATE TABLE posts (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title text NOT NULL,
+    published boolean NOT NULL DEFAULT false,
+    metadata jsonb,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    deleted_at timestamptz
+);`
+	if err := os.WriteFile(ddl, []byte(schema), 0600); err != nil {
+		t.Fatalf("Failed to write DDL: %v", err)
+	}
+
+	for _, db := range []string{"postgres", "mysql", "sqlite"} {
+		t.Run(db, func(t *testing.T) {
+			project := t.TempDir()
+			manifest := &generator.Manifest{Module: "github.com/example/app", Database: db, Migrations: generator.DefaultMigrationSettings()}
+			if err := manifest.Save(project); err != nil {
+				t.Fatalf("Failed to save manifest: %v", err)
+			}
+
+			var out bytes.Buffer
+			if err := runResourceCommand([]string{"import", "-project", project, "-ddl", ddl}, strings.NewReader(""), logger.New(&out), newReporter("test"), now); err != nil {
+				t.Fatalf("Import failed: %v\n%s", err, out.String())
+			}
+
+			for _, file := range []string{
+				"internal/models/user.go",
+				"internal/models/post.go",
+				"internal/models/pagination.go",
+				"internal/repository/user_repository.go",
+				"internal/repository/post_repository.go",
+				"internal/repository/helpers.go",
+				"internal/services/post_service.go",
+				"internal/handlers/post_handler.go",
+			} {
+				if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(project, file), nil, 0); err != nil {
+					t.Errorf("Generated %s is not valid Go: %v", file, err)
+				}
+			}
+
+			// Existing tables need no migrations unless -migrate is given
+			if _, err := os.Stat(filepath.Join(project, "migrations")); !os.IsNotExist(err) {
+				t.Errorf("Expected no migrations to be written")
+			}
+
+			loaded, err := generator.LoadManifest(project)
+			if err != nil {
+				t.Fatalf("Failed to load manifest: %v", err)
+			}
+			post := loaded.Resource("Post")
+			if post == nil || post.Fields[0] != "user_id:ref:required,ref=users,ondelete=cascade" {
+				t.Errorf("Expected manifest to record the imported fields, got %+v", post)
+			}
+
+			// Importing again leaves existing resources alone
+			out.Reset()
+			if err := runResourceCommand([]string{"import", "-project", project, "-ddl", ddl, "-tables", "users"}, strings.NewReader(""), logger.New(&out), newReporter("test"), now); err != nil {
+				t.Fatalf("Second import failed: %v", err)
+			}
+			if !strings.Contains(out.String(), "Skipping User: resource already exists") {
+				t.Errorf("Expected existing resource to be skipped, got:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestResourceCommandGRPC(t *testing.T) {
+	chdirRepoRoot(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	project := t.TempDir()
+	manifest := &generator.Manifest{
+		Module:     "github.com/example/app",
+		Database:   "postgres",
+		Transports: []string{generator.TransportGRPC},
+		Migrations: generator.DefaultMigrationSettings(),
+	}
+	if err := manifest.Save(project); err != nil {
+		t.Fatalf("Failed to save manifest: %v", err)
+	}
+
+	var out bytes.Buffer
+	args := []string{"-project", project, "-name", "OrderItem", "-fields", "name:string:required owner_id:ref:ref=users due:time:required"}
+	if err := runResourceCommand(args, strings.NewReader(""), logger.New(&out), newReporter("test"), now); err != nil {
+		t.Fatalf("Resource generation failed: %v\n%s", err, out.String())
+	}
+
+	server := filepath.Join(project, "internal/grpcserver/order_item_server.go")
+	if _, err := parser.ParseFile(token.NewFileSet(), server, nil, 0); err != nil {
+		t.Errorf("Generated gRPC server is not valid Go: %v", err)
+	}
+
+	proto, err := os.ReadFile(filepath.Join(project, "api/proto/order_item/v1/order_item.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read proto: %v", err)
+	}
+	for _, want := range []string{
+		"package order_item.v1;",
+		`option go_package = "github.com/example/app/gen/proto/order_item/v1;orderitemv1";`,
+		"rpc ListOrderItems(ListOrderItemsRequest) returns (ListOrderItemsResponse);",
+		"  string name = 4;\n  optional uint64 owner_id = 5;\n  google.protobuf.Timestamp due = 6;",
+	} {
+		if !strings.Contains(string(proto), want) {
+			t.Errorf("Expected proto to contain %q, got:\n%s", want, proto)
+		}
+	}
+
+	// A gRPC-only project has no HTTP handler or HTTP documentation
+	for _, file := range []string{"internal/handlers/order_item_handler.go", generator.OpenAPISpecFile} {
+		if _, err := os.Stat(filepath.Join(project, file)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s not to be generated", file)
+		}
+	}
+
+	// Removing a field reserves its number, and fields after it keep theirs
+	out.Reset()
+	args = []string{"-project", project, "-name", "OrderItem", "-yes", "-fields", "name:string:required due:time:required note:text"}
+	if err := runResourceCommand(args, strings.NewReader(""), logger.New(&out), newReporter("test"), now.Add(time.Hour)); err != nil {
+		t.Fatalf("Resource update failed: %v\n%s", err, out.String())
+	}
+	proto, err = os.ReadFile(filepath.Join(project, "api/proto/order_item/v1/order_item.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read proto: %v", err)
+	}
+	for _, want := range []string{
+		"  string name = 4;\n  google.protobuf.Timestamp due = 6;\n  optional string note = 7;\n  reserved 5;\n  reserved \"owner_id\";\n}",
+		"  string name = 1;\n  google.protobuf.Timestamp due = 3;\n  optional string note = 4;\n  reserved 2;\n  reserved \"owner_id\";\n}",
+	} {
+		if !strings.Contains(string(proto), want) {
+			t.Errorf("Expected proto to contain %q, got:\n%s", want, proto)
+		}
+	}
+
+	manifest, err = generator.LoadManifest(project)
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+	def := manifest.Resource("OrderItem")
+	if def == nil || def.Proto == nil || len(def.Proto.Reserved) != 1 || def.Proto.Reserved[0].Number != 2 {
+		t.Errorf("Expected the manifest to record the field numbers, got %+v", def)
+	}
+}
+
+func TestResourceCommandGraphQL(t *testing.T) {
+	chdirRepoRoot(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	project := t.TempDir()
+	manifest := &generator.Manifest{
+		Module:     "github.com/example/app",
+		Database:   "postgres",
+		Transports: []string{generator.TransportGraphQL, generator.TransportHTTP},
+		Migrations: generator.DefaultMigrationSettings(),
+	}
+	if err := manifest.Save(project); err != nil {
+		t.Fatalf("Failed to save manifest: %v", err)
+	}
+
+	var out bytes.Buffer
+	for i, fields := range []string{
+		"name:string:required email:string:required,email",
+		"title:string:required author_id:ref:required,ref=users views:int meta:json",
+	} {
+		name := []string{"User", "BlogPost"}[i]
+		args := []string{"-project", project, "-name", name, "-fields", fields}
+		if err := runResourceCommand(args, strings.NewReader(""), logger.New(&out), newReporter("test"), now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("Resource generation failed: %v\n%s", err, out.String())
+		}
+	}
+
+	for _, file := range []string{"resolver.go", "loaders.go", "user_resolver.go", "blog_post_resolver.go"} {
+		path := filepath.Join(project, "internal/gql", file)
+		if _, err := parser.ParseFile(token.NewFileSet(), path, nil, 0); err != nil {
+			t.Errorf("Generated %s is not valid Go: %v", file, err)
+		}
+	}
+
+	schema, err := os.ReadFile(filepath.Join(project, "internal/gql/schema.graphql"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	for _, want := range []string{
+		"  user(id: ID!): User\n",
+		`  blogPosts(offset: Int = 0, limit: Int = 10, sortBy: String = "id", sortDir: String = "asc"): BlogPostPage!`,
+		"  createBlogPost(input: BlogPostInput!): BlogPost!",
+		"  authorId: ID!\n  views: Int64\n  meta: JSON\n  author: User\n",
+	} {
+		if !strings.Contains(string(schema), want) {
+			t.Errorf("Expected schema to contain %q, got:\n%s", want, schema)
+		}
+	}
+
+	// Posts reference users, so users are batched through a dataloader
+	loaders, err := os.ReadFile(filepath.Join(project, "internal/gql/loaders.go"))
+	if err != nil {
+		t.Fatalf("Failed to read loaders: %v", err)
+	}
+	if !strings.Contains(string(loaders), "User: dataloader.NewBatchedLoader(batchByID(r.UserRepository.GetByIDs") {
+		t.Errorf("Expected a user loader, got:\n%s", loaders)
+	}
+}
+
+// TestCommandsInProject runs the code generating subcommands from inside a
+// generated project, the way users run them, rather than from the repository
+func TestCommandsInProject(t *testing.T) {
+	chdir(t, generateProject(t))
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := os.WriteFile("schema.sql", []byte("CREATE TABLE tags (id bigserial PRIMARY KEY, label text NOT NULL);"), 0600); err != nil {
+		t.Fatalf("Failed to write DDL: %v", err)
+	}
+	if err := os.WriteFile("openapi.yaml", []byte(petstoreSpec), 0600); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		run  func(log *logger.Logger) error
+		want string
+	}{
+		{
+			name: "resource",
+			run: func(log *logger.Logger) error {
+				args := []string{"-name", "Product", "-fields", "title:string:required"}
+				return runResourceCommand(args, strings.NewReader(""), log, newReporter("test"), now)
+			},
+			want: "internal/handlers/product_handler.go",
+		},
+		{
+			name: "resource import",
+			run: func(log *logger.Logger) error {
+				return runResourceCommand([]string{"import", "-ddl", "schema.sql"}, strings.NewReader(""), log, newReporter("test"), now)
+			},
+			want: "internal/repository/tag_repository.go",
+		},
+		{
+			name: "import openapi",
+			run: func(log *logger.Logger) error {
+				return runImportCommand([]string{"openapi", "openapi.yaml"}, log, newReporter("test"))
+			},
+			want: "internal/handlers/pets_api.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tt.run(logger.New(&out)); err != nil {
+				t.Fatalf("%s failed: %v\n%s", tt.name, err, out.String())
+			}
+			if _, err := os.Stat(tt.want); err != nil {
+				t.Errorf("Expected %s to be generated: %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/jwill9999/scaffold-go/pkg/deps"
+)
+
+// anyType matches the any alias, which is the same type as interface{}
+var anyType = regexp.MustCompile(`\bany\b`)
+
+// stubOf matches the package doc of a stub naming what it models
+var stubOf = regexp.MustCompile(`stub of (\S+) at (v\S+?)\.?(?:\s|$)`)
+
+// TestStubsMatchPinnedVersions checks every stub models the version of its
+// module the catalog pins, and, when that version can be downloaded, that
+// each function, method and type the stub declares exists with the same
+// signature in the real package. A stub written from what the templates
+// call rather than the pinned API would let projects that do not build
+// pass the type-check.
+func TestStubsMatchPinnedVersions(t *testing.T) {
+	chdirRepoRoot(t)
+
+	packages := stubPackageDirs(t)
+	for _, path := range packages {
+		t.Run(path, func(t *testing.T) {
+			dir := filepath.Join(stubDir, filepath.FromSlash(path))
+			stub := parsePackage(t, dir)
+
+			var doc string
+			for _, f := range stub {
+				if f.Doc != nil {
+					doc += f.Doc.Text()
+				}
+			}
+			match := stubOf.FindStringSubmatch(strings.Join(strings.Fields(doc), " "))
+			if match == nil {
+				t.Fatalf("Package doc of the stub does not name the version it models: %q", doc)
+			}
+			if match[1] != path {
+				t.Errorf("Stub in %s models %s", path, match[1])
+			}
+
+			module := pinnedModule(path)
+			if module == "" {
+				t.Fatalf("No module in the catalog provides %s", path)
+			}
+			if match[2] != deps.Versions[module] {
+				t.Fatalf("Stub models %s at %s, but the catalog pins %s at %s", path, match[2], module, deps.Versions[module])
+			}
+
+			root, ok := downloadModule(t, module, deps.Versions[module])
+			if !ok {
+				return
+			}
+			rel := strings.TrimPrefix(strings.TrimPrefix(path, module), "/")
+			real := declarations(parsePackage(t, filepath.Join(root, filepath.FromSlash(rel))))
+			for name, sigs := range declarations(stub) {
+				realSigs, ok := real[name]
+				if !ok {
+					t.Errorf("%s is not declared by %s@%s", name, module, deps.Versions[module])
+					continue
+				}
+				for sig := range sigs {
+					if sig != "" && !realSigs[sig] {
+						t.Errorf("%s is %s in the stub, but %s in %s@%s", name, sig, strings.Join(keys(realSigs), " or "), module, deps.Versions[module])
+					}
+				}
+			}
+		})
+	}
+}
+
+// stubPackageDirs returns the import paths of the stub packages
+func stubPackageDirs(t *testing.T) []string {
+	t.Helper()
+
+	var packages []string
+	err := filepath.WalkDir(stubDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.go"))
+		if err != nil || len(matches) == 0 {
+			return err
+		}
+		rel, err := filepath.Rel(stubDir, path)
+		if err != nil {
+			return err
+		}
+		packages = append(packages, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to list stubs: %v", err)
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// pinnedModule returns the module of the catalog providing the package,
+// preferring the longest module path, e.g. go.uber.org/zap/exp over
+// go.uber.org/zap
+func pinnedModule(pkg string) string {
+	var module string
+	for m := range deps.Versions {
+		if (pkg == m || strings.HasPrefix(pkg, m+"/")) && len(m) > len(module) {
+			module = m
+		}
+	}
+	return module
+}
+
+// downloadModule returns the directory of the module version in the module
+// cache, downloading it if needed. Without a module proxy or cache the
+// signatures are not compared.
+func downloadModule(t *testing.T, module, version string) (string, bool) {
+	t.Helper()
+
+	out, err := goCommand(".", "mod", "download", "-json", module+"@"+version)
+	if err != nil {
+		t.Logf("Not comparing with %s@%s, module unavailable: %v", module, version, err)
+		return "", false
+	}
+	var info struct{ Dir string }
+	if err := json.Unmarshal([]byte(out), &info); err != nil || info.Dir == "" {
+		t.Logf("Not comparing with %s@%s, unexpected download output: %s", module, version, out)
+		return "", false
+	}
+	return info.Dir, true
+}
+
+// parsePackage parses the non-test Go files in dir
+func parsePackage(t *testing.T, dir string) []*ast.File {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatalf("Failed to list %s: %v", dir, err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range matches {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", name, err)
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		t.Fatalf("No Go files in %s", dir)
+	}
+	return files
+}
+
+// declarations returns the exported functions, methods, types, constants
+// and variables of a package with the signatures of the functions and
+// methods. Methods are named Type.Method, including those promoted from
+// embedded fields. A package may declare a name in several files, one per
+// platform, so each name maps to every signature.
+func declarations(files []*ast.File) map[string]map[string]bool {
+	decls := make(map[string]map[string]bool)
+	methods := make(map[string]map[string]map[string]bool)
+	embeds := make(map[string][]string)
+	add := func(name, sig string) {
+		if decls[name] == nil {
+			decls[name] = make(map[string]bool)
+		}
+		decls[name][sig] = true
+	}
+
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if !ast.IsExported(d.Name.Name) {
+					continue
+				}
+				if d.Recv == nil || len(d.Recv.List) == 0 {
+					add(d.Name.Name, signature(d.Type))
+					continue
+				}
+				recv := receiverName(d.Recv.List[0].Type)
+				if methods[recv] == nil {
+					methods[recv] = make(map[string]map[string]bool)
+				}
+				if methods[recv][d.Name.Name] == nil {
+					methods[recv][d.Name.Name] = make(map[string]bool)
+				}
+				methods[recv][d.Name.Name][signature(d.Type)] = true
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						if ast.IsExported(s.Name.Name) {
+							add(s.Name.Name, "")
+						}
+						if st, ok := s.Type.(*ast.StructType); ok {
+							for _, field := range st.Fields.List {
+								if len(field.Names) == 0 {
+									embeds[s.Name.Name] = append(embeds[s.Name.Name], receiverName(field.Type))
+								}
+							}
+						}
+					case *ast.ValueSpec:
+						for _, n := range s.Names {
+							if ast.IsExported(n.Name) {
+								add(n.Name, "")
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, typ := range keys(decls) {
+		for name, sigs := range methodSet(typ, methods, embeds, make(map[string]bool)) {
+			for sig := range sigs {
+				add(typ+"."+name, sig)
+			}
+		}
+	}
+	return decls
+}
+
+// methodSet returns the methods declared on typ and promoted from the types
+// it embeds, which shallower declarations override
+func methodSet(typ string, methods map[string]map[string]map[string]bool, embeds map[string][]string, seen map[string]bool) map[string]map[string]bool {
+	set := make(map[string]map[string]bool)
+	if seen[typ] {
+		return set
+	}
+	seen[typ] = true
+	for _, embedded := range embeds[typ] {
+		for name, sigs := range methodSet(embedded, methods, embeds, seen) {
+			set[name] = sigs
+		}
+	}
+	for name, sigs := range methods[typ] {
+		set[name] = sigs
+	}
+	return set
+}
+
+// receiverName returns the type name of a method receiver
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.IndexExpr:
+		return receiverName(e.X)
+	case *ast.IndexListExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// signature formats the parameter and result types of a function without
+// their names
+func signature(fn *ast.FuncType) string {
+	return "(" + fieldTypes(fn.Params) + ")" + " (" + fieldTypes(fn.Results) + ")"
+}
+
+func fieldTypes(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+	var list []string
+	for _, f := range fields.List {
+		typ := anyType.ReplaceAllString(types.ExprString(f.Type), "interface{}")
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			list = append(list, typ)
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// keys returns the keys of m in order
+func keys[V any](m map[string]V) []string {
+	list := make([]string, 0, len(m))
+	for k := range m {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,11 @@
+// Package templates embeds the templates projects are generated from, so the
+// scaffold tool works from any directory rather than only the repository
+// root
+package templates
+
+import "embed"
+
+// FS holds the templates by their path below tools/scaffold/templates
+//
+//go:embed all:*
+var FS embed.FS
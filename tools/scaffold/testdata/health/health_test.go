@@ -0,0 +1,208 @@
+// Package health is copied into generated projects by the scaffold's tests
+// to run the generated health checks
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counter counts the runs of a check that sleeps for delay and fails with
+// err
+type counter struct {
+	runs  atomic.Int32
+	delay time.Duration
+	err   error
+}
+
+func (c *counter) Check(ctx context.Context) error {
+	c.runs.Add(1)
+	time.Sleep(c.delay)
+	return c.err
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+
+	cached, uncached := &counter{}, &counter{}
+	r.Register("cached", cached, WithCacheTTL(5*time.Second))
+	r.Register("uncached", uncached, WithCacheTTL(0))
+
+	r.Check(context.Background())
+	now = now.Add(4 * time.Second)
+	r.Check(context.Background())
+	if got := cached.runs.Load(); got != 1 {
+		t.Errorf("Expected a fresh result to be reused, check ran %d times", got)
+	}
+	if got := uncached.runs.Load(); got != 2 {
+		t.Errorf("Expected a check without TTL to run on every probe, ran %d times", got)
+	}
+
+	now = now.Add(time.Second)
+	report := r.Check(context.Background())
+	if got := cached.runs.Load(); got != 2 {
+		t.Errorf("Expected an expired result to be checked again, check ran %d times", got)
+	}
+	if got := report.Checks["cached"].CheckedAt; !got.Equal(now) {
+		t.Errorf("CheckedAt = %s, want %s", got, now)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	r := NewRegistry()
+	// The check ignores its context, like a client call without one
+	r.Register("slow", &counter{delay: time.Second}, WithTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	report := r.Check(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the timeout to end the check, took %s", elapsed)
+	}
+
+	result := report.Checks["slow"]
+	if report.Status != StatusDown || result.Status != StatusDown || result.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("Expected the check to time out, got %+v", report)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		cacheErr   error
+		wantCode   int
+		wantStatus Status
+	}{
+		{"All up", nil, http.StatusOK, StatusUp},
+		{"One down", errors.New("connection refused"), http.StatusServiceUnavailable, StatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			r.Register("database", &counter{})
+			r.Register("redis", &counter{err: tt.cacheErr})
+
+			rec := httptest.NewRecorder()
+			r.ReadinessHandler()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+				t.Errorf("Cache-Control = %q", got)
+			}
+
+			var report Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("Body is not a JSON report: %v\n%s", err, rec.Body)
+			}
+			if report.Status != tt.wantStatus || report.Checks["database"].Status != StatusUp {
+				t.Errorf("Unexpected report %+v", report)
+			}
+			redis := report.Checks["redis"]
+			if tt.cacheErr != nil && (redis.Status != StatusDown || redis.Error != tt.cacheErr.Error()) {
+				t.Errorf("Expected the redis check to report its error, got %+v", redis)
+			}
+			if redis.Duration == "" || redis.CheckedAt.IsZero() {
+				t.Errorf("Expected the redis check to report when and how long it ran, got %+v", redis)
+			}
+		})
+	}
+}
+
+func TestLivenessIgnoresDependencies(t *testing.T) {
+	r := NewRegistry()
+	database := &counter{err: errors.New("connection refused")}
+	r.Register("database", database)
+
+	rec := httptest.NewRecorder()
+	r.LivenessHandler()(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil || report.Status != StatusUp || len(report.Checks) != 0 {
+		t.Errorf("Expected an up report without checks, got %s", rec.Body)
+	}
+	if got := database.runs.Load(); got != 0 {
+		t.Errorf("Expected liveness to run no checks, ran %d", got)
+	}
+}
+
+func TestConcurrentProbes(t *testing.T) {
+	r := NewRegistry()
+	database := &counter{delay: 200 * time.Millisecond}
+	redis := &counter{delay: 200 * time.Millisecond}
+	r.Register("database", database, WithCacheTTL(time.Minute))
+	r.Register("redis", redis, WithCacheTTL(time.Minute))
+
+	// Probes arriving together wait for one run of each check and share
+	// its result; the checks run side by side
+	start := time.Now()
+	var wg sync.WaitGroup
+	codes := make([]int, 20)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			r.ReadinessHandler()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			codes[i] = rec.Code
+		}(i)
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("Probe %d got status %d", i, code)
+		}
+	}
+	if database.runs.Load() != 1 || redis.runs.Load() != 1 {
+		t.Errorf("Expected each check to run once, ran database %d and redis %d times", database.runs.Load(), redis.runs.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 350*time.Millisecond {
+		t.Errorf("Expected checks to run concurrently, probes took %s", elapsed)
+	}
+}
+
+func TestRegisterReplaces(t *testing.T) {
+	r := NewRegistry()
+	r.Register("database", &counter{err: errors.New("down")})
+	r.Register("database", &counter{})
+
+	report := r.Check(context.Background())
+	if report.Status != StatusUp || len(report.Checks) != 1 {
+		t.Errorf("Expected the second check to replace the first, got %+v", report)
+	}
+}
+
+func TestHTTPChecker(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+
+	checker := HTTPChecker(nil, srv.URL)
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("Expected a 200 response to pass, got %v", err)
+	}
+	status.Store(http.StatusServiceUnavailable)
+	if err := checker.Check(context.Background()); err == nil || err.Error() != "unexpected status 503" {
+		t.Errorf("Expected a 503 response to fail, got %v", err)
+	}
+}
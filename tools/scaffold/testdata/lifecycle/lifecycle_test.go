@@ -0,0 +1,312 @@
+// Package lifecycle is copied into generated projects by the scaffold's
+// tests to run the generated lifecycle manager
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder records the hooks that ran, in order
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) hook(call string, err error) Hook {
+	return func(ctx context.Context) error {
+		r.mu.Lock()
+		r.calls = append(r.calls, call)
+		r.mu.Unlock()
+		return err
+	}
+}
+
+func (r *recorder) component(name string, startErr, stopErr error, dependsOn ...string) Component {
+	return Component{
+		Name:      name,
+		Start:     r.hook("start "+name, startErr),
+		Stop:      r.hook("stop "+name, stopErr),
+		DependsOn: dependsOn,
+	}
+}
+
+func (r *recorder) got() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func newManager() *Manager {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestDependencyOrder(t *testing.T) {
+	rec := &recorder{}
+	m := newManager()
+	m.Register(rec.component("http", nil, nil, "database", "redis"))
+	m.Register(rec.component("redis", nil, nil))
+	m.Register(rec.component("database", nil, nil))
+	m.Register(rec.component("worker", nil, nil, "redis"))
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	want := []string{
+		"start database", "start redis", "start http", "start worker",
+		"stop worker", "stop http", "stop redis", "stop database",
+	}
+	if got := rec.got(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hooks ran as %v, want %v", got, want)
+	}
+}
+
+func TestInvalidDependencies(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Component
+		want       string
+	}{
+		{
+			name:       "Cycle",
+			components: []Component{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"c"}}, {Name: "c", DependsOn: []string{"a"}}},
+			want:       "dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name:       "Unknown dependency",
+			components: []Component{{Name: "http", DependsOn: []string{"database"}}},
+			want:       "component http depends on unknown component database",
+		},
+		{
+			name:       "Registered twice",
+			components: []Component{{Name: "http"}, {Name: "http"}},
+			want:       "component http is registered twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := false
+			m := newManager()
+			for _, c := range tt.components {
+				c.Start = func(context.Context) error {
+					started = true
+					return nil
+				}
+				m.Register(c)
+			}
+
+			err := m.Start(context.Background())
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Start() error = %v, want %q", err, tt.want)
+			}
+			if started {
+				t.Error("Expected no component to start")
+			}
+		})
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	// block ignores its context, like a hook stuck on a call without one
+	block := func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name      string
+		component Component
+		run       func(m *Manager) error
+	}{
+		{
+			name:      "Start",
+			component: Component{Name: "slow", Start: block, StartTimeout: 20 * time.Millisecond},
+			run:       func(m *Manager) error { return m.Start(context.Background()) },
+		},
+		{
+			name:      "Stop",
+			component: Component{Name: "slow", Stop: block, StopTimeout: 20 * time.Millisecond},
+			run: func(m *Manager) error {
+				if err := m.Start(context.Background()); err != nil {
+					t.Fatalf("Start failed: %v", err)
+				}
+				return m.Stop(context.Background())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newManager()
+			m.Register(tt.component)
+
+			begin := time.Now()
+			err := tt.run(m)
+			if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "slow: timed out after 20ms") {
+				t.Errorf("Expected the hook to time out, got %v", err)
+			}
+			if elapsed := time.Since(begin); elapsed > 500*time.Millisecond {
+				t.Errorf("Expected the timeout to end the hook, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestPartialStart(t *testing.T) {
+	rec := &recorder{}
+	errStart := errors.New("port in use")
+	errStop := errors.New("close failed")
+
+	m := newManager()
+	m.Register(rec.component("database", nil, errStop))
+	m.Register(rec.component("redis", nil, nil, "database"))
+	m.Register(rec.component("http", errStart, nil, "redis"))
+	m.Register(rec.component("grpc", nil, nil, "http"))
+
+	err := m.Start(context.Background())
+	if !errors.Is(err, errStart) || !errors.Is(err, errStop) {
+		t.Errorf("Expected the start and stop errors together, got %v", err)
+	}
+
+	want := []string{"start database", "start redis", "start http", "stop redis", "stop database"}
+	if got := rec.got(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hooks ran as %v, want %v", got, want)
+	}
+}
+
+func TestFailShutsDown(t *testing.T) {
+	rec := &recorder{}
+	errServe := errors.New("listener closed")
+
+	m := newManager()
+	m.Register(rec.component("database", nil, nil))
+	m.Register(Component{
+		Name:      "http",
+		DependsOn: []string{"database"},
+		Start: func(context.Context) error {
+			go func() {
+				m.Fail(errServe)
+				// Only the first failure is kept
+				m.Fail(errors.New("second failure"))
+			}()
+			return nil
+		},
+		Stop: rec.hook("stop http", nil),
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- m.Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errServe) || strings.Contains(err.Error(), "second failure") {
+			t.Errorf("Run() error = %v, want the first failure", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Run to return after a component failed")
+	}
+
+	want := []string{"start database", "stop http", "stop database"}
+	if got := rec.got(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hooks ran as %v, want %v", got, want)
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	rec := &recorder{}
+	started := make(chan struct{})
+	m := newManager()
+	m.Register(rec.component("database", nil, nil))
+	m.Register(Component{
+		Name:      "http",
+		DependsOn: []string{"database"},
+		Start: func(context.Context) error {
+			close(started)
+			return nil
+		},
+		Stop: rec.hook("stop http", nil),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Run to return once cancelled")
+	}
+
+	want := []string{"start database", "stop http", "stop database"}
+	if got := rec.got(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hooks ran as %v, want %v", got, want)
+	}
+}
+
+func TestStartCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := newManager()
+	m.Register(Component{
+		Name: "slow",
+		Start: func(context.Context) error {
+			cancel()
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+
+	err := m.Start(ctx)
+	if !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a cancelled start, got %v", err)
+	}
+}
+
+func TestStopJoinsErrors(t *testing.T) {
+	rec := &recorder{}
+	errDB := errors.New("database close failed")
+	errRedis := errors.New("redis close failed")
+
+	m := newManager()
+	m.Register(rec.component("database", nil, errDB))
+	m.Register(rec.component("redis", nil, errRedis))
+	m.Register(rec.component("http", nil, nil, "database", "redis"))
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	err := m.Stop(context.Background())
+	if !errors.Is(err, errDB) || !errors.Is(err, errRedis) {
+		t.Errorf("Expected both stop errors, got %v", err)
+	}
+	for _, want := range []string{"failed to stop database", "failed to stop redis"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected the error to contain %q, got %v", want, err)
+		}
+	}
+
+	// Every component is stopped despite the errors, and only once
+	want := []string{"start database", "start redis", "start http", "stop http", "stop redis", "stop database"}
+	if err := m.Stop(context.Background()); err != nil {
+		t.Errorf("Second Stop() error = %v", err)
+	}
+	if got := rec.got(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hooks ran as %v, want %v", got, want)
+	}
+}
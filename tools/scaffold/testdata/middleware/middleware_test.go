@@ -0,0 +1,107 @@
+// Package handlers is copied into generated projects by the scaffold's build
+// tests to check the router applies middleware to every request
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/shop/pkg/security"
+)
+
+func TestRouterMiddleware(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	limit := security.DefaultRateLimitConfig
+	limit.RequestsPerMinute = 4
+
+	verify := func(ctx context.Context, token string) (context.Context, error) {
+		if token != "secret" {
+			return nil, errors.New("invalid token")
+		}
+		return ctx, nil
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("Expected the request context to have a deadline")
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	routes := []Route{
+		{Method: http.MethodGet, Path: "/ping", Handler: ok},
+		{Method: http.MethodGet, Path: "/private", Handler: Authenticate(verify)(http.HandlerFunc(ok)).ServeHTTP},
+	}
+	router := NewRouter(log, routes,
+		security.SecurityHeaders(security.DefaultSecurityHeadersConfig),
+		security.CORS(security.DefaultCORSOptions),
+		security.RateLimit(security.NewMemoryRateLimiter(limit), limit),
+		Timeout(time.Second),
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header map[string]string
+		status int
+		want   map[string]string
+	}{
+		{
+			name: "Security headers", method: http.MethodGet, path: "/ping",
+			status: http.StatusOK,
+			want:   map[string]string{"X-Content-Type-Options": "nosniff", "X-Frame-Options": "DENY"},
+		},
+		{
+			name: "CORS", method: http.MethodGet, path: "/ping",
+			header: map[string]string{"Origin": "https://shop.example"},
+			status: http.StatusOK,
+			want:   map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+		{
+			name: "Preflight of an unrouted method", method: http.MethodOptions, path: "/ping",
+			header: map[string]string{"Origin": "https://shop.example", "Access-Control-Request-Method": "DELETE"},
+			status: http.StatusNoContent,
+			want:   map[string]string{"Access-Control-Allow-Origin": "*", "Access-Control-Max-Age": "43200"},
+		},
+		{
+			name: "Missing token", method: http.MethodGet, path: "/private",
+			status: http.StatusUnauthorized,
+			want:   map[string]string{"WWW-Authenticate": "Bearer"},
+		},
+		{
+			name: "Valid token", method: http.MethodGet, path: "/private",
+			header: map[string]string{"Authorization": "Bearer secret"},
+			status: http.StatusOK,
+		},
+		{
+			name: "Rate limited", method: http.MethodGet, path: "/ping",
+			status: http.StatusTooManyRequests,
+			want:   map[string]string{"Retry-After": "300", "X-Content-Type-Options": "nosniff"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			for k, v := range tt.want {
+				if got := rec.Header().Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package handlers is copied into generated projects by the scaffold's build
+// tests, after importing petstore.yaml, to serve the imported routes through
+// the project's router
+package handlers
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/shop/internal/dto"
+)
+
+type fakePets struct {
+	listed dto.ListPetsParams
+}
+
+func (p *fakePets) ListPets(ctx context.Context, params dto.ListPetsParams) ([]dto.Pet, error) {
+	p.listed = params
+	return []dto.Pet{{ID: 1, Name: "rex"}}, nil
+}
+
+func (p *fakePets) CreatePet(ctx context.Context, body *dto.NewPet) (*dto.Pet, error) {
+	return &dto.Pet{ID: 2, Name: body.Name}, nil
+}
+
+func (p *fakePets) ShowPet(ctx context.Context, params dto.ShowPetParams) (*dto.Pet, error) {
+	return &dto.Pet{ID: params.PetID, Name: "rex"}, nil
+}
+
+func (p *fakePets) DeletePet(ctx context.Context, params dto.DeletePetParams) error {
+	return nil
+}
+
+type fakeStores struct{}
+
+func (fakeStores) GetInventory(ctx context.Context, params dto.GetInventoryParams) (map[string]int64, error) {
+	return map[string]int64{params.StoreID: 3}, nil
+}
+
+func TestAPIRoutes(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	pets := &fakePets{}
+	router := NewRouter(log, APIRoutes(APIServices{Pets: pets, Stores: fakeStores{}}, log))
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		want   string
+	}{
+		{http.MethodGet, "/pets?limit=5&tag=dog", "", http.StatusOK, `"name":"rex"`},
+		{http.MethodGet, "/pets?limit=0", "", http.StatusBadRequest, ""},
+		{http.MethodGet, "/pets?limit=many", "", http.StatusBadRequest, ""},
+		{http.MethodGet, "/pets/42", "", http.StatusOK, `"id":42`},
+		{http.MethodGet, "/pets/rex", "", http.StatusBadRequest, ""},
+		{http.MethodDelete, "/pets/42", "", http.StatusNoContent, ""},
+		{http.MethodPost, "/pets", `{"name":"tom"}`, http.StatusCreated, `"name":"tom"`},
+		{http.MethodPost, "/pets", `{}`, http.StatusBadRequest, ""},
+		{http.MethodPost, "/pets", `{"name":"tom","status":"lost"}`, http.StatusBadRequest, ""},
+		{http.MethodGet, "/stores/main/inventory", "", http.StatusOK, `"main":3`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("Status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("Expected the body to contain %s, got %s", tt.want, rec.Body)
+			}
+		})
+	}
+
+	if pets.listed.Limit == nil || *pets.listed.Limit != 5 || pets.listed.Tag == nil || *pets.listed.Tag != "dog" {
+		t.Errorf("Expected the query parameters bound, got %+v", pets.listed)
+	}
+}
@@ -0,0 +1,71 @@
+// Package config_test is copied into generated projects by the scaffold's
+// build tests to check the service starts from the configuration it loads
+package config_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"example.com/shop/internal/config"
+	"example.com/shop/pkg/logger"
+)
+
+func TestLoadConfigStartsLogger(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		env    string
+		want   string
+		enable slog.Level
+	}{
+		{name: "Defaults", want: "info", enable: slog.LevelInfo},
+		{name: "Config file", file: "log_level: debug\n", want: "debug", enable: slog.LevelDebug},
+		{name: "Environment", file: "log_level: debug\n", env: "warn", want: "warn", enable: slog.LevelWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.file != "" {
+				if err := os.WriteFile(dir+"/config.yaml", []byte(tt.file), 0600); err != nil {
+					t.Fatalf("Failed to write config.yaml: %v", err)
+				}
+			}
+			if tt.env != "" {
+				t.Setenv("APP_LOG_LEVEL", tt.env)
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatalf("Failed to get working directory: %v", err)
+			}
+			if err := os.Chdir(dir); err != nil {
+				t.Fatalf("Failed to change directory: %v", err)
+			}
+			defer os.Chdir(wd) //nolint:errcheck
+
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.LogLevel != tt.want {
+				t.Errorf("LogLevel = %q, want %q", cfg.LogLevel, tt.want)
+			}
+
+			log, err := logger.New(cfg.LogLevel)
+			if err != nil {
+				t.Fatalf("logger.New(%q) error = %v", cfg.LogLevel, err)
+			}
+			// Standard levels are 4 apart, and debug is the lowest zap has
+			ctx := context.Background()
+			if !log.Enabled(ctx, tt.enable) || (tt.enable > slog.LevelDebug && log.Enabled(ctx, tt.enable-4)) {
+				t.Errorf("Expected the logger to log from %s", tt.enable)
+			}
+		})
+	}
+
+	if _, err := logger.New(""); err != nil {
+		t.Errorf("logger.New(\"\") error = %v, want info", err)
+	}
+}
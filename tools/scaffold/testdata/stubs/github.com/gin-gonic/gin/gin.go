@@ -0,0 +1,78 @@
+// Package gin is a type-checking stub of github.com/gin-gonic/gin at v1.10.0
+package gin
+
+import "net/http"
+
+type HandlerFunc func(*Context)
+
+type Param struct {
+	Key   string
+	Value string
+}
+
+type Params []Param
+
+type ResponseWriter interface {
+	http.ResponseWriter
+	Status() int
+	Size() int
+	Written() bool
+}
+
+type Context struct {
+	Request *http.Request
+	Writer  ResponseWriter
+	Params  Params
+}
+
+func (c *Context) Next()                                 {}
+func (c *Context) Abort()                                {}
+func (c *Context) ClientIP() string                      { return "" }
+func (c *Context) Header(key, value string)              {}
+func (c *Context) Param(key string) string               { return "" }
+func (c *Context) JSON(code int, obj interface{})        {}
+func (c *Context) AbortWithStatus(code int)              {}
+func (c *Context) Set(key string, value interface{})     {}
+func (c *Context) Get(key string) (interface{}, bool)    { return nil, false }
+func (c *Context) GetHeader(key string) string           { return "" }
+func (c *Context) AbortWithStatusJSON(code int, obj any) {}
+func (c *Context) Status(code int)                       {}
+func (c *Context) GetQuery(key string) (string, bool)    { return "", false }
+func (c *Context) ShouldBindJSON(obj any) error          { return nil }
+func (c *Context) ShouldBindQuery(obj any) error         { return nil }
+func (c *Context) ShouldBindUri(obj any) error           { return nil }
+
+type IRoutes interface {
+	Use(...HandlerFunc) IRoutes
+	Handle(string, string, ...HandlerFunc) IRoutes
+}
+
+type IRouter interface {
+	IRoutes
+	Group(string, ...HandlerFunc) *RouterGroup
+}
+
+type RouterGroup struct{}
+
+func (g *RouterGroup) Use(middleware ...HandlerFunc) IRoutes                       { return g }
+func (g *RouterGroup) Handle(method, path string, handlers ...HandlerFunc) IRoutes { return g }
+func (g *RouterGroup) Group(path string, handlers ...HandlerFunc) *RouterGroup     { return g }
+func (g *RouterGroup) GET(path string, handlers ...HandlerFunc) IRoutes            { return g }
+func (g *RouterGroup) POST(path string, handlers ...HandlerFunc) IRoutes           { return g }
+func (g *RouterGroup) PUT(path string, handlers ...HandlerFunc) IRoutes            { return g }
+func (g *RouterGroup) PATCH(path string, handlers ...HandlerFunc) IRoutes          { return g }
+func (g *RouterGroup) DELETE(path string, handlers ...HandlerFunc) IRoutes         { return g }
+
+type Engine struct {
+	RouterGroup
+}
+
+type OptionFunc func(*Engine)
+
+func New(opts ...OptionFunc) *Engine                               { return &Engine{} }
+func Default(opts ...OptionFunc) *Engine                           { return &Engine{} }
+func (e *Engine) Use(middleware ...HandlerFunc) IRoutes            { return e }
+func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+func (e *Engine) Run(addr ...string) error                         { return nil }
+
+func Recovery() HandlerFunc { return nil }
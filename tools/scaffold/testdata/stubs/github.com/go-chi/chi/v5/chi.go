@@ -0,0 +1,34 @@
+// Package chi is a type-checking stub of github.com/go-chi/chi/v5 at v5.1.0
+package chi
+
+import (
+	"context"
+	"net/http"
+)
+
+type Middlewares []func(http.Handler) http.Handler
+
+type Mux struct{}
+
+func NewRouter() *Mux { return &Mux{} }
+
+func (mx *Mux) Use(middlewares ...func(http.Handler) http.Handler)    {}
+func (mx *Mux) Method(method, pattern string, handler http.Handler)   {}
+func (mx *Mux) MethodFunc(method, pattern string, h http.HandlerFunc) {}
+func (mx *Mux) Handle(pattern string, handler http.Handler)           {}
+func (mx *Mux) Mount(pattern string, handler http.Handler)            {}
+func (mx *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request)      {}
+
+type RouteParams struct {
+	Keys, Values []string
+}
+
+type Context struct {
+	URLParams RouteParams
+}
+
+func (x *Context) URLParam(key string) string { return "" }
+
+func RouteContext(ctx context.Context) *Context { return nil }
+
+func URLParam(r *http.Request, key string) string { return "" }
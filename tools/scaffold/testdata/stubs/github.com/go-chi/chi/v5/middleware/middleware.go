@@ -0,0 +1,18 @@
+// Package middleware is a type-checking stub of
+// github.com/go-chi/chi/v5/middleware at v5.1.0
+package middleware
+
+import "net/http"
+
+type WrapResponseWriter interface {
+	http.ResponseWriter
+	Status() int
+	BytesWritten() int
+	Unwrap() http.ResponseWriter
+}
+
+func NewWrapResponseWriter(w http.ResponseWriter, protoMajor int) WrapResponseWriter { return nil }
+
+func Recoverer(next http.Handler) http.Handler { return next }
+
+func RequestID(next http.Handler) http.Handler { return next }
@@ -0,0 +1,24 @@
+// Package validator is a type-checking stub of
+// github.com/go-playground/validator/v10 at v10.22.0
+package validator
+
+type Validate struct{}
+
+type Option func(*Validate)
+
+func New(options ...Option) *Validate { return &Validate{} }
+
+func (v *Validate) SetTagName(name string)                  {}
+func (v *Validate) Struct(s interface{}) error              { return nil }
+func (v *Validate) Var(field interface{}, tag string) error { return nil }
+
+type FieldError interface {
+	Field() string
+	Tag() string
+	Param() string
+	Error() string
+}
+
+type ValidationErrors []FieldError
+
+func (ve ValidationErrors) Error() string { return "" }
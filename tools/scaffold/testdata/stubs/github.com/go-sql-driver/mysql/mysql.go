@@ -0,0 +1,3 @@
+// Package mysql is a type-checking stub of github.com/go-sql-driver/mysql at
+// v1.8.1. Projects only import it for its driver registration.
+package mysql
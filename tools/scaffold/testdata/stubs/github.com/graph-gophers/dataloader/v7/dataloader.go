@@ -0,0 +1,26 @@
+// Package dataloader is a type-checking stub of
+// github.com/graph-gophers/dataloader/v7 at v7.1.0
+package dataloader
+
+import "context"
+
+type Result[V any] struct {
+	Data  V
+	Error error
+}
+
+type BatchFunc[K comparable, V any] func(context.Context, []K) []*Result[V]
+
+type Thunk[V any] func() (V, error)
+
+type Option[K comparable, V any] func(*Loader[K, V])
+
+type Loader[K comparable, V any] struct {
+	batchFn BatchFunc[K, V]
+}
+
+func NewBatchedLoader[K comparable, V any](batchFn BatchFunc[K, V], opts ...Option[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{batchFn: batchFn}
+}
+
+func (l *Loader[K, V]) Load(ctx context.Context, key K) Thunk[V] { return nil }
@@ -0,0 +1,36 @@
+// Package sqlx is a type-checking stub of github.com/jmoiron/sqlx at v1.4.0
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+type DB struct {
+	*sql.DB
+}
+
+type Row struct{}
+
+func (r *Row) Scan(dest ...interface{}) error    { return nil }
+func (r *Row) StructScan(dest interface{}) error { return nil }
+
+func Connect(driverName, dataSourceName string) (*DB, error) { return nil, nil }
+
+func Open(driverName, dataSourceName string) (*DB, error) { return nil, nil }
+
+func In(query string, args ...interface{}) (string, []interface{}, error) { return query, args, nil }
+
+func (db *DB) Rebind(query string) string { return query }
+
+func (db *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+func (db *DB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+func (db *DB) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *Row {
+	return nil
+}
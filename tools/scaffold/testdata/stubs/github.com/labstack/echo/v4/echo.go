@@ -0,0 +1,51 @@
+// Package echo is a type-checking stub of github.com/labstack/echo/v4 at
+// v4.12.0
+package echo
+
+import "net/http"
+
+type HandlerFunc func(c Context) error
+
+type MiddlewareFunc func(next HandlerFunc) HandlerFunc
+
+type Response struct {
+	Writer http.ResponseWriter
+	Status int
+	Size   int64
+}
+
+func (r *Response) Header() http.Header         { return nil }
+func (r *Response) WriteHeader(code int)        {}
+func (r *Response) Write(b []byte) (int, error) { return len(b), nil }
+
+type Context interface {
+	Request() *http.Request
+	SetRequest(r *http.Request)
+	Response() *Response
+	RealIP() string
+	Param(name string) string
+	ParamNames() []string
+	ParamValues() []string
+	JSON(code int, i interface{}) error
+	Error(err error)
+}
+
+type Route struct {
+	Method string
+	Path   string
+	Name   string
+}
+
+type Echo struct {
+	HideBanner bool
+	HidePort   bool
+}
+
+func New() *Echo { return &Echo{} }
+
+func (e *Echo) Use(middleware ...MiddlewareFunc)                                         {}
+func (e *Echo) Add(method, path string, handler HandlerFunc, m ...MiddlewareFunc) *Route { return nil }
+func (e *Echo) ServeHTTP(w http.ResponseWriter, r *http.Request)                         {}
+func (e *Echo) Start(address string) error                                               { return nil }
+
+func WrapMiddleware(m func(http.Handler) http.Handler) MiddlewareFunc { return nil }
@@ -0,0 +1,9 @@
+// Package middleware is a type-checking stub of
+// github.com/labstack/echo/v4/middleware at v4.12.0
+package middleware
+
+import "github.com/labstack/echo/v4"
+
+func Recover() echo.MiddlewareFunc { return nil }
+
+func Logger() echo.MiddlewareFunc { return nil }
@@ -0,0 +1,3 @@
+// Package pq is a type-checking stub of github.com/lib/pq at v1.10.9.
+// Projects only import it for its driver registration.
+package pq
@@ -0,0 +1,3 @@
+// Package sqlite3 is a type-checking stub of github.com/mattn/go-sqlite3 at
+// v1.14.22. Projects only import it for its driver registration.
+package sqlite3
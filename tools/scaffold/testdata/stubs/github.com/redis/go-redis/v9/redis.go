@@ -0,0 +1,45 @@
+// Package redis is a type-checking stub of github.com/redis/go-redis/v9 at
+// v9.5.1
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+type Options struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+type Client struct{}
+
+func NewClient(opt *Options) *Client { return &Client{} }
+
+func (c *Client) Ping(ctx context.Context) *StatusCmd { return &StatusCmd{} }
+func (c *Client) Close() error                        { return nil }
+func (c *Client) Exists(ctx context.Context, keys ...string) *IntCmd {
+	return &IntCmd{}
+}
+func (c *Client) Incr(ctx context.Context, key string) *IntCmd { return &IntCmd{} }
+func (c *Client) Expire(ctx context.Context, key string, expiration time.Duration) *BoolCmd {
+	return &BoolCmd{}
+}
+func (c *Client) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *StatusCmd {
+	return &StatusCmd{}
+}
+func (c *Client) Del(ctx context.Context, keys ...string) *IntCmd { return &IntCmd{} }
+
+type StatusCmd struct{}
+
+func (cmd *StatusCmd) Err() error { return nil }
+
+type IntCmd struct{}
+
+func (cmd *IntCmd) Err() error             { return nil }
+func (cmd *IntCmd) Result() (int64, error) { return 0, nil }
+
+type BoolCmd struct{}
+
+func (cmd *BoolCmd) Err() error { return nil }
@@ -0,0 +1,38 @@
+// Package zerolog is a type-checking stub of github.com/rs/zerolog at
+// v1.33.0
+package zerolog
+
+import (
+	"io"
+	"time"
+)
+
+type Level int8
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+var TimestampFieldName = "time"
+
+type Logger struct{}
+
+func New(w io.Writer) Logger { return Logger{} }
+
+func (l *Logger) WithLevel(level Level) *Event { return nil }
+
+type Event struct{}
+
+func (e *Event) Msg(msg string)                             {}
+func (e *Event) Str(key, val string) *Event                 { return e }
+func (e *Event) Int64(key string, i int64) *Event           { return e }
+func (e *Event) Uint64(key string, i uint64) *Event         { return e }
+func (e *Event) Float64(key string, f float64) *Event       { return e }
+func (e *Event) Bool(key string, b bool) *Event             { return e }
+func (e *Event) Dur(key string, d time.Duration) *Event     { return e }
+func (e *Event) Time(key string, t time.Time) *Event        { return e }
+func (e *Event) AnErr(key string, err error) *Event         { return e }
+func (e *Event) Interface(key string, i interface{}) *Event { return e }
@@ -0,0 +1,27 @@
+// Package viper is a type-checking stub of github.com/spf13/viper at v1.18.2
+package viper
+
+type ConfigFileNotFoundError struct {
+	name, locations string
+}
+
+func (e ConfigFileNotFoundError) Error() string { return "" }
+
+// DecoderConfigOption configures a mapstructure.DecoderConfig, which the stub
+// leaves out
+type DecoderConfigOption func(any)
+
+type Viper struct{}
+
+func New() *Viper { return &Viper{} }
+
+func (v *Viper) AddConfigPath(in string)                                         {}
+func (v *Viper) AutomaticEnv()                                                   {}
+func (v *Viper) SetConfigName(in string)                                         {}
+func (v *Viper) SetConfigType(in string)                                         {}
+func (v *Viper) SetEnvPrefix(in string)                                          {}
+func (v *Viper) SetDefault(key string, value interface{})                        {}
+func (v *Viper) Set(key string, value interface{})                               {}
+func (v *Viper) ReadInConfig() error                                             { return nil }
+func (v *Viper) Unmarshal(rawVal interface{}, opts ...DecoderConfigOption) error { return nil }
+func (v *Viper) GetString(key string) string                                     { return "" }
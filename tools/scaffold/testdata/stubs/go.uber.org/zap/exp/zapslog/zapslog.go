@@ -0,0 +1,31 @@
+// Package zapslog is a type-checking stub of go.uber.org/zap/exp/zapslog at
+// v0.3.0
+package zapslog
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type HandlerOption interface {
+	apply(*Handler)
+}
+
+type optionFunc func(*Handler)
+
+func (f optionFunc) apply(h *Handler) { f(h) }
+
+func WithCaller(enabled bool) HandlerOption { return optionFunc(func(*Handler) {}) }
+
+type Handler struct {
+	core zapcore.Core
+}
+
+func NewHandler(core zapcore.Core, opts ...HandlerOption) *Handler { return &Handler{core: core} }
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool   { return true }
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error { return nil }
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler             { return h }
+func (h *Handler) WithGroup(name string) slog.Handler                   { return h }
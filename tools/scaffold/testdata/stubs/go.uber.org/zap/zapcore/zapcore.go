@@ -0,0 +1,34 @@
+// Package zapcore is a type-checking stub of go.uber.org/zap/zapcore at
+// v1.27.0
+package zapcore
+
+import "time"
+
+type Level int8
+
+const (
+	DebugLevel Level = iota - 1
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+type Core interface {
+	Enabled(Level) bool
+	Sync() error
+}
+
+type PrimitiveArrayEncoder interface {
+	AppendString(string)
+}
+
+type TimeEncoder func(time.Time, PrimitiveArrayEncoder)
+
+func ISO8601TimeEncoder(t time.Time, enc PrimitiveArrayEncoder) {}
+
+type EncoderConfig struct {
+	MessageKey string
+	LevelKey   string
+	TimeKey    string
+	EncodeTime TimeEncoder
+}
@@ -0,0 +1,27 @@
+// Package codes is a type-checking stub of google.golang.org/grpc/codes at
+// v1.64.0
+package codes
+
+type Code uint32
+
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+func (c Code) String() string { return "" }
@@ -0,0 +1,46 @@
+// Package grpc is a type-checking stub of google.golang.org/grpc at v1.64.0
+package grpc
+
+import (
+	"context"
+	"net"
+)
+
+type ServiceDesc struct {
+	ServiceName string
+	HandlerType interface{}
+}
+
+type ServiceRegistrar interface {
+	RegisterService(desc *ServiceDesc, impl interface{})
+}
+
+type ServerOption interface {
+	apply()
+}
+
+type UnaryServerInfo struct {
+	Server     interface{}
+	FullMethod string
+}
+
+type UnaryHandler func(ctx context.Context, req interface{}) (interface{}, error)
+
+type UnaryServerInterceptor func(ctx context.Context, req interface{}, info *UnaryServerInfo, handler UnaryHandler) (resp interface{}, err error)
+
+type serverOption func()
+
+func (f serverOption) apply() { f() }
+
+func ChainUnaryInterceptor(interceptors ...UnaryServerInterceptor) ServerOption {
+	return serverOption(func() {})
+}
+
+type Server struct{}
+
+func NewServer(opt ...ServerOption) *Server { return &Server{} }
+
+func (s *Server) RegisterService(sd *ServiceDesc, ss interface{}) {}
+func (s *Server) Serve(lis net.Listener) error                    { return nil }
+func (s *Server) GracefulStop()                                   {}
+func (s *Server) Stop()                                           {}
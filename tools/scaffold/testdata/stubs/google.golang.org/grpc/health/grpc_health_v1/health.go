@@ -0,0 +1,18 @@
+// Package grpc_health_v1 is a type-checking stub of
+// google.golang.org/grpc/health/grpc_health_v1 at v1.64.0
+package grpc_health_v1
+
+import "google.golang.org/grpc"
+
+type HealthCheckResponse_ServingStatus int32
+
+const (
+	HealthCheckResponse_UNKNOWN         HealthCheckResponse_ServingStatus = 0
+	HealthCheckResponse_SERVING         HealthCheckResponse_ServingStatus = 1
+	HealthCheckResponse_NOT_SERVING     HealthCheckResponse_ServingStatus = 2
+	HealthCheckResponse_SERVICE_UNKNOWN HealthCheckResponse_ServingStatus = 3
+)
+
+type HealthServer interface{}
+
+func RegisterHealthServer(s grpc.ServiceRegistrar, srv HealthServer) {}
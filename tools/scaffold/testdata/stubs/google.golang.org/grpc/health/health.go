@@ -0,0 +1,14 @@
+// Package health is a type-checking stub of google.golang.org/grpc/health at
+// v1.64.0
+package health
+
+import healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+type Server struct{}
+
+func NewServer() *Server { return &Server{} }
+
+func (s *Server) SetServingStatus(service string, servingStatus healthpb.HealthCheckResponse_ServingStatus) {
+}
+func (s *Server) Shutdown() {}
+func (s *Server) Resume()   {}
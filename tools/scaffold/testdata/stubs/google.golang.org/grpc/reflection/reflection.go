@@ -0,0 +1,11 @@
+// Package reflection is a type-checking stub of
+// google.golang.org/grpc/reflection at v1.64.0
+package reflection
+
+import "google.golang.org/grpc"
+
+type GRPCServer interface {
+	grpc.ServiceRegistrar
+}
+
+func Register(s GRPCServer) {}
@@ -0,0 +1,11 @@
+// Package status is a type-checking stub of google.golang.org/grpc/status at
+// v1.64.0
+package status
+
+import "google.golang.org/grpc/codes"
+
+func Error(c codes.Code, msg string) error { return nil }
+
+func Errorf(c codes.Code, format string, a ...interface{}) error { return nil }
+
+func Code(err error) codes.Code { return codes.OK }
@@ -0,0 +1,17 @@
+// Package timestamppb is a type-checking stub of
+// google.golang.org/protobuf/types/known/timestamppb at v1.34.2
+package timestamppb
+
+import "time"
+
+type Timestamp struct {
+	Seconds int64
+	Nanos   int32
+}
+
+func New(t time.Time) *Timestamp { return &Timestamp{} }
+
+func Now() *Timestamp { return &Timestamp{} }
+
+func (x *Timestamp) AsTime() time.Time { return time.Time{} }
+func (x *Timestamp) IsValid() bool     { return true }
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jwill9999/scaffold-go/pkg/logger"
+	"github.com/jwill9999/scaffold-go/tools/scaffold/generator"
+	"golang.org/x/mod/modfile"
+)
+
+// stubDir holds minimal source stubs of the third-party packages generated
+// projects import, laid out by import path. It is relative to the
+// repository root, where generation tests run.
+const stubDir = "tools/scaffold/testdata/stubs"
+
+// generatedCodeDir is where protoc writes Go code in a gRPC project. That
+// code does not exist until the user runs buf, so files importing it are
+// not checked.
+const generatedCodeDir = "gen/"
+
+var (
+	// stdImporter loads standard library export data through the go command
+	stdImporter = importer.Default()
+
+	// stubPackages caches type-checked stubs across projects
+	stubPackages   = make(map[string]*types.Package)
+	stubPackagesMu sync.Mutex
+)
+
+// projectImporter resolves the imports of a generated project: packages of
+// the project are type-checked from its source, third-party packages from
+// the stubs and everything else from the standard library
+type projectImporter struct {
+	fset     *token.FileSet
+	dir      string
+	module   string
+	packages map[string]*types.Package
+	failed   map[string]error
+	errors   []string
+	// thirdParty records the third-party packages the project imports
+	thirdParty map[string]bool
+}
+
+// typeCheckProject parses and type-checks every Go package of the generated
+// project in dir and fails the test with every type error found
+func typeCheckProject(t *testing.T, dir, module string) {
+	t.Helper()
+
+	imp := &projectImporter{
+		fset:       token.NewFileSet(),
+		dir:        dir,
+		module:     module,
+		packages:   make(map[string]*types.Package),
+		failed:     make(map[string]error),
+		thirdParty: make(map[string]bool),
+	}
+
+	var packages []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.go"))
+		if err != nil || len(matches) == 0 {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		packages = append(packages, imp.importPath(filepath.ToSlash(rel)))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to list packages of %s: %v", dir, err)
+	}
+	if len(packages) == 0 {
+		t.Fatalf("No Go packages generated in %s", dir)
+	}
+
+	sort.Strings(packages)
+	for _, path := range packages {
+		if _, err := imp.Import(path); err != nil {
+			imp.errors = append(imp.errors, err.Error())
+		}
+	}
+
+	if len(imp.errors) > 0 {
+		t.Errorf("Generated project does not type-check:\n%s", strings.Join(imp.errors, "\n"))
+	}
+
+	// Every third-party package must come from a module go.mod requires
+	required := requiredModules(t, dir)
+	for path := range imp.thirdParty {
+		if !providedBy(required, path) {
+			t.Errorf("go.mod does not require a module providing %s", path)
+		}
+	}
+}
+
+// requiredModules returns the module paths required by the project's go.mod
+func requiredModules(t *testing.T, dir string) []string {
+	t.Helper()
+
+	// #nosec G304 - dir is the test's generated project
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatalf("Failed to read go.mod: %v", err)
+	}
+
+	f, err := modfile.Parse("go.mod", content, nil)
+	if err != nil {
+		t.Fatalf("Failed to parse go.mod: %v", err)
+	}
+
+	modules := make([]string, 0, len(f.Require))
+	for _, r := range f.Require {
+		modules = append(modules, r.Mod.Path)
+	}
+	return modules
+}
+
+// providedBy reports whether one of modules contains the package path
+func providedBy(modules []string, pkg string) bool {
+	for _, m := range modules {
+		if pkg == m || strings.HasPrefix(pkg, m+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// importPath returns the import path of the project directory rel
+func (imp *projectImporter) importPath(rel string) string {
+	if rel == "." {
+		return imp.module
+	}
+	return imp.module + "/" + rel
+}
+
+func (imp *projectImporter) Import(path string) (*types.Package, error) {
+	if err, ok := imp.failed[path]; ok {
+		return nil, err
+	}
+	if pkg, ok := imp.packages[path]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", path)
+		}
+		return pkg, nil
+	}
+
+	if path == imp.module || strings.HasPrefix(path, imp.module+"/") {
+		rel := strings.TrimPrefix(strings.TrimPrefix(path, imp.module), "/")
+		imp.packages[path] = nil
+		pkg, err := imp.check(path, filepath.Join(imp.dir, filepath.FromSlash(rel)))
+		if err != nil {
+			delete(imp.packages, path)
+			imp.failed[path] = err
+			return nil, err
+		}
+		imp.packages[path] = pkg
+		return pkg, nil
+	}
+
+	first := strings.SplitN(path, "/", 2)[0]
+	if !strings.Contains(first, ".") {
+		return stdImporter.Import(path)
+	}
+	imp.thirdParty[path] = true
+	return imp.importStub(path)
+}
+
+// importStub type-checks the stub of a third-party package
+func (imp *projectImporter) importStub(path string) (*types.Package, error) {
+	stubPackagesMu.Lock()
+	defer stubPackagesMu.Unlock()
+
+	if pkg, ok := stubPackages[path]; ok {
+		return pkg, nil
+	}
+
+	dir := filepath.Join(stubDir, filepath.FromSlash(path))
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("no stub for %s in %s", path, stubDir)
+	}
+
+	// Stubs only import the standard library and other stubs
+	stubs := &stubImporter{fset: imp.fset}
+	pkg, err := stubs.check(path, dir)
+	if err != nil {
+		return nil, err
+	}
+	stubPackages[path] = pkg
+	return pkg, nil
+}
+
+// check parses and type-checks the package in dir. Project packages record
+// their errors and are returned even when incomplete so dependent packages
+// are still checked.
+func (imp *projectImporter) check(path, dir string) (*types.Package, error) {
+	files, err := parseDir(imp.fset, dir, imp.module+"/"+generatedCodeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := types.Config{
+		Importer: imp,
+		Error: func(err error) {
+			imp.errors = append(imp.errors, err.Error())
+		},
+	}
+	pkg, _ := conf.Check(path, imp.fset, files, nil)
+	return pkg, nil
+}
+
+// stubImporter type-checks stubs, which may import each other
+type stubImporter struct {
+	fset *token.FileSet
+}
+
+func (s *stubImporter) Import(path string) (*types.Package, error) {
+	first := strings.SplitN(path, "/", 2)[0]
+	if !strings.Contains(first, ".") {
+		return stdImporter.Import(path)
+	}
+
+	if pkg, ok := stubPackages[path]; ok {
+		return pkg, nil
+	}
+	pkg, err := s.check(path, filepath.Join(stubDir, filepath.FromSlash(path)))
+	if err != nil {
+		return nil, err
+	}
+	stubPackages[path] = pkg
+	return pkg, nil
+}
+
+func (s *stubImporter) check(path, dir string) (*types.Package, error) {
+	files, err := parseDir(s.fset, dir, "")
+	if err != nil {
+		return nil, err
+	}
+
+	conf := types.Config{Importer: s}
+	pkg, err := conf.Check(path, s.fset, files, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stub %s: %w", path, err)
+	}
+	return pkg, nil
+}
+
+// parseDir parses the non-test Go files in dir, leaving out files that
+// import a package under skipImports
+func parseDir(fset *token.FileSet, dir, skipImports string) ([]*ast.File, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, name := range matches {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if skipImports != "" && importsUnder(file, skipImports) {
+			continue
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return files, nil
+}
+
+// importsUnder reports whether file imports a package under prefix
+func importsUnder(file *ast.File, prefix string) bool {
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err == nil && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGeneratedProjectsTypeCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping type-check of generated projects in short mode")
+	}
+	chdirRepoRoot(t)
+
+	tests := []struct {
+		name       string
+		router     string
+		logger     string
+		database   string
+		transports []string
+		features   []string
+		// spec is an OpenAPI document imported into the project
+		spec string
+	}{
+		{"gin with slog", generator.RouterGin, generator.LoggerSlog, "postgres", []string{generator.TransportHTTP}, nil, ""},
+		{"stdlib on mysql", generator.RouterStdlib, generator.LoggerSlog, "mysql", []string{generator.TransportHTTP}, nil, ""},
+		{"chi with zap and graphql", generator.RouterChi, generator.LoggerZap, "postgres", []string{generator.TransportHTTP, generator.TransportGraphQL}, nil, ""},
+		{"echo with zerolog and grpc on sqlite", generator.RouterEcho, generator.LoggerZerolog, "sqlite", []string{generator.TransportHTTP, generator.TransportGRPC}, []string{"cache"}, ""},
+		{"every transport", generator.RouterGin, generator.LoggerSlog, "postgres", []string{generator.TransportHTTP, generator.TransportGRPC, generator.TransportGraphQL}, []string{"cache"}, ""},
+		{"every feature", generator.RouterGin, generator.LoggerZap, "postgres", []string{generator.TransportHTTP, generator.TransportGRPC}, []string{"auth", "cache", "metrics", "tracing"}, ""},
+		{"imported openapi spec on chi", generator.RouterChi, generator.LoggerSlog, "mysql", []string{generator.TransportHTTP}, []string{"auth", "metrics"}, "tools/scaffold/testdata/openapi/petstore.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			module := "example.com/shop"
+			project := t.TempDir()
+			scaffold := &ProjectScaffold{
+				Name:       project,
+				Module:     module,
+				Features:   tt.features,
+				Transports: tt.transports,
+				Router:     tt.router,
+				Logger:     tt.logger,
+				Config: ProjectConfig{
+					Database:   DatabaseConfig{Type: tt.database},
+					Migrations: generator.DefaultMigrationSettings(),
+				},
+			}
+			if err := scaffold.generateBaseFiles(); err != nil {
+				t.Fatalf("Failed to generate project: %v", err)
+			}
+
+			now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			for i, res := range [][2]string{
+				{"User", "name:string:required email:string:required,email active:bool"},
+				{"BlogPost", "title:string:required author_id:ref:required,ref=users views:int rating:float meta:json published_at:time status:enum:oneof=draft|live,default=draft"},
+			} {
+				var out bytes.Buffer
+				args := []string{"-project", project, "-name", res[0], "-fields", res[1]}
+				if err := runResourceCommand(args, strings.NewReader(""), logger.New(&out), newReporter("test"), now.Add(time.Duration(i)*time.Second)); err != nil {
+					t.Fatalf("Failed to generate %s: %v\n%s", res[0], err, out.String())
+				}
+			}
+
+			if tt.spec != "" {
+				var out bytes.Buffer
+				args := []string{"openapi", "-project", project, "-module", module, tt.spec}
+				if err := runImportCommand(args, logger.New(&out), newReporter("test")); err != nil {
+					t.Fatalf("Failed to import %s: %v\n%s", tt.spec, err, out.String())
+				}
+			}
+
+			typeCheckProject(t, project, module)
+		})
+	}
+}